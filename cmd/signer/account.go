@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/spf13/cobra"
+)
+
+func newKeygenCommand() *cobra.Command {
+	var keystorePath string
+	var passwordFile string
+
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Generate a new random signing key",
+		Long: "Generate a new random signing key. If a keystore path is given, the key is" +
+			" written to an encrypted keystore and only its public key is shown. Otherwise" +
+			" the private key is printed.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			privKey, publicKey, _, err := curve.GetRandomKeys()
+			if err != nil {
+				return fmt.Errorf("cannot generate a new key: %w", err)
+			}
+			if keystorePath != "" {
+				return writeKeystore(cmd, keystorePath, privKey, passwordFile)
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Private key: 0x%s\n", privKey.Text(16))  //nolint:mnd // Hex base
+			fmt.Fprintf(out, "Public key: 0x%s\n", publicKey.Text(16)) //nolint:mnd // Hex base
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(
+		&keystorePath,
+		"keystore",
+		"",
+		"Path to an encrypted keystore to write the key to, instead of printing it",
+	)
+	addNewPasswordFileFlag(cmd, &passwordFile)
+
+	return cmd
+}
+
+func newAddressCommand() *cobra.Command {
+	var publicKeyHex string
+	var keystorePath string
+	var passwordFile string
+	var accountType string
+	var classHashHex string
+	var saltHex string
+
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "address",
+		Short: "Compute the address of the account owned by a signing key",
+		Long: "Compute the counterfactual address of the account owned by a signing key, that" +
+			" is the address it gets once deployed. The public key is either given or read" +
+			" from a keystore. The account is deployed from the default class of the account" +
+			" type unless a class hash is given.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			publicKey, err := readPublicKey(publicKeyHex, keystorePath, passwordFile)
+			if err != nil {
+				return err
+			}
+
+			var classHash *felt.Felt
+			if classHashHex != "" {
+				classHash, err = new(felt.Felt).SetString(classHashHex)
+				if err != nil {
+					return fmt.Errorf("invalid class hash %q: %w", classHashHex, err)
+				}
+			}
+			accountClass, err := signer.NewAccountClass(signer.AccountType(accountType), classHash)
+			if err != nil {
+				return err
+			}
+
+			salt := publicKey
+			if saltHex != "" {
+				salt, err = new(felt.Felt).SetString(saltHex)
+				if err != nil {
+					return fmt.Errorf("invalid salt %q: %w", saltHex, err)
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Account type: %s\n", accountClass.Type)
+			fmt.Fprintf(out, "Class hash: %s\n", accountClass.ClassHash)
+			fmt.Fprintf(out, "Public key: %s\n", publicKey)
+			fmt.Fprintf(out, "Salt: %s\n", salt)
+			fmt.Fprintf(out, "Address: %s\n", accountClass.Address(publicKey, salt))
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&publicKeyHex, "public-key", "", "Public key owning the account")
+	cmd.Flags().StringVar(
+		&keystorePath,
+		"keystore",
+		"",
+		"Path to an encrypted keystore holding the key owning the account",
+	)
+	cmd.Flags().StringVar(
+		&passwordFile,
+		"password-file",
+		"",
+		"Path to a file holding the keystore password. If not set, the password is read"+
+			" from the "+keystorePasswordEnv+" env var or prompted for",
+	)
+	cmd.Flags().StringVar(
+		&accountType,
+		"account-type",
+		string(signer.AccountTypeOpenZeppelin),
+		fmt.Sprintf(
+			"Account contract: %q, %q or %q",
+			signer.AccountTypeOpenZeppelin, signer.AccountTypeArgent, signer.AccountTypeBraavos,
+		),
+	)
+	cmd.Flags().StringVar(
+		&classHashHex,
+		"class-hash",
+		"",
+		"Class hash of the account contract. Defaults to OpenZeppelin v0.8.1, Argent v0.4.0"+
+			" or the Braavos base account, depending on the account type",
+	)
+	cmd.Flags().StringVar(
+		&saltHex, "salt", "", "Salt of the account deployment. Defaults to the public key",
+	)
+
+	return cmd
+}
+
+// Returns the public key given, or the one of the key held by the keystore
+func readPublicKey(publicKeyHex, keystorePath, passwordFile string) (*felt.Felt, error) {
+	if (publicKeyHex == "") == (keystorePath == "") {
+		return nil, errors.New("exactly one of --public-key or --keystore should be set")
+	}
+	if publicKeyHex != "" {
+		publicKey, err := new(felt.Felt).SetString(publicKeyHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %q: %w", publicKeyHex, err)
+		}
+
+		return publicKey, nil
+	}
+
+	privKey, err := readSignerKeyFromKeystore(keystorePath, passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, _ := curve.PrivateKeyToPoint(privKey)
+
+	return new(felt.Felt).SetBigInt(publicKey), nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/spf13/cobra"
+)
+
+func NewAuditCommand() *cobra.Command {
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the audit log of signing requests",
+	}
+
+	cmd.AddCommand(newAuditVerifyCommand())
+
+	return cmd
+}
+
+func newAuditVerifyCommand() *cobra.Command {
+	var expectedHead string
+
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "verify <audit log path>",
+		Short: "Check the audit log was neither modified nor truncated",
+		Long: "Check every entry of the audit log is unmodified and chained to the previous" +
+			" one, and that the last entry matches the head file written next to the log." +
+			" Pass the hash of the last entry as recorded elsewhere with --head to also" +
+			" detect the log and its head file being rewritten together.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			lastEntry, err := signer.VerifyAuditLog(args[0], expectedHead)
+			if err != nil {
+				return fmt.Errorf("audit log verification failed: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if lastEntry == nil {
+				fmt.Fprintln(out, "Audit log is empty")
+
+				return nil
+			}
+			fmt.Fprintf(out, "Audit log is valid: %d entries\n", lastEntry.Seq+1)
+			fmt.Fprintf(out, "Last entry hash: %s\n", lastEntry.Hash)
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(
+		&expectedHead, "head", "", "Expected hash of the last entry of the audit log",
+	)
+
+	return cmd
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+)
+
+// Re-reads the keys file on SIGHUP, enabling or disabling keys as set in it. Adding or
+// removing keys requires a restart.
+func watchKeyStates(
+	ctx context.Context, keysPath string, remoteSigner *signer.Signer, logger *utils.ZapLogger,
+) {
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(reload)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reload:
+				reloadKeyStates(keysPath, remoteSigner, logger)
+			}
+		}
+	}()
+}
+
+func reloadKeyStates(keysPath string, remoteSigner *signer.Signer, logger *utils.ZapLogger) {
+	keysConfig, err := signer.LoadKeysConfig(keysPath)
+	if err != nil {
+		logger.Errorw("cannot reload keys file, keeping current key states", "error", err)
+
+		return
+	}
+
+	for _, key := range keysConfig.Keys {
+		account, err := new(felt.Felt).SetString(key.Account)
+		if err != nil {
+			continue
+		}
+		if err := remoteSigner.SetKeyEnabled(account, !key.Disabled); err != nil {
+			logger.Warnf("key for account %s is new, restart the signer to load it", key.Account)
+
+			continue
+		}
+		logger.Infow("reloaded key state", "account", key.Account, "enabled", !key.Disabled)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/NethermindEth/starknet-staking-v2/keystore"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/spf13/cobra"
+)
+
+const keystorePasswordEnv = "SIGNER_KEYSTORE_PASSWORD"
+
+func NewKeystoreCommand() *cobra.Command {
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "keystore",
+		Short: "Manage encrypted JSON keystores holding signing keys",
+	}
+
+	cmd.AddCommand(
+		newKeystoreNewCommand(), newKeystoreImportCommand(), newKeystoreInspectCommand(),
+	)
+
+	return cmd
+}
+
+func newKeystoreNewCommand() *cobra.Command {
+	var passwordFile string
+
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "new <keystore path>",
+		Short: "Generate a new random signing key and store it in an encrypted keystore",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			privKey, _, _, err := curve.GetRandomKeys()
+			if err != nil {
+				return fmt.Errorf("cannot generate a new key: %w", err)
+			}
+
+			return writeKeystore(cmd, args[0], privKey, passwordFile)
+		},
+	}
+	addNewPasswordFileFlag(cmd, &passwordFile)
+
+	return cmd
+}
+
+func newKeystoreImportCommand() *cobra.Command {
+	var passwordFile string
+	var privateKeyFile string
+
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "import <keystore path>",
+		Short: "Encrypt an existing signing key into a keystore",
+		Long: "Encrypt an existing signing key into a keystore. The private key is either" +
+			" read from a file or typed in interactively, so it never ends up in the shell" +
+			" history.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			privKey, err := readPrivateKey(privateKeyFile)
+			if err != nil {
+				return err
+			}
+
+			return writeKeystore(cmd, args[0], privKey, passwordFile)
+		},
+	}
+	addNewPasswordFileFlag(cmd, &passwordFile)
+	addPrivateKeyFileFlag(cmd, &privateKeyFile, "import")
+
+	return cmd
+}
+
+func addPrivateKeyFileFlag(cmd *cobra.Command, privateKeyFile *string, action string) {
+	cmd.Flags().StringVar(
+		privateKeyFile,
+		"private-key-file",
+		"",
+		"Path to a file holding the private key to "+action+". Prompted for if not set",
+	)
+}
+
+// Reads the private key from the file, or prompts for it if no file is given, so it
+// never ends up in the shell history
+func readPrivateKey(privateKeyFile string) (*big.Int, error) {
+	var rawKey string
+	if privateKeyFile != "" {
+		data, err := os.ReadFile(privateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read private key file: %w", err)
+		}
+		rawKey = string(data)
+	} else {
+		var err error
+		rawKey, err = keystore.PromptSecret("Enter private key: ")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	privKey, ok := new(big.Int).SetString(strings.TrimSpace(rawKey), 0)
+	if !ok {
+		return nil, errors.New("the provided value is not a valid private key")
+	}
+
+	return privKey, nil
+}
+
+func newKeystoreInspectCommand() *cobra.Command {
+	var passwordFile string
+
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "inspect <keystore path>",
+		Short: "Decrypt a keystore and show the public key of the key it holds",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, err := keystore.Load(args[0])
+			if err != nil {
+				return err
+			}
+
+			privKey, err := readSignerKeyFromKeystore(args[0], passwordFile)
+			if err != nil {
+				return err
+			}
+			publicKey, _ := curve.PrivateKeyToPoint(privKey)
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Keystore ID: %s\n", ks.ID)
+			fmt.Fprintf(out, "KDF: %s\n", ks.Crypto.KDF)
+			fmt.Fprintf(out, "Public key: 0x%s\n", publicKey.Text(16)) //nolint:mnd // Hex base
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(
+		&passwordFile,
+		"password-file",
+		"",
+		"Path to a file holding the keystore password. If not set, the password is read"+
+			" from the "+keystorePasswordEnv+" env var or prompted for",
+	)
+
+	return cmd
+}
+
+func addNewPasswordFileFlag(cmd *cobra.Command, passwordFile *string) {
+	cmd.Flags().StringVar(
+		passwordFile,
+		"password-file",
+		"",
+		"Path to a file holding the password for the new keystore. If not set, the password"+
+			" is read from the "+keystorePasswordEnv+" env var or prompted for",
+	)
+}
+
+func writeKeystore(cmd *cobra.Command, path string, privKey *big.Int, passwordFile string) error {
+	password, err := readNewPassword(passwordFile)
+	if err != nil {
+		return err
+	}
+
+	ks, err := keystore.Encrypt(privKey, password, keystore.StandardScryptN)
+	if err != nil {
+		return fmt.Errorf("cannot encrypt key: %w", err)
+	}
+	if err := ks.Save(path); err != nil {
+		return fmt.Errorf("cannot write keystore: %w", err)
+	}
+
+	publicKey, _ := curve.PrivateKeyToPoint(privKey)
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Keystore written to %s\n", path)
+	fmt.Fprintf(out, "Public key: 0x%s\n", publicKey.Text(16)) //nolint:mnd // Hex base
+
+	return nil
+}
+
+// Reads the password for a new keystore. Typed in passwords have to be confirmed
+func readNewPassword(passwordFile string) (string, error) {
+	if _, ok := os.LookupEnv(keystorePasswordEnv); passwordFile == "" && !ok {
+		return keystore.PromptNewPassword()
+	}
+
+	//nolint:exhaustruct // Prompting is done separately to ask for a confirmation
+	passwordSource := keystore.PasswordSource{File: passwordFile, Env: keystorePasswordEnv}
+
+	return passwordSource.Read()
+}
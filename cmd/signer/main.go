@@ -4,23 +4,34 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/keystore"
+	"github.com/NethermindEth/starknet-staking-v2/secret"
 	"github.com/NethermindEth/starknet-staking-v2/signer"
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
 )
 
-func NewCommand() cobra.Command {
+func NewCommand() *cobra.Command {
 	var address string
+	var grpcAddress string
 	var envFilePath string
 	var logLevelF string
+	var keystorePath string
+	var keystorePasswordFile string
+	var keysPath string
+	var options serverOptions
 
-	var privKey string
+	var privKey *big.Int
+	var accountKeys []signer.AccountKey
 	var logger *utils.ZapLogger
 
-	preRunE := func(_ *cobra.Command, args []string) error {
+	preRunE := func(cmd *cobra.Command, args []string) error {
 		var err error
 
 		logLevel := utils.NewLogLevel(utils.INFO)
@@ -33,7 +44,43 @@ func NewCommand() cobra.Command {
 			return err
 		}
 
-		privKey, err = readSignerKeyFromEnv(envFilePath, logger)
+		loadEnvFile(envFilePath, logger)
+
+		options.fillFromEnv()
+
+		if keysPath == "" {
+			keysPath = os.Getenv("SIGNER_KEYS")
+		}
+		if keystorePath == "" {
+			keystorePath = os.Getenv("SIGNER_KEYSTORE")
+		}
+		if keysPath != "" {
+			if keystorePath != "" {
+				return errors.New("cannot use both a keys file and a keystore")
+			}
+			keysConfig, err := signer.LoadKeysConfig(keysPath)
+			if err != nil {
+				return err
+			}
+			accountKeys, err = signer.LoadAccountKeys(cmd.Context(), &keysConfig)
+			if err != nil {
+				return err
+			}
+			logger.Infof("loaded %d signing keys from %s", len(accountKeys), keysPath)
+
+			return nil
+		}
+		if keystorePath != "" {
+			privKey, err = readSignerKeyFromKeystore(keystorePath, keystorePasswordFile)
+			if err != nil {
+				return err
+			}
+			logger.Infof("loaded signing key from keystore %s", keystorePath)
+
+			return nil
+		}
+
+		privKey, err = readSignerKeyFromEnv(cmd.Context())
 		if err != nil {
 			return err
 		}
@@ -41,17 +88,43 @@ func NewCommand() cobra.Command {
 		return nil
 	}
 
-	runE := func(_ *cobra.Command, args []string) error {
-		remoteSigner, err := signer.New(privKey, logger)
+	runE := func(cmd *cobra.Command, args []string) error {
+		var remoteSigner signer.Signer
+		if accountKeys != nil {
+			var err error
+			remoteSigner, err = signer.NewWithAccountKeys(accountKeys, logger)
+			if err != nil {
+				return err
+			}
+			watchKeyStates(cmd.Context(), keysPath, &remoteSigner, logger)
+		} else {
+			remoteSigner = signer.NewWithKey(privKey, logger)
+		}
+		defer func() {
+			if err := remoteSigner.Close(); err != nil {
+				logger.Warnw("cannot close signing keys", "error", err)
+			}
+		}()
+
+		closeFn, err := options.apply(cmd.Context(), &remoteSigner, logger)
 		if err != nil {
 			return err
 		}
+		defer closeFn()
 
-		return remoteSigner.Listen(address)
+		// Finish answering in-flight requests before releasing the history and audit log
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if grpcAddress == "" {
+			return remoteSigner.Serve(ctx, address)
+		}
+
+		return serveWithGRPC(ctx, &remoteSigner, address, grpcAddress)
 	}
 
 	//nolint:exhaustruct // Only specifying used fields
-	cmd := cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "signer",
 		Short:   "Program that signs transactions received by http request",
 		PreRunE: preRunE,
@@ -60,12 +133,56 @@ func NewCommand() cobra.Command {
 	}
 
 	cmd.Flags().StringVar(
-		&address, "address", "localhost:8080", "Address where to listen for requests",
+		&address,
+		"address",
+		"localhost:8080",
+		"Address where to listen for requests, or 'unix:///path/to/socket' to listen on a"+
+			" Unix domain socket",
+	)
+	cmd.Flags().StringVar(
+		&grpcAddress,
+		"grpc-address",
+		"",
+		"If set, also serve the gRPC interface of the signer at this address, with the same"+
+			" authentication and TLS settings",
 	)
 	cmd.Flags().StringVar(&envFilePath, "env", ".env", "Path to JSON config file")
 	cmd.Flags().StringVar(
 		&logLevelF, "log-level", utils.INFO.String(), "Options: trace, debug, info, warn, error",
 	)
+	cmd.Flags().StringVar(
+		&keystorePath,
+		"keystore",
+		"",
+		"Path to an encrypted JSON keystore with the signing key. Takes precedence over the"+
+			" SIGNER_PRIVATE_KEY env var",
+	)
+	cmd.Flags().StringVar(
+		&keystorePasswordFile,
+		"keystore-password-file",
+		"",
+		"Path to a file holding the keystore password. If not set, the password is read"+
+			" from the SIGNER_KEYSTORE_PASSWORD env var or prompted for",
+	)
+
+	cmd.Flags().StringVar(
+		&keysPath,
+		"keys",
+		"",
+		"Path to a JSON file with several signing keys, each one bound to the account it signs"+
+			" for. Can also be set with the SIGNER_KEYS env var. Send SIGHUP to the signer to"+
+			" apply changes to the keys disabled state",
+	)
+	options.addFlags(cmd)
+
+	cmd.AddCommand(
+		NewKeystoreCommand(),
+		NewAuditCommand(),
+		NewPKCS11Command(),
+		newKeygenCommand(),
+		newAddressCommand(),
+		newSignFileCommand(),
+	)
 
 	return cmd
 }
@@ -79,20 +196,51 @@ func main() {
 	}
 }
 
-func readSignerKeyFromEnv(envFilePath string, logger *utils.ZapLogger) (string, error) {
+func loadEnvFile(envFilePath string, logger *utils.ZapLogger) {
 	err := godotenv.Load(envFilePath)
 	if err != nil {
 		logger.Debugf("couldn't load env var at %s: %s", envFilePath, err)
 	}
+}
 
-	signerKey := os.Getenv("SIGNER_PRIVATE_KEY")
-	if signerKey == "" {
-		return "",
+// Reads the signing key from the SIGNER_PRIVATE_KEY env var, which can either hold the
+// key itself or a `file:`, `env:` or `exec:` reference to it
+func readSignerKeyFromEnv(ctx context.Context) (*big.Int, error) {
+	signerKey := secret.New(os.Getenv("SIGNER_PRIVATE_KEY"))
+	if signerKey.IsZero() {
+		return nil,
 			errors.New(
 				"couldn't read SIGNER_PRIVATE_KEY env var." +
 					"Please make sure it is set before running this program",
 			)
 	}
+	if err := signerKey.Resolve(ctx); err != nil {
+		return nil, fmt.Errorf("resolving SIGNER_PRIVATE_KEY: %w", err)
+	}
+
+	privKey, ok := new(big.Int).SetString(signerKey.Reveal(), 0)
+	if !ok {
+		return nil, errors.New("SIGNER_PRIVATE_KEY env var is not a valid private key")
+	}
+
+	return privKey, nil
+}
+
+func readSignerKeyFromKeystore(keystorePath, passwordFile string) (*big.Int, error) {
+	passwordSource := keystore.PasswordSource{
+		File:   passwordFile,
+		Env:    keystorePasswordEnv,
+		Prompt: fmt.Sprintf("Enter password for keystore %s: ", keystorePath),
+	}
+	password, err := passwordSource.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	privKey, err := keystore.Open(keystorePath, password)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open keystore %s: %w", keystorePath, err)
+	}
 
-	return signerKey, nil
+	return privKey, nil
 }
@@ -1 +1,304 @@
 package main_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	main "github.com/NethermindEth/starknet-staking-v2/cmd/signer"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	snUtils "github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeystoreCommand(t *testing.T) {
+	dir := t.TempDir()
+	keystorePath := filepath.Join(dir, "keystore.json")
+	passwordFile := writeFile(t, dir, "password", "some password\n")
+	privateKeyFile := writeFile(t, dir, "private-key", "0x123\n")
+	// Public key for private key "0x123"
+	const publicKey = "0x566d69d8c99f62bc71118399bab25c1f03719463eab8d6a444cd11ece131616"
+
+	t.Run("Import private key into a keystore", func(t *testing.T) {
+		output := executeCommand(
+			t,
+			"keystore", "import", keystorePath,
+			"--private-key-file", privateKeyFile,
+			"--password-file", passwordFile,
+		)
+		require.Contains(t, output, "Public key: "+publicKey)
+	})
+
+	t.Run("Importing never overwrites an existing keystore", func(t *testing.T) {
+		cmd := main.NewCommand()
+		cmd.SetArgs([]string{
+			"keystore", "import", keystorePath,
+			"--private-key-file", privateKeyFile,
+			"--password-file", passwordFile,
+		})
+		require.ErrorIs(t, cmd.ExecuteContext(t.Context()), os.ErrExist)
+	})
+
+	t.Run("Inspect keystore with the password from env", func(t *testing.T) {
+		t.Setenv("SIGNER_KEYSTORE_PASSWORD", "some password")
+
+		output := executeCommand(t, "keystore", "inspect", keystorePath)
+		require.Contains(t, output, "Public key: "+publicKey)
+	})
+
+	t.Run("Inspect keystore with the wrong password", func(t *testing.T) {
+		t.Setenv("SIGNER_KEYSTORE_PASSWORD", "wrong password")
+
+		cmd := main.NewCommand()
+		cmd.SetArgs([]string{"keystore", "inspect", keystorePath})
+		require.ErrorContains(t, cmd.ExecuteContext(t.Context()), "wrong password")
+	})
+}
+
+func TestKeygenCommand(t *testing.T) {
+	t.Run("Print a new key", func(t *testing.T) {
+		output := executeCommand(t, "keygen")
+		require.Contains(t, output, "Private key: 0x")
+		require.Contains(t, output, "Public key: 0x")
+	})
+
+	t.Run("Write a new key to a keystore", func(t *testing.T) {
+		dir := t.TempDir()
+		keystorePath := filepath.Join(dir, "keystore.json")
+		passwordFile := writeFile(t, dir, "password", "some password\n")
+
+		output := executeCommand(
+			t, "keygen", "--keystore", keystorePath, "--password-file", passwordFile,
+		)
+		require.NotContains(t, output, "Private key")
+		require.Contains(t, output, "Keystore written to "+keystorePath)
+		require.FileExists(t, keystorePath)
+	})
+}
+
+func TestAddressCommand(t *testing.T) {
+	// Public key for private key "0x123"
+	const publicKey = "0x566d69d8c99f62bc71118399bab25c1f03719463eab8d6a444cd11ece131616"
+
+	expectedAddress := func(
+		t *testing.T, accountType signer.AccountType, salt *felt.Felt,
+	) string {
+		t.Helper()
+
+		class, err := signer.NewAccountClass(accountType, nil)
+		require.NoError(t, err)
+
+		return class.Address(utils.HexToFelt(t, publicKey), salt).String()
+	}
+
+	t.Run("Address of an OpenZeppelin account salted with the public key", func(t *testing.T) {
+		output := executeCommand(t, "address", "--public-key", publicKey)
+		require.Contains(t, output, "Account type: oz")
+		require.Contains(
+			t,
+			output,
+			"Address: "+expectedAddress(t, signer.AccountTypeOpenZeppelin, utils.HexToFelt(t, publicKey)),
+		)
+	})
+
+	t.Run("Address of an Argent account from a keystore", func(t *testing.T) {
+		dir := t.TempDir()
+		keystorePath := filepath.Join(dir, "keystore.json")
+		passwordFile := writeFile(t, dir, "password", "some password\n")
+		executeCommand(
+			t,
+			"keystore", "import", keystorePath,
+			"--private-key-file", writeFile(t, dir, "private-key", "0x123\n"),
+			"--password-file", passwordFile,
+		)
+
+		output := executeCommand(
+			t,
+			"address",
+			"--keystore", keystorePath,
+			"--password-file", passwordFile,
+			"--account-type", "argent",
+			"--salt", "0x1",
+		)
+		require.Contains(
+			t, output, "Address: "+expectedAddress(t, signer.AccountTypeArgent, new(felt.Felt).SetUint64(1)),
+		)
+	})
+
+	t.Run("Both a public key and a keystore", func(t *testing.T) {
+		cmd := main.NewCommand()
+		cmd.SetArgs([]string{"address", "--public-key", publicKey, "--keystore", "keystore.json"})
+		require.ErrorContains(t, cmd.ExecuteContext(t.Context()), "exactly one of")
+	})
+}
+
+func TestSignFileCommand(t *testing.T) {
+	dir := t.TempDir()
+	keystorePath := filepath.Join(dir, "keystore.json")
+	passwordFile := writeFile(t, dir, "password", "some password\n")
+	executeCommand(
+		t,
+		"keystore", "import", keystorePath,
+		"--private-key-file", writeFile(t, dir, "private-key", "0x123\n"),
+		"--password-file", passwordFile,
+	)
+
+	txnPath := filepath.Join(dir, "txn.json")
+	stakingContract := utils.HexToFelt(t, constants.SepoliaStakingContractAddress)
+	calls := snUtils.InvokeFuncCallsToFunctionCalls([]rpc.InvokeFunctionCall{{
+		ContractAddress: stakingContract,
+		FunctionName:    "claim_rewards",
+		CallData:        []*felt.Felt{utils.HexToFelt(t, "0x456")},
+	}})
+	unsigned := signer.OfflineTransaction{
+		ChainID: new(felt.Felt).SetBytes([]byte("SN_SEPOLIA")),
+		Transaction: &rpc.BroadcastInvokeTxnV3{
+			Type:          rpc.TransactionTypeInvoke,
+			SenderAddress: utils.HexToFelt(t, "0x456"),
+			Calldata:      account.FmtCallDataCairo2(calls),
+			Version:       rpc.TransactionV3,
+			Signature:     []*felt.Felt{},
+			Nonce:         new(felt.Felt).SetUint64(1),
+			ResourceBounds: &rpc.ResourceBoundsMapping{
+				L1Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+				L1DataGas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+				L2Gas:     rpc.ResourceBounds{MaxAmount: "0x1000", MaxPricePerUnit: "0x10"},
+			},
+			Tip:                   "0x0",
+			PayMasterData:         []*felt.Felt{},
+			AccountDeploymentData: []*felt.Felt{},
+			NonceDataMode:         rpc.DAModeL1,
+			FeeMode:               rpc.DAModeL1,
+		},
+	}
+	require.NoError(t, unsigned.WriteFile(txnPath))
+
+	for _, answer := range []string{"n\n", ""} {
+		t.Run(fmt.Sprintf("Signing not confirmed with %q", answer), func(t *testing.T) {
+			cmd := main.NewCommand()
+			cmd.SetIn(strings.NewReader(answer))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs([]string{
+				"sign-file", txnPath, "--keystore", keystorePath, "--password-file", passwordFile,
+			})
+			require.ErrorContains(t, cmd.ExecuteContext(t.Context()), "signing cancelled")
+
+			txn, err := signer.ReadOfflineTransaction(txnPath)
+			require.NoError(t, err)
+			require.False(t, txn.Signed())
+		})
+	}
+
+	t.Run("Signing confirmed", func(t *testing.T) {
+		outPath := filepath.Join(dir, "confirmed.json")
+		cmd := main.NewCommand()
+		cmd.SetIn(strings.NewReader("y\n"))
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs([]string{
+			"sign-file",
+			txnPath,
+			"--keystore",
+			keystorePath,
+			"--password-file",
+			passwordFile,
+			"--out",
+			outPath,
+		})
+		require.NoError(t, cmd.ExecuteContext(t.Context()))
+
+		txn, err := signer.ReadOfflineTransaction(outPath)
+		require.NoError(t, err)
+		require.True(t, txn.Signed())
+	})
+
+	t.Run("Sign a transaction file", func(t *testing.T) {
+		output := executeCommand(
+			t,
+			"sign-file", txnPath,
+			"--keystore", keystorePath,
+			"--password-file", passwordFile,
+			"--yes",
+		)
+		require.Contains(t, output, "Chain: SN_SEPOLIA")
+		require.Contains(t, output, "Max fee: 65536 FRI")
+		require.Contains(t, output, "Call 1: claim_rewards on "+stakingContract.String())
+
+		txn, err := signer.ReadOfflineTransaction(txnPath)
+		require.NoError(t, err)
+		require.True(t, txn.Signed())
+		txHash, err := txn.Hash()
+		require.NoError(t, err)
+		require.Contains(t, output, "Transaction hash: "+txHash.String())
+	})
+
+	t.Run("Signed transaction files are not signed again", func(t *testing.T) {
+		cmd := main.NewCommand()
+		cmd.SetArgs([]string{
+			"sign-file",
+			txnPath,
+			"--keystore",
+			keystorePath,
+			"--password-file",
+			passwordFile,
+			"--yes",
+		})
+		require.ErrorContains(t, cmd.ExecuteContext(t.Context()), "already signed")
+	})
+}
+
+func executeCommand(t *testing.T, args ...string) string {
+	t.Helper()
+
+	var output bytes.Buffer
+	cmd := main.NewCommand()
+	cmd.SetOut(&output)
+	cmd.SetArgs(args)
+	require.NoError(t, cmd.ExecuteContext(t.Context()))
+
+	return output.String()
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestAuditVerifyCommand(t *testing.T) {
+	dir := t.TempDir()
+	auditLogPath := filepath.Join(dir, "audit.jsonl")
+
+	auditLog, err := signer.OpenAuditLog(auditLogPath)
+	require.NoError(t, err)
+	//nolint:exhaustruct // Only specifying used fields
+	entry := signer.AuditEntry{Decision: signer.DecisionRefused, Reason: "some reason"}
+	require.NoError(t, auditLog.Append(&entry))
+	require.NoError(t, auditLog.Close())
+
+	t.Run("Verify valid audit log", func(t *testing.T) {
+		output := executeCommand(t, "audit", "verify", auditLogPath, "--head", entry.Hash)
+		require.Contains(t, output, "Audit log is valid: 1 entries")
+	})
+
+	t.Run("Verify truncated audit log", func(t *testing.T) {
+		require.NoError(t, os.Truncate(auditLogPath, 0))
+
+		cmd := main.NewCommand()
+		cmd.SetArgs([]string{"audit", "verify", auditLogPath})
+		require.ErrorContains(t, cmd.ExecuteContext(t.Context()), "audit log was truncated")
+	})
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	snUtils "github.com/NethermindEth/starknet.go/utils"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func newSignFileCommand() *cobra.Command {
+	var keystorePath string
+	var passwordFile string
+	var outPath string
+	var yes bool
+
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "sign-file <transaction file>",
+		Short: "Sign a transaction file built by `validator tx build`",
+		Long: "Sign a transaction file built by `validator tx build` with the key of a" +
+			" keystore, without any network access. The transaction is shown and has to be" +
+			" confirmed before being signed, and the signed transaction is written back to the" +
+			" file unless an output path is given. Send it with `validator tx submit`.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txn, err := signer.ReadOfflineTransaction(args[0])
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if err := describeOfflineTransaction(out, txn); err != nil {
+				return err
+			}
+			if !yes {
+				if err := confirmSigning(cmd); err != nil {
+					return err
+				}
+			}
+
+			privKey, err := readSignerKeyFromKeystore(keystorePath, passwordFile)
+			if err != nil {
+				return err
+			}
+			key := signer.NewMemoryKey(privKey)
+			defer func() { _ = key.Close() }()
+
+			txHash, err := txn.Sign(cmd.Context(), key)
+			if err != nil {
+				return err
+			}
+			if outPath == "" {
+				outPath = args[0]
+			}
+			if err := txn.WriteFile(outPath); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Transaction hash: %s\n", txHash)
+			fmt.Fprintf(out, "Signed transaction written to %s\n", outPath)
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(
+		&keystorePath, "keystore", "", "Path to the encrypted keystore holding the signing key",
+	)
+	_ = cmd.MarkFlagRequired("keystore")
+	cmd.Flags().StringVar(
+		&passwordFile,
+		"password-file",
+		"",
+		"Path to a file holding the keystore password. If not set, the password is read"+
+			" from the "+keystorePasswordEnv+" env var or prompted for",
+	)
+	cmd.Flags().StringVar(
+		&outPath,
+		"out",
+		"",
+		"Path the signed transaction is written to. Defaults to the transaction file",
+	)
+	cmd.Flags().BoolVar(
+		&yes,
+		"yes",
+		false,
+		"Sign the transaction without asking for a confirmation. Required when not run"+
+			" from a terminal",
+	)
+
+	return cmd
+}
+
+// Asks for a confirmation before signing. Without a terminal to ask on, signing has to be
+// confirmed with --yes
+func confirmSigning(cmd *cobra.Command) error {
+	in := cmd.InOrStdin()
+	//nolint:gosec // File descriptors fit in an int
+	if in == os.Stdin && !term.IsTerminal(int(os.Stdin.Fd())) {
+		return errors.New("signing has to be confirmed, run from a terminal or pass --yes")
+	}
+
+	fmt.Fprint(cmd.ErrOrStderr(), "Sign this transaction? [y/N]: ")
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("cannot read the confirmation: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return errors.New("signing cancelled")
+	}
+}
+
+// Shows what signing the transaction allows, so it can be checked before being signed
+func describeOfflineTransaction(out io.Writer, txn *signer.OfflineTransaction) error {
+	if txn.Signed() {
+		return errors.New("the transaction is already signed")
+	}
+	maxFee, err := snUtils.ResBoundsMapToOverallFee(
+		txn.Transaction.ResourceBounds, 1, txn.Transaction.Tip,
+	)
+	if err != nil {
+		return fmt.Errorf("invalid resource bounds: %w", err)
+	}
+
+	fmt.Fprintf(out, "Chain: %s\n", snUtils.HexToShortStr(txn.ChainID.String()))
+	fmt.Fprintf(out, "Sender: %s\n", txn.Transaction.SenderAddress)
+	fmt.Fprintf(out, "Nonce: %s\n", txn.Transaction.Nonce)
+	fmt.Fprintf(
+		out,
+		"Max fee: %s FRI\n",
+		maxFee.BigInt(new(big.Int)).Text(10), //nolint:mnd // Decimal base
+	)
+
+	calls, ok := txn.Calls()
+	if !ok {
+		fmt.Fprintf(out, "Calldata: %s\n", txn.Transaction.Calldata)
+
+		return nil
+	}
+	for i, call := range calls {
+		function := call.Function
+		if function == "" {
+			function = call.Selector.String()
+		}
+		fmt.Fprintf(
+			out, "Call %d: %s on %s with %s\n", i+1, function, call.ContractAddress, call.Calldata,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/NethermindEth/starknet-staking-v2/keystore"
+	"github.com/NethermindEth/starknet-staking-v2/secret"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/spf13/cobra"
+)
+
+const pkcs11PINEnv = "SIGNER_PKCS11_PIN"
+
+func NewPKCS11Command() *cobra.Command {
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "pkcs11",
+		Short: "Manage signing keys held by PKCS#11 tokens",
+	}
+
+	cmd.AddCommand(newPKCS11WrapCommand())
+
+	return cmd
+}
+
+func newPKCS11WrapCommand() *cobra.Command {
+	var config signer.PKCS11Config
+	var privateKeyFile string
+
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "wrap <wrapped key path>",
+		Short: "Encrypt a signing key with an AES key of a PKCS#11 token",
+		Long: "Encrypt a signing key with an AES key of a PKCS#11 token, for tokens without" +
+			" Stark curve support. The wrapped key can only be decrypted by the token, and is" +
+			" set as the wrappedKey of a pkcs11 key in the keys file.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if config.PIN.IsZero() {
+				pin, ok := os.LookupEnv(pkcs11PINEnv)
+				if !ok {
+					var err error
+					pin, err = keystore.PromptSecret("Enter token PIN: ")
+					if err != nil {
+						return err
+					}
+				}
+				config.PIN = secret.New(pin)
+			}
+			privKey, err := readPrivateKey(privateKeyFile)
+			if err != nil {
+				return err
+			}
+
+			wrapped, err := signer.WrapPKCS11Key(cmd.Context(), &config, privKey)
+			if err != nil {
+				return err
+			}
+			if _, err := os.Stat(args[0]); !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("%s already exists", args[0])
+			}
+			if err := os.WriteFile(args[0], wrapped, 0o600); err != nil {
+				return fmt.Errorf("cannot write wrapped key: %w", err)
+			}
+
+			publicKey, _ := curve.PrivateKeyToPoint(privKey)
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Wrapped key written to %s\n", args[0])
+			fmt.Fprintf(out, "Public key: 0x%s\n", publicKey.Text(16)) //nolint:mnd // Hex base
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&config.Module, "module", "", "Path to the PKCS#11 module of the token")
+	cmd.Flags().StringVar(&config.TokenLabel, "token-label", "", "Label of the token")
+	cmd.Flags().Var(
+		&config.PIN,
+		"pin",
+		"PIN of the token user, or a reference to it such as env:NAME or file:/path. If not"+
+			" set, the PIN is read from the "+pkcs11PINEnv+" env var or prompted for",
+	)
+	cmd.Flags().StringVar(
+		&config.KeyLabel, "key-label", "", "Label of the AES key of the token wrapping the key",
+	)
+	addPrivateKeyFileFlag(cmd, &privateKeyFile, "wrap")
+
+	return cmd
+}
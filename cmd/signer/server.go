@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/spf13/cobra"
+)
+
+// Settings protecting the signer server: client authentication, TLS, signing policy
+// and double signing protection. Also where signing requests are audited and reported
+type serverOptions struct {
+	authConfigPath   string
+	authReplayWindow time.Duration
+	tls              signer.TLSConfig
+	policyPath       string
+	historyPath      string
+	auditLogPath     string
+	metrics          bool
+	allowHashOnly    bool
+}
+
+func (o *serverOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&o.authConfigPath,
+		"auth-config",
+		"",
+		"Path to a JSON file with the clients allowed to request signatures. Can also be set"+
+			" with the SIGNER_AUTH_CONFIG env var. If not set, requests are not authenticated",
+	)
+	cmd.Flags().DurationVar(
+		&o.authReplayWindow,
+		"auth-replay-window",
+		signer.DefaultReplayWindow,
+		"Maximum clock difference accepted for HMAC signed requests",
+	)
+	cmd.Flags().StringVar(
+		&o.tls.CertFile, "tls-cert", "", "Path to the PEM certificate used to serve TLS",
+	)
+	cmd.Flags().StringVar(
+		&o.tls.KeyFile, "tls-key", "", "Path to the PEM private key of the TLS certificate",
+	)
+	cmd.Flags().StringVar(
+		&o.tls.ClientCAFile,
+		"tls-client-ca",
+		"",
+		"Path to a PEM CA bundle. If set, clients must present a certificate issued by it",
+	)
+	cmd.Flags().StringVar(
+		&o.policyPath,
+		"policy",
+		"",
+		"Path to a JSON file with the signing policy. Can also be set with the SIGNER_POLICY"+
+			" env var. If not set, any transaction is signed",
+	)
+	cmd.Flags().StringVar(
+		&o.historyPath,
+		"history",
+		"",
+		"Path to the file recording every signed transaction, used to refuse double signing."+
+			" Can also be set with the SIGNER_HISTORY env var",
+	)
+	cmd.Flags().StringVar(
+		&o.auditLogPath,
+		"audit-log",
+		"",
+		"Path to the hash-chained audit log recording every signing request. Can also be set"+
+			" with the SIGNER_AUDIT_LOG env var",
+	)
+	cmd.Flags().BoolVar(
+		&o.metrics, "metrics", false, "Expose Prometheus metrics of the signing requests at /metrics",
+	)
+	cmd.Flags().BoolVar(
+		&o.allowHashOnly,
+		"allow-hash-only",
+		false,
+		"Sign hashes sent without their transaction. Can't be used with a policy nor a history,"+
+			" as neither can be checked without the transaction",
+	)
+}
+
+// Fills the options not set through flags from their env vars
+func (o *serverOptions) fillFromEnv() {
+	if o.authConfigPath == "" {
+		o.authConfigPath = os.Getenv("SIGNER_AUTH_CONFIG")
+	}
+	if o.policyPath == "" {
+		o.policyPath = os.Getenv("SIGNER_POLICY")
+	}
+	if o.historyPath == "" {
+		o.historyPath = os.Getenv("SIGNER_HISTORY")
+	}
+	if o.auditLogPath == "" {
+		o.auditLogPath = os.Getenv("SIGNER_AUDIT_LOG")
+	}
+}
+
+// Configures the signer with the options. The returned function releases the resources
+// opened for it
+func (o *serverOptions) apply(
+	ctx context.Context, remoteSigner *signer.Signer, logger *utils.ZapLogger,
+) (func(), error) {
+	if o.allowHashOnly && (o.policyPath != "" || o.historyPath != "") {
+		return nil, errors.New("--allow-hash-only cannot be used with a signing policy or history")
+	}
+
+	if o.authConfigPath != "" {
+		authConfig, err := signer.LoadAuthConfig(ctx, o.authConfigPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load auth config: %w", err)
+		}
+		remoteSigner.SetAuthenticator(
+			signer.NewAuthenticator(&authConfig, o.authReplayWindow, logger),
+		)
+		logger.Infof("authentication enabled for %d clients", len(authConfig.Clients))
+	}
+
+	if o.tls.Enabled() {
+		tlsConfig, err := o.tls.ServerConfig()
+		if err != nil {
+			return nil, err
+		}
+		remoteSigner.SetTLSConfig(tlsConfig)
+	}
+
+	if o.policyPath != "" {
+		policyConfig, err := signer.LoadPolicyConfig(o.policyPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load signing policy: %w", err)
+		}
+		policy, err := signer.NewPolicy(&policyConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signing policy: %w", err)
+		}
+		remoteSigner.SetPolicy(policy)
+	} else {
+		logger.Warnf("no signing policy set, any transaction sent to the signer is signed")
+	}
+
+	if o.metrics {
+		remoteSigner.SetMetrics(signer.NewMetrics())
+	}
+
+	if o.allowHashOnly {
+		remoteSigner.SetAllowHashOnly(true)
+		logger.Warnf("hash-only signing enabled, hashes are signed without their transaction")
+	}
+
+	var closers []func() error
+	closeFn := func() {
+		for _, closer := range closers {
+			_ = closer()
+		}
+	}
+
+	if o.historyPath != "" {
+		history, err := signer.OpenHistory(o.historyPath)
+		if err != nil {
+			return nil, err
+		}
+		remoteSigner.SetHistory(history)
+		closers = append(closers, history.Close)
+	} else {
+		logger.Warnf("no signing history set, double signing protection is disabled")
+	}
+
+	if o.auditLogPath != "" {
+		auditLog, err := signer.OpenAuditLog(o.auditLogPath)
+		if err != nil {
+			closeFn()
+
+			return nil, err
+		}
+		remoteSigner.SetAuditLog(auditLog)
+		closers = append(closers, auditLog.Close)
+	}
+
+	return closeFn, nil
+}
+
+// Serves both the HTTP and gRPC interfaces of the signer, stopping both once the context
+// is done or either of them fails
+func serveWithGRPC(
+	ctx context.Context, remoteSigner *signer.Signer, address, grpcAddress string,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	served := make(chan error, 2) //nolint:mnd // One per interface
+	go func() { served <- remoteSigner.Serve(ctx, address) }()
+	go func() { served <- remoteSigner.ServeGRPC(ctx, grpcAddress) }()
+
+	err := <-served
+	cancel()
+
+	return errors.Join(err, <-served)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator"
+	configP "github.com/NethermindEth/starknet-staking-v2/validator/config"
+	signerP "github.com/NethermindEth/starknet-staking-v2/validator/signer"
+	"github.com/spf13/cobra"
+)
+
+func newAccountCommand(
+	config *configP.Config, snConfig *configP.StarknetConfig, logger *utils.ZapLogger,
+) *cobra.Command {
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "account",
+		Short: "Manage the operational account",
+	}
+	cmd.AddCommand(
+		newAccountDeployCommand(config, snConfig, logger),
+		newAccountRotateKeyCommand(config, snConfig, logger),
+	)
+
+	return cmd
+}
+
+func newAccountDeployCommand(
+	config *configP.Config, snConfig *configP.StarknetConfig, logger *utils.ZapLogger,
+) *cobra.Command {
+	var classFlags accountClassFlags
+	var acceptanceTimeout time.Duration
+
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Deploy the operational account",
+		Long: "Deploy the operational account, owned by the key of the configured signer. The" +
+			" operational address has to be the one the account type, class hash and salt" +
+			" give, and has to hold enough STRK to pay for the deployment. External signers" +
+			" need to support deploy account transactions. Waits until the deployment is" +
+			" accepted.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			accountClass, salt, err := classFlags.parse()
+			if err != nil {
+				return err
+			}
+			v, err := newUndeployedValidator(cmd, config, snConfig, logger)
+			if err != nil {
+				return err
+			}
+
+			txHash, err := v.DeployAccount(cmd.Context(), &accountClass, salt, acceptanceTimeout)
+			out := cmd.OutOrStdout()
+			if errors.Is(err, validator.ErrAccountDeployed) {
+				fmt.Fprintf(
+					out, "Operational account %s is already deployed\n",
+					config.Signer.OperationalAddress,
+				)
+
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Operational account deployed by transaction %s\n", txHash)
+
+			return nil
+		},
+	}
+	classFlags.add(cmd)
+	addAcceptanceTimeoutFlag(cmd, &acceptanceTimeout)
+
+	return cmd
+}
+
+// Adds the flag setting the time the transactions sent by the command have to be accepted
+func addAcceptanceTimeoutFlag(cmd *cobra.Command, acceptanceTimeout *time.Duration) {
+	cmd.Flags().DurationVar(
+		acceptanceTimeout,
+		"acceptance-timeout",
+		validator.DefaultAcceptanceTimeout,
+		"Time each transaction sent has to be accepted before the command fails",
+	)
+}
+
+// Flags choosing the class and salt the operational account is deployed with
+type accountClassFlags struct {
+	accountType  string
+	classHashHex string
+	saltHex      string
+}
+
+func (f *accountClassFlags) add(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&f.accountType,
+		"account-type",
+		string(signer.AccountTypeOpenZeppelin),
+		fmt.Sprintf(
+			"Account contract: %q or %q", signer.AccountTypeOpenZeppelin, signer.AccountTypeArgent,
+		),
+	)
+	cmd.Flags().StringVar(
+		&f.classHashHex,
+		"class-hash",
+		"",
+		"Class hash of the account contract. Defaults to OpenZeppelin v0.8.1 or Argent"+
+			" v0.4.0, depending on the account type",
+	)
+	cmd.Flags().StringVar(
+		&f.saltHex, "salt", "", "Salt of the account deployment. Defaults to the public key",
+	)
+}
+
+// Returns the account class and the salt, nil if it defaults to the public key
+func (f *accountClassFlags) parse() (signer.AccountClass, *felt.Felt, error) {
+	var classHash *felt.Felt
+	var err error
+	if f.classHashHex != "" {
+		classHash, err = new(felt.Felt).SetString(f.classHashHex)
+		if err != nil {
+			return signer.AccountClass{}, nil, fmt.Errorf(
+				"invalid class hash %q: %w", f.classHashHex, err,
+			)
+		}
+	}
+	accountClass, err := signer.NewAccountClass(signer.AccountType(f.accountType), classHash)
+	if err != nil {
+		return signer.AccountClass{}, nil, err
+	}
+
+	var salt *felt.Felt
+	if f.saltHex != "" {
+		salt, err = new(felt.Felt).SetString(f.saltHex)
+		if err != nil {
+			return signer.AccountClass{}, nil, fmt.Errorf("invalid salt %q: %w", f.saltHex, err)
+		}
+	}
+
+	return accountClass, salt, nil
+}
+
+// Creates the validator of an operational account which might not be deployed yet
+func newUndeployedValidator(
+	cmd *cobra.Command,
+	config *configP.Config,
+	snConfig *configP.StarknetConfig,
+	logger *utils.ZapLogger,
+) (validator.Validator, error) {
+	signerType, err := config.Signer.ResolveType()
+	if err != nil {
+		return validator.Validator{}, err
+	}
+	if signerType == configP.ExternalSigner {
+		// The account can't tell its public key before being deployed
+		if err := signerP.ResolveExternalPublicKey(&config.Signer); err != nil {
+			return validator.Validator{}, err
+		}
+	}
+
+	// Braavos accounts aren't deployed by the tool, so the transaction format
+	// they require never applies
+	return validator.New(cmd.Context(), config, snConfig, *logger, false)
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/keystore"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator"
+	configP "github.com/NethermindEth/starknet-staking-v2/validator/config"
+	signerP "github.com/NethermindEth/starknet-staking-v2/validator/signer"
+	"github.com/spf13/cobra"
+)
+
+func newAccountRotateKeyCommand(
+	config *configP.Config, snConfig *configP.StarknetConfig, logger *utils.ZapLogger,
+) *cobra.Command {
+	var accountType string
+	var newKeystorePath string
+	var newPasswordFile string
+	var acceptanceTimeout time.Duration
+
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "rotate-key",
+		Short: "Change the key of the operational account",
+		Long: "Change the public key of the operational account, keeping its address, to the" +
+			" key of a new keystore, e.g. one written by `signer keygen`. The change is signed" +
+			" with the current key by the configured signer. Once accepted, a dry-run attest" +
+			" signed with the new key is checked against the account, and a configured" +
+			" keystore is overwritten with the new key, encrypted with the same password.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := signer.AccountType(accountType).Validate(); err != nil {
+				return err
+			}
+			//nolint:exhaustruct // The env var is the one of the configured keystore
+			newPassword, err := (&keystore.PasswordSource{
+				File:   newPasswordFile,
+				Prompt: fmt.Sprintf("Enter password for keystore %s: ", newKeystorePath),
+			}).Read()
+			if err != nil {
+				return err
+			}
+			newPrivateKey, err := keystore.Open(newKeystorePath, newPassword)
+			if err != nil {
+				return fmt.Errorf("cannot open keystore %s: %w", newKeystorePath, err)
+			}
+			newKey := signer.NewMemoryKey(newPrivateKey)
+
+			signerType, err := config.Signer.ResolveType()
+			if err != nil {
+				return err
+			}
+			// Read before the key changes, so the keystore can be updated afterwards
+			var password string
+			if signerType == configP.KeystoreSigner {
+				password, err = (&keystore.PasswordSource{
+					File:   config.Signer.KeystorePasswordFile,
+					Env:    signerP.KeystorePasswordEnv,
+					Prompt: fmt.Sprintf("Enter password for keystore %s: ", config.Signer.Keystore),
+				}).Read()
+				if err != nil {
+					return err
+				}
+				if _, err := keystore.Open(config.Signer.Keystore, password); err != nil {
+					return fmt.Errorf("cannot open keystore %s: %w", config.Signer.Keystore, err)
+				}
+			}
+
+			// Braavos accounts take the query bit version of the fee estimation
+			braavos := signer.AccountType(accountType) == signer.AccountTypeBraavos
+			v, err := validator.New(cmd.Context(), config, snConfig, *logger, braavos)
+			if err != nil {
+				return err
+			}
+			txHash, err := v.ChangeAccountKey(
+				cmd.Context(), signer.AccountType(accountType), newKey, acceptanceTimeout,
+			)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			newPublicKey := new(felt.Felt).SetBigInt(newKey.PublicKey())
+			fmt.Fprintf(
+				out,
+				"Key of operational account %s changed to public key %s by transaction %s\n",
+				config.Signer.OperationalAddress,
+				newPublicKey,
+				txHash,
+			)
+
+			switch signerType {
+			case configP.KeystoreSigner:
+				if err := replaceKeystore(config.Signer.Keystore, newPrivateKey, password); err != nil {
+					return fmt.Errorf(
+						"cannot write the new key to keystore %s, use keystore %s instead: %w",
+						config.Signer.Keystore,
+						newKeystorePath,
+						err,
+					)
+				}
+				fmt.Fprintf(out, "Keystore %s now holds the new key\n", config.Signer.Keystore)
+			case configP.InternalSigner:
+				fmt.Fprintf(
+					out,
+					"Set the signer private key of the configuration to the new key, or its"+
+						" keystore to %s\n",
+					newKeystorePath,
+				)
+			case configP.ExternalSigner:
+				fmt.Fprintf(
+					out,
+					"Bind operational account %s to the new key in the keys of the external"+
+						" signer and restart it\n",
+					config.Signer.OperationalAddress,
+				)
+				if config.Signer.PublicKey != "" {
+					fmt.Fprintf(
+						out, "Set the signer public key of the configuration to %s\n", newPublicKey,
+					)
+				}
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(
+		&accountType,
+		"account-type",
+		string(signer.AccountTypeOpenZeppelin),
+		fmt.Sprintf(
+			"Account contract: %q, %q or %q",
+			signer.AccountTypeOpenZeppelin,
+			signer.AccountTypeArgent,
+			signer.AccountTypeBraavos,
+		),
+	)
+	cmd.Flags().StringVar(
+		&newKeystorePath, "new-keystore", "", "Path to the keystore holding the new key",
+	)
+	_ = cmd.MarkFlagRequired("new-keystore")
+	cmd.Flags().StringVar(
+		&newPasswordFile,
+		"new-keystore-password-file",
+		"",
+		"Path to a file holding the password of the new keystore. If not set, the password"+
+			" is prompted for",
+	)
+	addAcceptanceTimeoutFlag(cmd, &acceptanceTimeout)
+
+	return cmd
+}
+
+// Overwrites the keystore at the path with the private key, encrypted with the password
+func replaceKeystore(path string, privateKey *big.Int, password string) error {
+	ks, err := keystore.Encrypt(privateKey, password, keystore.StandardScryptN)
+	if err != nil {
+		return err
+	}
+	// Written aside first, so the keystore is never left half written
+	newPath := path + ".new"
+	if err := ks.Save(newPath); err != nil {
+		return err
+	}
+
+	return os.Rename(newPath, path)
+}
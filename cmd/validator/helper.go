@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/secret"
 	"github.com/NethermindEth/starknet-staking-v2/validator"
 	"github.com/NethermindEth/starknet-staking-v2/validator/config"
 	"github.com/NethermindEth/starknet-staking-v2/validator/types"
@@ -30,7 +31,7 @@ func tryNewValidator(
 			logger.Warnf(
 				"couldn't connect with RPC Provider at %s (attempts left: %s)."+
 					" Retrying in 3s...",
-				conf.Provider.HTTP,
+				secret.RedactURL(conf.Provider.HTTP.Reveal()),
 				retries.String(),
 			)
 			time.Sleep(3 * time.Second)
@@ -43,7 +44,8 @@ func tryNewValidator(
 		if retries.IsZero() {
 			return validator.Validator{},
 				fmt.Errorf(
-					"RPC provider unreachable at %s", conf.Provider.HTTP,
+					"RPC provider unreachable at %s",
+					secret.RedactURL(conf.Provider.HTTP.Reveal()),
 				)
 		}
 	}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -27,7 +28,7 @@ Validator program for Starknet stakers created by Nethermind
 `
 
 //nolint:funlen // It's the main function, so it's normal to be long
-func NewCommand() cobra.Command {
+func NewCommand() *cobra.Command {
 	var configPath string
 	var logLevelF string
 	var maxRetriesF string
@@ -42,7 +43,8 @@ func NewCommand() cobra.Command {
 	var snConfig configP.StarknetConfig
 	var logger utils.ZapLogger
 
-	preRunE := func(cmd *cobra.Command, args []string) error {
+	// Loads the configuration and the logger, checking the configuration with `check`
+	setup := func(cmd *cobra.Command, check func() error) error {
 		// Config takes the values from flags directly,
 		// then fills the missing ones from the env vars
 		configFromEnv := configP.FromEnv()
@@ -57,7 +59,10 @@ func NewCommand() cobra.Command {
 			}
 			config.Fill(&configFromFile)
 		}
-		if err := config.Check(); err != nil {
+		if err := config.ResolveSecrets(cmd.Context()); err != nil {
+			return err
+		}
+		if err := check(); err != nil {
 			return err
 		}
 
@@ -82,6 +87,19 @@ func NewCommand() cobra.Command {
 
 		return nil
 	}
+	preRunE := func(cmd *cobra.Command, args []string) error {
+		return setup(cmd, config.Check)
+	}
+	// Transactions signed offline are only built and sent through the http provider
+	providerPreRunE := func(cmd *cobra.Command, args []string) error {
+		return setup(cmd, func() error {
+			if config.Provider.HTTP.IsZero() {
+				return errors.New("http provider url not set in provider configuration")
+			}
+
+			return nil
+		})
+	}
 
 	run := func(cmd *cobra.Command, args []string) {
 		fmt.Printf(greeting, validator.Version)
@@ -100,6 +118,26 @@ func NewCommand() cobra.Command {
 			return
 		}
 
+		var funder *validator.Funder
+		if config.TopUp.Target > 0 {
+			funder, err = v.NewFunder(cmd.Context(), &config.TopUp, balanceThreshold, &snConfig)
+			if err != nil {
+				logger.Errorw("Failed to set up the top-up funder", "error", err)
+
+				return
+			}
+		}
+
+		var claimer *validator.RewardsClaimer
+		if config.Rewards.ClaimThreshold > 0 {
+			claimer, err = v.NewRewardsClaimer(cmd.Context(), &config.Rewards, &snConfig)
+			if err != nil {
+				logger.Errorw("Failed to set up the rewards claimer", "error", err)
+
+				return
+			}
+		}
+
 		var tracer metrics.Tracer = metrics.NewNoOpMetrics()
 		if metricsF {
 			// Create metrics server
@@ -131,7 +169,9 @@ func NewCommand() cobra.Command {
 		// Start validator in a goroutine
 		errCh := make(chan error, 1)
 		go func() {
-			err := v.Attest(cmd.Context(), maxRetries, balanceThreshold, tracer)
+			err := v.Attest(
+				cmd.Context(), maxRetries, balanceThreshold, funder, claimer, tracer,
+			)
 			if err != nil {
 				errCh <- err
 			}
@@ -150,47 +190,148 @@ func NewCommand() cobra.Command {
 	}
 
 	//nolint:exhaustruct // Only specifying used fields
-	cmd := cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "validator",
 		Short:   "Validator program for Starknet stakers created by Nethermind",
 		Version: validator.Version,
-		PreRunE: preRunE,
-		Run:     run,
-		Args:    cobra.NoArgs,
+		// Shared by the subcommands, which take the same configuration
+		PersistentPreRunE: preRunE,
+		Run:               run,
+		Args:              cobra.NoArgs,
 	}
+	cmd.AddCommand(
+		newAccountCommand(&config, &snConfig, &logger),
+		newRotateCommand(&config, &snConfig, &logger),
+		newTxCommand(&config, &logger, providerPreRunE),
+	)
 
 	// Config file path flag
-	cmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to JSON config file")
+	cmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "Path to JSON config file")
 
 	// Config provider flags
-	cmd.Flags().StringVar(&config.Provider.HTTP, "provider-http", "", "Provider http address")
-	cmd.Flags().StringVar(&config.Provider.WS, "provider-ws", "", "Provider ws address")
+	cmd.PersistentFlags().Var(&config.Provider.HTTP, "provider-http", "Provider http address")
+	cmd.PersistentFlags().Var(&config.Provider.WS, "provider-ws", "Provider ws address")
 
 	// Config signer flags
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
+		(*string)(&config.Signer.Type),
+		"signer-type",
+		"",
+		"Signer backend, one of 'internal', 'external' or 'keystore'. Inferred from the"+
+			" signer private key, keystore or url when not set",
+	)
+	cmd.PersistentFlags().StringVar(
 		&config.Signer.ExternalURL,
 		"signer-url",
 		"",
-		"Signer url address, required if using an external signer",
+		"Signer url address, required if using an external signer. Use"+
+			" 'unix:///path/to/socket' for a signer listening on a Unix domain socket, or"+
+			" 'grpc://host:port' for a signer served over gRPC",
 	)
-	cmd.Flags().StringVar(
-		&config.Signer.PrivKey, "signer-priv-key", "", "Signer private key, required for signing",
+	cmd.PersistentFlags().StringSliceVar(
+		&config.Signer.ExternalURLs,
+		"signer-urls",
+		nil,
+		"Comma separated urls of redundant external signers holding the same key, tried in"+
+			" order after --signer-url when the previous ones fail",
 	)
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
+		&config.Signer.RequestTimeout,
+		"signer-request-timeout",
+		"",
+		"Time each external signer has to answer a request before the next one is tried."+
+			" Defaults to 10s",
+	)
+	cmd.PersistentFlags().StringVar(
+		&config.Signer.HealthCheckInterval,
+		"signer-health-check-interval",
+		"",
+		"Time between health checks of redundant external signers. Defaults to 30s",
+	)
+	cmd.PersistentFlags().Var(
+		&config.Signer.PrivKey,
+		"signer-priv-key",
+		"Signer private key, required for internal signing. Prefer a reference such as"+
+			" 'file:/run/secrets/key' over the raw value, which is visible to other processes",
+	)
+	cmd.PersistentFlags().StringVar(
+		&config.Signer.Keystore,
+		"signer-keystore",
+		"",
+		"Path to an encrypted JSON keystore holding the signer private key",
+	)
+	cmd.PersistentFlags().StringVar(
+		&config.Signer.KeystorePasswordFile,
+		"signer-keystore-password-file",
+		"",
+		"Path to a file holding the keystore password. If not set, the password is read"+
+			" from the SIGNER_KEYSTORE_PASSWORD env var or prompted for",
+	)
+	cmd.PersistentFlags().StringVar(
 		&config.Signer.OperationalAddress,
 		"signer-op-address",
 		"",
 		"Signer operational address, required for attesting",
 	)
+	cmd.PersistentFlags().StringVar(
+		&config.Signer.AuthClientID,
+		"signer-auth-client-id",
+		"",
+		"Client ID used to authenticate against the external signer with a HMAC key."+
+			" Not supported by grpc:// signers",
+	)
+	cmd.PersistentFlags().Var(
+		&config.Signer.AuthToken,
+		"signer-auth-token",
+		"Bearer token used to authenticate against the external signer. The only"+
+			" authentication supported by grpc:// signers",
+	)
+	cmd.PersistentFlags().Var(
+		&config.Signer.AuthHMACKey,
+		"signer-auth-hmac-key",
+		"Key used to HMAC sign the requests sent to the external signer. Not supported by"+
+			" grpc:// signers, use --signer-auth-token instead",
+	)
+	cmd.PersistentFlags().StringVar(
+		&config.Signer.TLSCACert,
+		"signer-tls-ca-cert",
+		"",
+		"Path to a PEM CA bundle used to verify the external signer certificate",
+	)
+	cmd.PersistentFlags().StringVar(
+		&config.Signer.TLSClientCert,
+		"signer-tls-client-cert",
+		"",
+		"Path to the PEM client certificate presented to the external signer for mutual TLS",
+	)
+	cmd.PersistentFlags().StringVar(
+		&config.Signer.TLSClientKey,
+		"signer-tls-client-key",
+		"",
+		"Path to the PEM private key of the client certificate",
+	)
+	cmd.PersistentFlags().StringVar(
+		&config.Signer.TLSServerName,
+		"signer-tls-server-name",
+		"",
+		"Name the external signer certificate must be valid for. Defaults to the url host",
+	)
+	cmd.PersistentFlags().StringVar(
+		&config.Signer.PublicKey,
+		"signer-public-key",
+		"",
+		"Public key the external signer signatures are verified against before being sent."+
+			" Read from the operational account if not set",
+	)
 
 	// Config starknet flags
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
 		&snConfig.ContractAddresses.Attest,
 		"attest-contract-address",
 		"",
 		"Staking contract address. Defaults values are provided for Sepolia and Mainnet",
 	)
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
 		&snConfig.ContractAddresses.Staking,
 		"staking-contract-address",
 		"",
@@ -203,7 +344,7 @@ func NewCommand() cobra.Command {
 	cmd.Flags().StringVar(&metricsPortF, "metrics-port", "9090", "Port for the metric server")
 
 	// Other flags
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
 		&maxRetriesF,
 		"max-retries",
 		"infinite",
@@ -217,6 +358,40 @@ func NewCommand() cobra.Command {
 		"Triggers a warning if it detects the signer account (i.e. operational address)"+
 			" stark balance below the specified threshold. One stark equals 1 << 1e18.",
 	)
+	cmd.Flags().Float64Var(
+		&config.Rewards.ClaimThreshold,
+		"rewards-claim-threshold",
+		0,
+		"Claims the staker rewards once the unclaimed ones reach this amount of STRK, signed"+
+			" by the rewards claimer of the config file. Rewards are only monitored if not set",
+	)
+	cmd.Flags().Float64Var(
+		&config.TopUp.Target,
+		"top-up-target",
+		0,
+		"Balance, in STRK, the signer account is topped up to by the top-up funder of the"+
+			" config file once it falls below the balance threshold. Top-ups are disabled if"+
+			" not set",
+	)
+	cmd.Flags().Float64Var(
+		&config.TopUp.DailyCap,
+		"top-up-daily-cap",
+		0,
+		"Most STRK sent to the signer account by top-ups in 24 hours",
+	)
+	cmd.Flags().Uint64Var(
+		&config.TopUp.DailyTransfers,
+		"top-up-daily-transfers",
+		0,
+		"Most top-ups of the signer account sent in 24 hours. Not limited if not set",
+	)
+	cmd.Flags().StringVar(
+		&config.TopUp.StateFile,
+		"top-up-state-file",
+		"",
+		"Path to the file the sent top-ups are kept in, so the daily caps hold across"+
+			" restarts. Required with --top-up-target",
+	)
 	cmd.Flags().BoolVar(
 		&braavosAccount,
 		"braavos-account",
@@ -224,7 +399,7 @@ func NewCommand() cobra.Command {
 		"Changes the the transaction version format from 0x3 to 1<<128 + 0x3, required by"+
 			" Braavos accounts. Only applies for internal signing.",
 	)
-	cmd.Flags().StringVar(
+	cmd.PersistentFlags().StringVar(
 		&logLevelF, "log-level", utils.INFO.String(), "Options: trace, debug, info, warn, error.",
 	)
 
@@ -232,8 +407,11 @@ func NewCommand() cobra.Command {
 }
 
 func main() {
-	command := NewCommand()
-	if err := command.ExecuteContext(context.Background()); err != nil {
+	// Cancelled on interrupt, so the commands waiting for a transaction stop waiting
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := NewCommand().ExecuteContext(ctx)
+	stop()
+	if err != nil {
 		os.Exit(1)
 	}
 }
@@ -1,12 +1,11 @@
 package main_test
 
 import (
-	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
 
 	main "github.com/NethermindEth/starknet-staking-v2/cmd/validator"
-	"github.com/NethermindEth/starknet-staking-v2/validator/config"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 )
@@ -23,16 +22,15 @@ func TestNewCommand(t *testing.T) {
 	t.Run("PreRunE returns an error: config file verification fails", func(t *testing.T) {
 		command := main.NewCommand()
 
-		conf := config.Config{
-			Provider: config.Provider{
-				HTTP: "http://localhost:1234",
-				WS:   "ws://localhost:1235",
-			},
-			Signer: config.Signer{
-				OperationalAddress: "0x456",
-			},
-		}
-		filePath := createTemporaryConfigFile(t, &conf)
+		filePath := createTemporaryConfigFile(t, `{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "operationalAddress": "0x456"
+            }
+        }`)
 		defer deleteFile(t, filePath)
 
 		command.SetArgs([]string{"--config", filePath})
@@ -42,17 +40,16 @@ func TestNewCommand(t *testing.T) {
 	})
 
 	t.Run("Full command setup works with config file", func(t *testing.T) {
-		conf := config.Config{
-			Provider: config.Provider{
-				HTTP: "http://localhost:1234",
-				WS:   "ws://localhost:1235",
-			},
-			Signer: config.Signer{
-				OperationalAddress: "0x456",
-				PrivKey:            "0x123",
-			},
-		}
-		filePath := createTemporaryConfigFile(t, &conf)
+		filePath := createTemporaryConfigFile(t, `{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "operationalAddress": "0x456",
+                "privateKey": "0x123"
+            }
+        }`)
 		defer deleteFile(t, filePath)
 
 		command := newTestCommandWithArgs(t, "--config", filePath)
@@ -72,18 +69,74 @@ func TestNewCommand(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("Account deploy takes the validator configuration", func(t *testing.T) {
+		command := main.NewCommand()
+		command.SetArgs([]string{
+			"account", "deploy",
+			"--provider-http", "http://localhost:1234",
+			"--provider-ws", "ws://localhost:1234",
+			"--signer-op-address", "0x456",
+			"--signer-url", "http://localhost:5555",
+			"--account-type", "unknown",
+		})
+		err := command.ExecuteContext(t.Context())
+		require.ErrorContains(t, err, "unknown account type")
+	})
+
+	t.Run("Account key rotation opens the new keystore first", func(t *testing.T) {
+		passwordFile := filepath.Join(t.TempDir(), "password")
+		require.NoError(t, os.WriteFile(passwordFile, []byte("password"), 0o600))
+
+		command := main.NewCommand()
+		command.SetArgs([]string{
+			"account", "rotate-key",
+			"--provider-http", "http://localhost:1234",
+			"--provider-ws", "ws://localhost:1234",
+			"--signer-op-address", "0x456",
+			"--signer-url", "http://localhost:5555",
+			"--new-keystore", filepath.Join(t.TempDir(), "missing.json"),
+			"--new-keystore-password-file", passwordFile,
+		})
+		err := command.ExecuteContext(t.Context())
+		require.ErrorContains(t, err, "cannot open keystore")
+	})
+
+	t.Run("Operational address rotation takes the validator configuration", func(t *testing.T) {
+		command := main.NewCommand()
+		command.SetArgs([]string{
+			"rotate", "change",
+			"--provider-http", "http://localhost:1234",
+			"--provider-ws", "ws://localhost:1234",
+			"--signer-op-address", "0x123",
+			"--signer-url", "http://localhost:5555",
+			"--operational-address", "not an address",
+		})
+		err := command.ExecuteContext(t.Context())
+		require.ErrorContains(t, err, `invalid operational address "not an address"`)
+	})
+
+	t.Run("Offline transactions only take the provider configuration", func(t *testing.T) {
+		command := main.NewCommand()
+		command.SetArgs([]string{
+			"tx", "build",
+			"--provider-http", "http://localhost:1234",
+			"--sender", "0x456",
+			"--call", "0x123",
+			"--out", filepath.Join(t.TempDir(), "txn.json"),
+		})
+		err := command.ExecuteContext(t.Context())
+		require.ErrorContains(t, err, `invalid call "0x123"`)
+	})
+
 	t.Run("Full command setup works with config file and with flags", func(t *testing.T) {
-		conf, err := config.FromData([]byte(`{
+		filePath := createTemporaryConfigFile(t, `{
             "provider": {
                 "http": "http://localhost:1234"
             },
             "signer": {
                 "url": "http://localhost:5678"
             }
-        }`),
-		)
-		require.NoError(t, err)
-		filePath := createTemporaryConfigFile(t, &conf)
+        }`)
 		defer deleteFile(t, filePath)
 
 		command := newTestCommandWithArgs(
@@ -92,12 +145,40 @@ func TestNewCommand(t *testing.T) {
 			"--provider-ws", "ws://localhost:1234",
 			"--signer-op-address", "0x456",
 		)
-		err = command.ExecuteContext(t.Context())
+		err := command.ExecuteContext(t.Context())
 		require.NoError(t, err)
 	})
+	t.Run("PreRunE returns an error: secret reference cannot be resolved", func(t *testing.T) {
+		command := newTestCommandWithArgs(
+			t,
+			"--provider-http", "http://localhost:1234",
+			"--provider-ws", "ws://localhost:1234",
+			"--signer-op-address", "0x456",
+			"--signer-priv-key", "file:some inexisting file name",
+		)
+		err := command.ExecuteContext(t.Context())
+		require.ErrorContains(t, err, "resolving signer private key")
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Full command setup works with secrets from files", func(t *testing.T) {
+		privateKeyPath := filepath.Join(t.TempDir(), "private_key")
+		require.NoError(t, os.WriteFile(privateKeyPath, []byte("0x123\n"), 0o600))
+
+		command := newTestCommandWithArgs(
+			t,
+			"--provider-http", "http://localhost:1234",
+			"--provider-ws", "ws://localhost:1234",
+			"--signer-op-address", "0x456",
+			"--signer-priv-key", "file:"+privateKeyPath,
+		)
+		err := command.ExecuteContext(t.Context())
+		require.NoError(t, err)
+	})
+
 	t.Run("Priority order is flags -> env vars -> config file", func(t *testing.T) {
 		// Configuration through file
-		conf, err := config.FromData([]byte(`{
+		filePath := createTemporaryConfigFile(t, `{
             "provider": {
                 "http": "http://localhost:1234",
                 "ws": "ws://localhost:1235"
@@ -106,10 +187,7 @@ func TestNewCommand(t *testing.T) {
                 "url": "http://localhost:5678",
                 "operationalAddress": "0x456"
             }
-        }`),
-		)
-		require.NoError(t, err)
-		filePath := createTemporaryConfigFile(t, &conf)
+        }`)
 		defer deleteFile(t, filePath)
 
 		// Configuration through env var
@@ -120,24 +198,23 @@ func TestNewCommand(t *testing.T) {
 			"--config", filePath,
 			"--provider-http", "12",
 		)
-		err = command.ExecuteContext(t.Context())
+		err := command.ExecuteContext(t.Context())
 		// Very hard to test with the current architecture
 		// return in the future to fix it
 		require.NoError(t, err)
 	})
 }
 
-func createTemporaryConfigFile(t *testing.T, conf *config.Config) string {
+func createTemporaryConfigFile(t *testing.T, conf string) string {
 	t.Helper()
 
 	// Create a temporary file
 	tmpFile, err := os.CreateTemp(t.TempDir(), "config-*.json")
 	require.NoError(t, err)
 
-	// Encode the mocked config to JSON and write to the file
-	jsonData, err := json.Marshal(conf)
-	require.NoError(t, err)
-	_, err = tmpFile.Write(jsonData)
+	// Write the config to the file. The config is kept as raw JSON since
+	// marshalling a `config.Config` redacts its secrets
+	_, err = tmpFile.WriteString(conf)
 	require.NoError(t, err)
 	err = tmpFile.Close()
 	require.NoError(t, err)
@@ -151,7 +228,7 @@ func deleteFile(t *testing.T, filePath string) {
 }
 
 // Creates a new command with max retries set to 1
-func newTestCommandWithArgs(t *testing.T, args ...string) cobra.Command {
+func newTestCommandWithArgs(t *testing.T, args ...string) *cobra.Command {
 	t.Helper()
 
 	args = append(args, "--max-retries", "1")
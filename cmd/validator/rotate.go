@@ -0,0 +1,216 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/validator"
+	configP "github.com/NethermindEth/starknet-staking-v2/validator/config"
+	"github.com/NethermindEth/starknet-staking-v2/validator/types"
+	"github.com/spf13/cobra"
+)
+
+func newRotateCommand(
+	config *configP.Config, snConfig *configP.StarknetConfig, logger *utils.ZapLogger,
+) *cobra.Command {
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Rotate the operational address of a staker",
+		Long: "Rotate the operational address of a staker, in three steps:\n" +
+			"  1. `rotate declare`, with the signer of the new operational account, deploys" +
+			" the account if needed and declares it for the staker;\n" +
+			"  2. `rotate change`, with the signer of the staker account, changes the" +
+			" operational address of the staker once it attested in the current epoch;\n" +
+			"  3. `rotate verify`, with the signer of the new operational account, checks the" +
+			" staking contract resolves it to the staker.\n" +
+			"In each step, --signer-op-address is the address of the account signing it.",
+	}
+	cmd.AddCommand(
+		newRotateDeclareCommand(config, snConfig, logger),
+		newRotateChangeCommand(config, snConfig, logger),
+		newRotateVerifyCommand(config, snConfig, logger),
+	)
+
+	return cmd
+}
+
+func newRotateDeclareCommand(
+	config *configP.Config, snConfig *configP.StarknetConfig, logger *utils.ZapLogger,
+) *cobra.Command {
+	var stakerHex string
+	var classFlags accountClassFlags
+	var acceptanceTimeout time.Duration
+
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "declare",
+		Short: "Declare the new operational account for the staker",
+		Long: "Declare the operational account of the configured signer for the staker, which" +
+			" lets the staker change its operational address to it. The account is deployed" +
+			" first if it isn't yet, as done by `account deploy`.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			staker, err := parseAddress("staker address", stakerHex)
+			if err != nil {
+				return err
+			}
+			accountClass, salt, err := classFlags.parse()
+			if err != nil {
+				return err
+			}
+			v, err := newUndeployedValidator(cmd, config, snConfig, logger)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			deployHash, err := v.DeployAccount(
+				cmd.Context(), &accountClass, salt, acceptanceTimeout,
+			)
+			switch {
+			case errors.Is(err, validator.ErrAccountDeployed):
+			case err != nil:
+				return err
+			default:
+				fmt.Fprintf(out, "Operational account deployed by transaction %s\n", deployHash)
+			}
+
+			txHash, err := v.DeclareOperationalAddress(cmd.Context(), &staker, acceptanceTimeout)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(
+				out,
+				"Operational account %s declared for staker %s by transaction %s\n",
+				config.Signer.OperationalAddress,
+				&staker,
+				txHash,
+			)
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&stakerHex, "staker-address", "", "Address of the staker")
+	_ = cmd.MarkFlagRequired("staker-address")
+	classFlags.add(cmd)
+	addAcceptanceTimeoutFlag(cmd, &acceptanceTimeout)
+
+	return cmd
+}
+
+func newRotateChangeCommand(
+	config *configP.Config, snConfig *configP.StarknetConfig, logger *utils.ZapLogger,
+) *cobra.Command {
+	var operationalHex string
+	var now bool
+	var acceptanceTimeout time.Duration
+
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "change",
+		Short: "Change the operational address of the staker",
+		Long: "Change the operational address of the staker, the account of the configured" +
+			" signer, to a new operational account which declared it. The change waits" +
+			" until the staker attested in the current epoch, so the old validator" +
+			" attests for it and the new one only from the next epoch. Stop the old validator" +
+			" once the change is done.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			operationalAddress, err := parseAddress("operational address", operationalHex)
+			if err != nil {
+				return err
+			}
+			v, err := validator.New(cmd.Context(), config, snConfig, *logger, false)
+			if err != nil {
+				return err
+			}
+
+			txHash, err := v.ChangeOperationalAddress(
+				cmd.Context(), &operationalAddress, !now, acceptanceTimeout,
+			)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"Operational address of staker %s changed to %s by transaction %s\n",
+				config.Signer.OperationalAddress,
+				&operationalAddress,
+				txHash,
+			)
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(
+		&operationalHex, "operational-address", "", "New operational address of the staker",
+	)
+	_ = cmd.MarkFlagRequired("operational-address")
+	cmd.Flags().BoolVar(
+		&now,
+		"now",
+		false,
+		"Change the operational address without waiting for the staker to attest in the"+
+			" current epoch. The attestation of the current epoch is missed if the new"+
+			" validator doesn't make it",
+	)
+	addAcceptanceTimeoutFlag(cmd, &acceptanceTimeout)
+
+	return cmd
+}
+
+func newRotateVerifyCommand(
+	config *configP.Config, snConfig *configP.StarknetConfig, logger *utils.ZapLogger,
+) *cobra.Command {
+	var stakerHex string
+
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check the new operational address is the staker's",
+		Long: "Check the staking contract resolves the operational address of the configured" +
+			" signer to the staker, so the validator attests for it.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			staker, err := parseAddress("staker address", stakerHex)
+			if err != nil {
+				return err
+			}
+			v, err := validator.New(cmd.Context(), config, snConfig, *logger, false)
+			if err != nil {
+				return err
+			}
+
+			epochInfo, err := v.VerifyOperationalAddress(&staker)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"Operational address %s attests for staker %s from epoch %d\n",
+				config.Signer.OperationalAddress,
+				&staker,
+				epochInfo.EpochID,
+			)
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&stakerHex, "staker-address", "", "Address of the staker")
+	_ = cmd.MarkFlagRequired("staker-address")
+
+	return cmd
+}
+
+func parseAddress(name, value string) (types.Address, error) {
+	address, err := new(felt.Felt).SetString(value)
+	if err != nil {
+		return types.Address{}, fmt.Errorf("invalid %s %q: %w", name, value, err)
+	}
+
+	return types.Address(*address), nil
+}
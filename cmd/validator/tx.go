@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator"
+	configP "github.com/NethermindEth/starknet-staking-v2/validator/config"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/spf13/cobra"
+)
+
+func newTxCommand(
+	config *configP.Config,
+	logger *utils.ZapLogger,
+	preRunE func(cmd *cobra.Command, args []string) error,
+) *cobra.Command {
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "tx",
+		Short: "Build and send transactions signed offline",
+		Long: "Build and send transactions signed offline, e.g. staker operations signed by" +
+			" a key kept on a machine without network access. `tx build` writes the unsigned" +
+			" transaction to a file, `signer sign-file` signs it on the offline machine and" +
+			" `tx submit` sends it. Only the provider configuration is required.",
+		PersistentPreRunE: preRunE,
+	}
+	cmd.AddCommand(newTxBuildCommand(config, logger), newTxSubmitCommand(config, logger))
+
+	return cmd
+}
+
+func newTxBuildCommand(config *configP.Config, logger *utils.ZapLogger) *cobra.Command {
+	var senderHex string
+	var callsF []string
+	var outPath string
+
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "build",
+		Short: "Build an unsigned transaction to be signed offline",
+		Long: "Build an unsigned V3 invoke transaction of the calls, sent by the sender" +
+			" account, and write it to a file together with the chain id. The nonce and" +
+			" resource bounds are set from the current state of the account, so the" +
+			" transaction has to be signed and submitted before the account sends any other.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sender, err := new(felt.Felt).SetString(senderHex)
+			if err != nil {
+				return fmt.Errorf("invalid sender address %q: %w", senderHex, err)
+			}
+			calls := make([]rpc.InvokeFunctionCall, len(callsF))
+			for i, callF := range callsF {
+				if calls[i], err = parseCall(callF); err != nil {
+					return err
+				}
+			}
+
+			provider, err := validator.NewProvider(
+				cmd.Context(), config.Provider.HTTP.Reveal(), logger,
+			)
+			if err != nil {
+				return err
+			}
+			txn, err := validator.BuildOfflineTransaction(cmd.Context(), provider, sender, calls)
+			if err != nil {
+				return err
+			}
+			if err := txn.WriteFile(outPath); err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Unsigned transaction written to %s\n", outPath)
+			fmt.Fprintf(out, "Sender: %s\n", sender)
+			fmt.Fprintf(out, "Nonce: %s\n", txn.Transaction.Nonce)
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&senderHex, "sender", "", "Address of the account sending the transaction")
+	cmd.Flags().StringArrayVar(
+		&callsF,
+		"call",
+		nil,
+		"Call of the transaction, as '<contract address>:<function>[:<arg>,<arg>...]'."+
+			" Repeat it to send several calls in the same transaction",
+	)
+	cmd.Flags().StringVar(&outPath, "out", "", "Path of the file the transaction is written to")
+	for _, flag := range []string{"sender", "call", "out"} {
+		_ = cmd.MarkFlagRequired(flag)
+	}
+
+	return cmd
+}
+
+func newTxSubmitCommand(config *configP.Config, logger *utils.ZapLogger) *cobra.Command {
+	var acceptanceTimeout time.Duration
+
+	//nolint:exhaustruct // Only specifying used fields
+	cmd := &cobra.Command{
+		Use:   "submit <transaction file>",
+		Short: "Send a transaction signed offline",
+		Long: "Send the transaction of the file, once signed with `signer sign-file`, and" +
+			" wait until it is accepted.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txn, err := signer.ReadOfflineTransaction(args[0])
+			if err != nil {
+				return err
+			}
+
+			provider, err := validator.NewProvider(
+				cmd.Context(), config.Provider.HTTP.Reveal(), logger,
+			)
+			if err != nil {
+				return err
+			}
+			txHash, err := validator.SubmitOfflineTransaction(
+				cmd.Context(), provider, txn, acceptanceTimeout, logger,
+			)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Transaction %s accepted\n", txHash)
+
+			return nil
+		},
+	}
+	addAcceptanceTimeoutFlag(cmd, &acceptanceTimeout)
+
+	return cmd
+}
+
+// Parses a call given as `<contract address>:<function>[:<arg>,<arg>...]`
+func parseCall(callF string) (rpc.InvokeFunctionCall, error) {
+	// Contract address, function and calldata, which can be left out
+	const callParts = 3
+	parts := strings.SplitN(callF, ":", callParts)
+	if len(parts) < callParts-1 || parts[1] == "" {
+		return rpc.InvokeFunctionCall{}, fmt.Errorf(
+			"invalid call %q, expected '<contract address>:<function>[:<arg>,<arg>...]'",
+			callF,
+		)
+	}
+
+	contract, err := new(felt.Felt).SetString(parts[0])
+	if err != nil {
+		return rpc.InvokeFunctionCall{}, fmt.Errorf(
+			"invalid contract address %q: %w", parts[0], err,
+		)
+	}
+	calldata := []*felt.Felt{}
+	if len(parts) == callParts && parts[2] != "" {
+		for arg := range strings.SplitSeq(parts[2], ",") {
+			value, err := new(felt.Felt).SetString(strings.TrimSpace(arg))
+			if err != nil {
+				return rpc.InvokeFunctionCall{}, fmt.Errorf("invalid call argument %q: %w", arg, err)
+			}
+			calldata = append(calldata, value)
+		}
+	}
+
+	return rpc.InvokeFunctionCall{
+		ContractAddress: contract,
+		FunctionName:    parts[1],
+		CallData:        calldata,
+	}, nil
+}
@@ -0,0 +1,329 @@
+// Package keystore implements encrypted JSON keystores for Stark private keys.
+//
+// The format is the Web3 Secret Storage (version 3) one used by Starknet tooling such as
+// starkli and by Starknet wallets: the key is derived from a password with scrypt or pbkdf2,
+// the private key is encrypted with AES-128-CTR and authenticated with a keccak256 MAC.
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc/stark-curve/fr"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// Scrypt cost used when creating new keystores
+	StandardScryptN = 1 << 18
+	// Cheaper scrypt cost, only meant for tests or low powered devices
+	LightScryptN = 1 << 12
+
+	version       = 3
+	cipherName    = "aes-128-ctr"
+	scryptName    = "scrypt"
+	pbkdf2Name    = "pbkdf2"
+	pbkdf2PRF     = "hmac-sha256"
+	scryptR       = 8
+	scryptP       = 1
+	derivedKeyLen = 32
+	privateKeyLen = 32
+	saltLen       = 32
+)
+
+var ErrDecrypt = errors.New("could not decrypt keystore: wrong password or corrupted file")
+
+// Order of the Stark curve, which private keys are below
+var curveOrder = fr.Modulus()
+
+// Encrypted private key as stored on disk
+type Keystore struct {
+	Crypto  Crypto `json:"crypto"`
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+}
+
+type Crypto struct {
+	Cipher       string          `json:"cipher"`
+	CipherText   string          `json:"ciphertext"`
+	CipherParams CipherParams    `json:"cipherparams"`
+	KDF          string          `json:"kdf"`
+	KDFParams    json.RawMessage `json:"kdfparams"`
+	MAC          string          `json:"mac"`
+}
+
+type CipherParams struct {
+	IV string `json:"iv"`
+}
+
+type scryptParams struct {
+	DKLen int    `json:"dklen"`
+	N     int    `json:"n"`
+	P     int    `json:"p"`
+	R     int    `json:"r"`
+	Salt  string `json:"salt"`
+}
+
+type pbkdf2Params struct {
+	C     int    `json:"c"`
+	DKLen int    `json:"dklen"`
+	PRF   string `json:"prf"`
+	Salt  string `json:"salt"`
+}
+
+// Encrypts the private key with the given password. `scryptN` sets the scrypt
+// cost parameter, see `StandardScryptN`
+func Encrypt(privateKey *big.Int, password string, scryptN int) (Keystore, error) {
+	if err := checkPrivateKey(privateKey); err != nil {
+		return Keystore{}, err
+	}
+
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return Keystore{}, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return Keystore{}, err
+	}
+
+	derivedKey, err := scrypt.Key([]byte(password), salt, scryptN, scryptR, scryptP, derivedKeyLen)
+	if err != nil {
+		return Keystore{}, err
+	}
+
+	keyBytes := privateKey.FillBytes(make([]byte, privateKeyLen))
+	cipherText, err := aesCTR(derivedKey[:16], iv, keyBytes)
+	if err != nil {
+		return Keystore{}, err
+	}
+
+	kdfParams, err := json.Marshal(scryptParams{
+		DKLen: derivedKeyLen,
+		N:     scryptN,
+		P:     scryptP,
+		R:     scryptR,
+		Salt:  hex.EncodeToString(salt),
+	})
+	if err != nil {
+		return Keystore{}, err
+	}
+
+	id, err := newUUID()
+	if err != nil {
+		return Keystore{}, err
+	}
+
+	return Keystore{
+		Crypto: Crypto{
+			Cipher:       cipherName,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: CipherParams{IV: hex.EncodeToString(iv)},
+			KDF:          scryptName,
+			KDFParams:    kdfParams,
+			MAC:          hex.EncodeToString(mac(derivedKey, cipherText)),
+		},
+		ID:      id,
+		Version: version,
+	}, nil
+}
+
+// Decrypts the keystore, returning the private key
+func (ks *Keystore) Decrypt(password string) (*big.Int, error) {
+	if ks.Version != version {
+		return nil, fmt.Errorf("unsupported keystore version %d", ks.Version)
+	}
+	if ks.Crypto.Cipher != cipherName {
+		return nil, fmt.Errorf("unsupported keystore cipher %q", ks.Crypto.Cipher)
+	}
+
+	derivedKey, err := ks.deriveKey(password)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherText, err := hex.DecodeString(ks.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore ciphertext: %w", err)
+	}
+	expectedMAC, err := hex.DecodeString(ks.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore mac: %w", err)
+	}
+	if subtle.ConstantTimeCompare(mac(derivedKey, cipherText), expectedMAC) != 1 {
+		return nil, ErrDecrypt
+	}
+
+	iv, err := hex.DecodeString(ks.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore iv: %w", err)
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf(
+			"invalid keystore iv: %d bytes long, expected %d", len(iv), aes.BlockSize,
+		)
+	}
+	keyBytes, err := aesCTR(derivedKey[:16], iv, cipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(keyBytes), nil
+}
+
+// Checks the private key is a valid Stark key: above zero and below the curve order
+func checkPrivateKey(privateKey *big.Int) error {
+	if privateKey.Sign() <= 0 || privateKey.Cmp(curveOrder) >= 0 {
+		return errors.New("private key is out of range of the stark curve order")
+	}
+
+	return nil
+}
+
+func (ks *Keystore) deriveKey(password string) ([]byte, error) {
+	switch ks.Crypto.KDF {
+	case scryptName:
+		var params scryptParams
+		if err := json.Unmarshal(ks.Crypto.KDFParams, &params); err != nil {
+			return nil, fmt.Errorf("invalid scrypt parameters: %w", err)
+		}
+		if params.N <= 1 || params.N&(params.N-1) != 0 {
+			return nil, fmt.Errorf("invalid scrypt n %d: has to be a power of 2 above 1", params.N)
+		}
+		if params.R <= 0 || params.P <= 0 {
+			return nil, fmt.Errorf(
+				"invalid scrypt r %d and p %d: have to be above 0", params.R, params.P,
+			)
+		}
+		if err := checkDKLen(params.DKLen); err != nil {
+			return nil, err
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrypt salt: %w", err)
+		}
+
+		return scrypt.Key([]byte(password), salt, params.N, params.R, params.P, params.DKLen)
+	case pbkdf2Name:
+		var params pbkdf2Params
+		if err := json.Unmarshal(ks.Crypto.KDFParams, &params); err != nil {
+			return nil, fmt.Errorf("invalid pbkdf2 parameters: %w", err)
+		}
+		if params.PRF != pbkdf2PRF {
+			return nil, fmt.Errorf("unsupported pbkdf2 prf %q", params.PRF)
+		}
+		if params.C <= 0 {
+			return nil, fmt.Errorf("invalid pbkdf2 iteration count %d: has to be above 0", params.C)
+		}
+		if err := checkDKLen(params.DKLen); err != nil {
+			return nil, err
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pbkdf2 salt: %w", err)
+		}
+
+		return pbkdf2.Key(sha256.New, password, salt, params.C, params.DKLen)
+	default:
+		return nil, fmt.Errorf("unsupported keystore kdf %q", ks.Crypto.KDF)
+	}
+}
+
+// The derived key holds the AES key and the MAC key, 16 bytes each
+func checkDKLen(dkLen int) error {
+	if dkLen < derivedKeyLen {
+		return fmt.Errorf(
+			"invalid keystore dklen %d: has to be at least %d", dkLen, derivedKeyLen,
+		)
+	}
+
+	return nil
+}
+
+// Reads a keystore from a JSON file
+func Load(path string) (Keystore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Keystore{}, err
+	}
+
+	var ks Keystore
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return Keystore{}, fmt.Errorf("cannot parse keystore %s: %w", path, err)
+	}
+
+	return ks, nil
+}
+
+// Reads and decrypts the keystore at path, returning the private key
+func Open(path, password string) (*big.Int, error) {
+	ks, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ks.Decrypt(password)
+}
+
+// Writes the keystore as JSON to a new file only readable by its owner.
+// It never overwrites an existing file
+func (ks *Keystore) Save(path string) error {
+	data, err := json.MarshalIndent(ks, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	//nolint:mnd // Owner read & write permissions only
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
+
+func aesCTR(key, iv, input []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	output := make([]byte, len(input))
+	cipher.NewCTR(block, iv).XORKeyStream(output, input)
+
+	return output, nil
+}
+
+func mac(derivedKey, cipherText []byte) []byte {
+	hasher := sha3.NewLegacyKeccak256()
+	hasher.Write(bytes.Join([][]byte{derivedKey[16:32], cipherText}, nil))
+
+	return hasher.Sum(nil)
+}
+
+func newUUID() (string, error) {
+	id := make([]byte, 16) //nolint:mnd // UUID length in bytes
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	id[6] = (id[6] & 0x0f) | 0x40 // version 4
+	id[8] = (id[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]), nil
+}
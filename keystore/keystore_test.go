@@ -0,0 +1,243 @@
+package keystore_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/starknet-staking-v2/keystore"
+	"github.com/stretchr/testify/require"
+)
+
+// Test vectors taken from the Web3 Secret Storage definition
+const (
+	vectorPassword   = "testpassword"
+	vectorPrivateKey = "0x7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+	pbkdf2Vector     = `{
+        "crypto": {
+            "cipher": "aes-128-ctr",
+            "cipherparams": {"iv": "6087dab2f9fdbbfaddc31a909735c1e6"},
+            "ciphertext": "5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46",
+            "kdf": "pbkdf2",
+            "kdfparams": {
+                "c": 262144,
+                "dklen": 32,
+                "prf": "hmac-sha256",
+                "salt": "ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"
+            },
+            "mac": "517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"
+        },
+        "id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+        "version": 3
+    }`
+	scryptVector = `{
+        "crypto": {
+            "cipher": "aes-128-ctr",
+            "cipherparams": {"iv": "83dbcc02d8ccb40e466191a123791e0e"},
+            "ciphertext": "d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c",
+            "kdf": "scrypt",
+            "kdfparams": {
+                "dklen": 32,
+                "n": 262144,
+                "r": 1,
+                "p": 8,
+                "salt": "ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"
+            },
+            "mac": "2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"
+        },
+        "id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+        "version": 3
+    }`
+)
+
+func TestDecrypt(t *testing.T) {
+	expectedKey, ok := new(big.Int).SetString(vectorPrivateKey, 0)
+	require.True(t, ok)
+
+	for name, vector := range map[string]string{"pbkdf2": pbkdf2Vector, "scrypt": scryptVector} {
+		t.Run("Decrypt "+name+" keystore", func(t *testing.T) {
+			var ks keystore.Keystore
+			require.NoError(t, json.Unmarshal([]byte(vector), &ks))
+
+			key, err := ks.Decrypt(vectorPassword)
+			require.NoError(t, err)
+			require.Equal(t, expectedKey, key)
+		})
+	}
+
+	t.Run("Wrong password", func(t *testing.T) {
+		var ks keystore.Keystore
+		require.NoError(t, json.Unmarshal([]byte(pbkdf2Vector), &ks))
+
+		key, err := ks.Decrypt("wrong password")
+		require.Nil(t, key)
+		require.ErrorIs(t, err, keystore.ErrDecrypt)
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			vector string
+			modify func(ks *keystore.Keystore)
+			reason string
+		}{
+			{
+				name:   "Short iv",
+				vector: pbkdf2Vector,
+				modify: func(ks *keystore.Keystore) { ks.Crypto.CipherParams.IV = "6087dab2" },
+				reason: "invalid keystore iv: 4 bytes long, expected 16",
+			},
+			{
+				name:   "Short scrypt dklen",
+				vector: scryptVector,
+				modify: func(ks *keystore.Keystore) {
+					ks.Crypto.KDFParams = json.RawMessage(
+						`{"dklen": 16, "n": 262144, "r": 1, "p": 8, "salt": "ab0c"}`,
+					)
+				},
+				reason: "invalid keystore dklen 16: has to be at least 32",
+			},
+			{
+				name:   "Scrypt n not a power of 2",
+				vector: scryptVector,
+				modify: func(ks *keystore.Keystore) {
+					ks.Crypto.KDFParams = json.RawMessage(
+						`{"dklen": 32, "n": 1000, "r": 1, "p": 8, "salt": "ab0c"}`,
+					)
+				},
+				reason: "invalid scrypt n 1000: has to be a power of 2 above 1",
+			},
+			{
+				name:   "Scrypt without p",
+				vector: scryptVector,
+				modify: func(ks *keystore.Keystore) {
+					ks.Crypto.KDFParams = json.RawMessage(
+						`{"dklen": 32, "n": 1024, "r": 1, "salt": "ab0c"}`,
+					)
+				},
+				reason: "invalid scrypt r 1 and p 0: have to be above 0",
+			},
+			{
+				name:   "Short pbkdf2 dklen",
+				vector: pbkdf2Vector,
+				modify: func(ks *keystore.Keystore) {
+					ks.Crypto.KDFParams = json.RawMessage(
+						`{"c": 262144, "dklen": 20, "prf": "hmac-sha256", "salt": "ae3c"}`,
+					)
+				},
+				reason: "invalid keystore dklen 20: has to be at least 32",
+			},
+			{
+				name:   "Pbkdf2 without iterations",
+				vector: pbkdf2Vector,
+				modify: func(ks *keystore.Keystore) {
+					ks.Crypto.KDFParams = json.RawMessage(
+						`{"dklen": 32, "prf": "hmac-sha256", "salt": "ae3c"}`,
+					)
+				},
+				reason: "invalid pbkdf2 iteration count 0: has to be above 0",
+			},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				var ks keystore.Keystore
+				require.NoError(t, json.Unmarshal([]byte(test.vector), &ks))
+				test.modify(&ks)
+
+				key, err := ks.Decrypt(vectorPassword)
+				require.Nil(t, key)
+				require.ErrorContains(t, err, test.reason)
+			})
+		}
+	})
+
+	t.Run("Unsupported kdf", func(t *testing.T) {
+		var ks keystore.Keystore
+		require.NoError(t, json.Unmarshal([]byte(pbkdf2Vector), &ks))
+		ks.Crypto.KDF = "argon2"
+
+		key, err := ks.Decrypt(vectorPassword)
+		require.Nil(t, key)
+		require.ErrorContains(t, err, `unsupported keystore kdf "argon2"`)
+	})
+}
+
+func TestEncryptSaveAndOpen(t *testing.T) {
+	privateKey := big.NewInt(0x123)
+	path := filepath.Join(t.TempDir(), "keystore.json")
+
+	ks, err := keystore.Encrypt(privateKey, "password", keystore.LightScryptN)
+	require.NoError(t, err)
+	require.NoError(t, ks.Save(path))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// An existing keystore is never overwritten
+	require.ErrorIs(t, ks.Save(path), os.ErrExist)
+
+	key, err := keystore.Open(path, "password")
+	require.NoError(t, err)
+	require.Equal(t, privateKey, key)
+
+	_, err = keystore.Open(path, "other password")
+	require.ErrorIs(t, err, keystore.ErrDecrypt)
+}
+
+func TestEncryptKeyRange(t *testing.T) {
+	curveOrder, ok := new(big.Int).SetString(
+		"0x800000000000010ffffffffffffffffb781126dcae7b2321e66a241adc64d2f", 0,
+	)
+	require.True(t, ok)
+
+	for name, privateKey := range map[string]*big.Int{
+		"Zero key":             new(big.Int),
+		"Curve order":          curveOrder,
+		"Key above the order":  new(big.Int).Add(curveOrder, big.NewInt(1)),
+		"Negative private key": big.NewInt(-1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := keystore.Encrypt(privateKey, "password", keystore.LightScryptN)
+			require.ErrorContains(t, err, "out of range of the stark curve order")
+		})
+	}
+
+	t.Run("Largest key", func(t *testing.T) {
+		_, err := keystore.Encrypt(
+			new(big.Int).Sub(curveOrder, big.NewInt(1)), "password", keystore.LightScryptN,
+		)
+		require.NoError(t, err)
+	})
+}
+
+func TestPasswordSource(t *testing.T) {
+	t.Run("Password file takes precedence", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "password")
+		require.NoError(t, os.WriteFile(path, []byte("from file\n"), 0o600))
+		t.Setenv("TEST_KEYSTORE_PASSWORD", "from env")
+
+		source := keystore.PasswordSource{File: path, Env: "TEST_KEYSTORE_PASSWORD"}
+		password, err := source.Read()
+		require.NoError(t, err)
+		require.Equal(t, "from file", password)
+	})
+
+	t.Run("Password from environment variable", func(t *testing.T) {
+		t.Setenv("TEST_KEYSTORE_PASSWORD", "from env")
+
+		source := keystore.PasswordSource{Env: "TEST_KEYSTORE_PASSWORD"}
+		password, err := source.Read()
+		require.NoError(t, err)
+		require.Equal(t, "from env", password)
+	})
+
+	t.Run("No password source available", func(t *testing.T) {
+		source := keystore.PasswordSource{Env: "TEST_KEYSTORE_PASSWORD_UNSET"}
+		password, err := source.Read()
+		require.Empty(t, password)
+		require.ErrorContains(t, err, "keystore password not provided")
+	})
+}
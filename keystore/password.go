@@ -0,0 +1,79 @@
+package keystore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Where to read a keystore password from, in order of priority
+type PasswordSource struct {
+	// Path to a file holding the password
+	File string
+	// Name of the environment variable holding the password
+	Env string
+	// Message shown when the password has to be typed in. Prompting is only
+	// done when stdin is a terminal
+	Prompt string
+}
+
+// Returns the password from the first available source: the password file, the
+// environment variable or an interactive prompt
+func (p *PasswordSource) Read() (string, error) {
+	if p.File != "" {
+		data, err := os.ReadFile(p.File)
+		if err != nil {
+			return "", fmt.Errorf("cannot read password file: %w", err)
+		}
+
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	if p.Env != "" {
+		if password, ok := os.LookupEnv(p.Env); ok {
+			return password, nil
+		}
+	}
+
+	//nolint:gosec // File descriptors fit in an int
+	if p.Prompt != "" && term.IsTerminal(int(os.Stdin.Fd())) {
+		return PromptSecret(p.Prompt)
+	}
+
+	return "", errors.New(
+		"keystore password not provided: use a password file, an environment variable" +
+			" or run interactively",
+	)
+}
+
+// Reads a secret from the terminal without echoing it
+func PromptSecret(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	secret, err := term.ReadPassword(int(os.Stdin.Fd())) //nolint:gosec // Fds fit in an int
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("cannot read from terminal: %w", err)
+	}
+
+	return string(secret), nil
+}
+
+// Prompts twice for a new password making sure both entries match
+func PromptNewPassword() (string, error) {
+	password, err := PromptSecret("Enter keystore password: ")
+	if err != nil {
+		return "", err
+	}
+	confirmation, err := PromptSecret("Confirm keystore password: ")
+	if err != nil {
+		return "", err
+	}
+	if password != confirmation {
+		return "", errors.New("passwords do not match")
+	}
+
+	return password, nil
+}
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/NethermindEth/starknet-staking-v2/validator/signer (interfaces: Signer)
+// Source: github.com/NethermindEth/starknet-staking-v2/validator/signer (interfaces: Signer,AccountDeployer,Invoker)
 //
 // Generated by this command:
 //
-//	mockgen -destination=../../mocks/mock_signer.go -package=mocks github.com/NethermindEth/starknet-staking-v2/validator/signer Signer
+//	mockgen -destination=../../mocks/mock_signer.go -package=mocks github.com/NethermindEth/starknet-staking-v2/validator/signer Signer,AccountDeployer,Invoker
 //
 
 // Package mocks is a generated GoMock package.
@@ -87,18 +87,18 @@ func (mr *MockSignerMockRecorder) BuildAttestTransaction(blockHash any) *gomock.
 }
 
 // Call mocks base method.
-func (m *MockSigner) Call(call rpc.FunctionCall, blockId rpc.BlockID) ([]*felt.Felt, error) {
+func (m *MockSigner) Call(call rpc.FunctionCall, blockID rpc.BlockID) ([]*felt.Felt, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Call", call, blockId)
+	ret := m.ctrl.Call(m, "Call", call, blockID)
 	ret0, _ := ret[0].([]*felt.Felt)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Call indicates an expected call of Call.
-func (mr *MockSignerMockRecorder) Call(call, blockId any) *gomock.Call {
+func (mr *MockSignerMockRecorder) Call(call, blockID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockSigner)(nil).Call), call, blockId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockSigner)(nil).Call), call, blockID)
 }
 
 // EstimateFee mocks base method.
@@ -147,18 +147,18 @@ func (mr *MockSignerMockRecorder) Nonce() *gomock.Call {
 }
 
 // SignTransaction mocks base method.
-func (m *MockSigner) SignTransaction(txn *rpc.BroadcastInvokeTxnV3) (*rpc.BroadcastInvokeTxnV3, error) {
+func (m *MockSigner) SignTransaction(txn *rpc.BroadcastInvokeTxnV3, epochID uint64) (*rpc.BroadcastInvokeTxnV3, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SignTransaction", txn)
+	ret := m.ctrl.Call(m, "SignTransaction", txn, epochID)
 	ret0, _ := ret[0].(*rpc.BroadcastInvokeTxnV3)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SignTransaction indicates an expected call of SignTransaction.
-func (mr *MockSignerMockRecorder) SignTransaction(txn any) *gomock.Call {
+func (mr *MockSignerMockRecorder) SignTransaction(txn, epochID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignTransaction", reflect.TypeOf((*MockSigner)(nil).SignTransaction), txn)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignTransaction", reflect.TypeOf((*MockSigner)(nil).SignTransaction), txn, epochID)
 }
 
 // TransactionStatus mocks base method.
@@ -189,3 +189,465 @@ func (mr *MockSignerMockRecorder) ValidationContracts() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidationContracts", reflect.TypeOf((*MockSigner)(nil).ValidationContracts))
 }
+
+// MockAccountDeployer is a mock of AccountDeployer interface.
+type MockAccountDeployer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountDeployerMockRecorder
+	isgomock struct{}
+}
+
+// MockAccountDeployerMockRecorder is the mock recorder for MockAccountDeployer.
+type MockAccountDeployerMockRecorder struct {
+	mock *MockAccountDeployer
+}
+
+// NewMockAccountDeployer creates a new mock instance.
+func NewMockAccountDeployer(ctrl *gomock.Controller) *MockAccountDeployer {
+	mock := &MockAccountDeployer{ctrl: ctrl}
+	mock.recorder = &MockAccountDeployerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountDeployer) EXPECT() *MockAccountDeployerMockRecorder {
+	return m.recorder
+}
+
+// AccountDeployed mocks base method.
+func (m *MockAccountDeployer) AccountDeployed() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountDeployed")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountDeployed indicates an expected call of AccountDeployed.
+func (mr *MockAccountDeployerMockRecorder) AccountDeployed() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountDeployed", reflect.TypeOf((*MockAccountDeployer)(nil).AccountDeployed))
+}
+
+// AccountPublicKey mocks base method.
+func (m *MockAccountDeployer) AccountPublicKey() (*felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountPublicKey")
+	ret0, _ := ret[0].(*felt.Felt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountPublicKey indicates an expected call of AccountPublicKey.
+func (mr *MockAccountDeployerMockRecorder) AccountPublicKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountPublicKey", reflect.TypeOf((*MockAccountDeployer)(nil).AccountPublicKey))
+}
+
+// Address mocks base method.
+func (m *MockAccountDeployer) Address() *types.Address {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Address")
+	ret0, _ := ret[0].(*types.Address)
+	return ret0
+}
+
+// Address indicates an expected call of Address.
+func (mr *MockAccountDeployerMockRecorder) Address() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Address", reflect.TypeOf((*MockAccountDeployer)(nil).Address))
+}
+
+// BlockWithTxHashes mocks base method.
+func (m *MockAccountDeployer) BlockWithTxHashes(blockID rpc.BlockID) (any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockWithTxHashes", blockID)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockWithTxHashes indicates an expected call of BlockWithTxHashes.
+func (mr *MockAccountDeployerMockRecorder) BlockWithTxHashes(blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockWithTxHashes", reflect.TypeOf((*MockAccountDeployer)(nil).BlockWithTxHashes), blockID)
+}
+
+// BuildAttestTransaction mocks base method.
+func (m *MockAccountDeployer) BuildAttestTransaction(blockHash *types.BlockHash) (rpc.BroadcastInvokeTxnV3, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildAttestTransaction", blockHash)
+	ret0, _ := ret[0].(rpc.BroadcastInvokeTxnV3)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuildAttestTransaction indicates an expected call of BuildAttestTransaction.
+func (mr *MockAccountDeployerMockRecorder) BuildAttestTransaction(blockHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildAttestTransaction", reflect.TypeOf((*MockAccountDeployer)(nil).BuildAttestTransaction), blockHash)
+}
+
+// BuildDeployAccountTransaction mocks base method.
+func (m *MockAccountDeployer) BuildDeployAccountTransaction(classHash, salt *felt.Felt, constructorCalldata []*felt.Felt) (rpc.BroadcastDeployAccountTxnV3, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildDeployAccountTransaction", classHash, salt, constructorCalldata)
+	ret0, _ := ret[0].(rpc.BroadcastDeployAccountTxnV3)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuildDeployAccountTransaction indicates an expected call of BuildDeployAccountTransaction.
+func (mr *MockAccountDeployerMockRecorder) BuildDeployAccountTransaction(classHash, salt, constructorCalldata any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildDeployAccountTransaction", reflect.TypeOf((*MockAccountDeployer)(nil).BuildDeployAccountTransaction), classHash, salt, constructorCalldata)
+}
+
+// Call mocks base method.
+func (m *MockAccountDeployer) Call(call rpc.FunctionCall, blockID rpc.BlockID) ([]*felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Call", call, blockID)
+	ret0, _ := ret[0].([]*felt.Felt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Call indicates an expected call of Call.
+func (mr *MockAccountDeployerMockRecorder) Call(call, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockAccountDeployer)(nil).Call), call, blockID)
+}
+
+// DeployAccountTransaction mocks base method.
+func (m *MockAccountDeployer) DeployAccountTransaction(txn *rpc.BroadcastDeployAccountTxnV3) (rpc.AddDeployAccountTransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeployAccountTransaction", txn)
+	ret0, _ := ret[0].(rpc.AddDeployAccountTransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeployAccountTransaction indicates an expected call of DeployAccountTransaction.
+func (mr *MockAccountDeployerMockRecorder) DeployAccountTransaction(txn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployAccountTransaction", reflect.TypeOf((*MockAccountDeployer)(nil).DeployAccountTransaction), txn)
+}
+
+// EstimateDeployAccountFee mocks base method.
+func (m *MockAccountDeployer) EstimateDeployAccountFee(txn *rpc.BroadcastDeployAccountTxnV3) (rpc.FeeEstimation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateDeployAccountFee", txn)
+	ret0, _ := ret[0].(rpc.FeeEstimation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateDeployAccountFee indicates an expected call of EstimateDeployAccountFee.
+func (mr *MockAccountDeployerMockRecorder) EstimateDeployAccountFee(txn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateDeployAccountFee", reflect.TypeOf((*MockAccountDeployer)(nil).EstimateDeployAccountFee), txn)
+}
+
+// EstimateFee mocks base method.
+func (m *MockAccountDeployer) EstimateFee(txn *rpc.BroadcastInvokeTxnV3) (rpc.FeeEstimation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateFee", txn)
+	ret0, _ := ret[0].(rpc.FeeEstimation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateFee indicates an expected call of EstimateFee.
+func (mr *MockAccountDeployerMockRecorder) EstimateFee(txn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateFee", reflect.TypeOf((*MockAccountDeployer)(nil).EstimateFee), txn)
+}
+
+// InvokeTransaction mocks base method.
+func (m *MockAccountDeployer) InvokeTransaction(txn *rpc.BroadcastInvokeTxnV3) (rpc.AddInvokeTransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvokeTransaction", txn)
+	ret0, _ := ret[0].(rpc.AddInvokeTransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InvokeTransaction indicates an expected call of InvokeTransaction.
+func (mr *MockAccountDeployerMockRecorder) InvokeTransaction(txn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvokeTransaction", reflect.TypeOf((*MockAccountDeployer)(nil).InvokeTransaction), txn)
+}
+
+// Nonce mocks base method.
+func (m *MockAccountDeployer) Nonce() (*felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Nonce")
+	ret0, _ := ret[0].(*felt.Felt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Nonce indicates an expected call of Nonce.
+func (mr *MockAccountDeployerMockRecorder) Nonce() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Nonce", reflect.TypeOf((*MockAccountDeployer)(nil).Nonce))
+}
+
+// SignDeployAccountTransaction mocks base method.
+func (m *MockAccountDeployer) SignDeployAccountTransaction(txn *rpc.BroadcastDeployAccountTxnV3) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignDeployAccountTransaction", txn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SignDeployAccountTransaction indicates an expected call of SignDeployAccountTransaction.
+func (mr *MockAccountDeployerMockRecorder) SignDeployAccountTransaction(txn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignDeployAccountTransaction", reflect.TypeOf((*MockAccountDeployer)(nil).SignDeployAccountTransaction), txn)
+}
+
+// SignTransaction mocks base method.
+func (m *MockAccountDeployer) SignTransaction(txn *rpc.BroadcastInvokeTxnV3, epochID uint64) (*rpc.BroadcastInvokeTxnV3, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignTransaction", txn, epochID)
+	ret0, _ := ret[0].(*rpc.BroadcastInvokeTxnV3)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignTransaction indicates an expected call of SignTransaction.
+func (mr *MockAccountDeployerMockRecorder) SignTransaction(txn, epochID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignTransaction", reflect.TypeOf((*MockAccountDeployer)(nil).SignTransaction), txn, epochID)
+}
+
+// TransactionStatus mocks base method.
+func (m *MockAccountDeployer) TransactionStatus(transactionHash *felt.Felt) (*rpc.TxnStatusResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransactionStatus", transactionHash)
+	ret0, _ := ret[0].(*rpc.TxnStatusResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransactionStatus indicates an expected call of TransactionStatus.
+func (mr *MockAccountDeployerMockRecorder) TransactionStatus(transactionHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionStatus", reflect.TypeOf((*MockAccountDeployer)(nil).TransactionStatus), transactionHash)
+}
+
+// ValidationContracts mocks base method.
+func (m *MockAccountDeployer) ValidationContracts() *types.ValidationContracts {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidationContracts")
+	ret0, _ := ret[0].(*types.ValidationContracts)
+	return ret0
+}
+
+// ValidationContracts indicates an expected call of ValidationContracts.
+func (mr *MockAccountDeployerMockRecorder) ValidationContracts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidationContracts", reflect.TypeOf((*MockAccountDeployer)(nil).ValidationContracts))
+}
+
+// MockInvoker is a mock of Invoker interface.
+type MockInvoker struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvokerMockRecorder
+	isgomock struct{}
+}
+
+// MockInvokerMockRecorder is the mock recorder for MockInvoker.
+type MockInvokerMockRecorder struct {
+	mock *MockInvoker
+}
+
+// NewMockInvoker creates a new mock instance.
+func NewMockInvoker(ctrl *gomock.Controller) *MockInvoker {
+	mock := &MockInvoker{ctrl: ctrl}
+	mock.recorder = &MockInvokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvoker) EXPECT() *MockInvokerMockRecorder {
+	return m.recorder
+}
+
+// Address mocks base method.
+func (m *MockInvoker) Address() *types.Address {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Address")
+	ret0, _ := ret[0].(*types.Address)
+	return ret0
+}
+
+// Address indicates an expected call of Address.
+func (mr *MockInvokerMockRecorder) Address() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Address", reflect.TypeOf((*MockInvoker)(nil).Address))
+}
+
+// BlockWithTxHashes mocks base method.
+func (m *MockInvoker) BlockWithTxHashes(blockID rpc.BlockID) (any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockWithTxHashes", blockID)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockWithTxHashes indicates an expected call of BlockWithTxHashes.
+func (mr *MockInvokerMockRecorder) BlockWithTxHashes(blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockWithTxHashes", reflect.TypeOf((*MockInvoker)(nil).BlockWithTxHashes), blockID)
+}
+
+// BuildAttestTransaction mocks base method.
+func (m *MockInvoker) BuildAttestTransaction(blockHash *types.BlockHash) (rpc.BroadcastInvokeTxnV3, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildAttestTransaction", blockHash)
+	ret0, _ := ret[0].(rpc.BroadcastInvokeTxnV3)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuildAttestTransaction indicates an expected call of BuildAttestTransaction.
+func (mr *MockInvokerMockRecorder) BuildAttestTransaction(blockHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildAttestTransaction", reflect.TypeOf((*MockInvoker)(nil).BuildAttestTransaction), blockHash)
+}
+
+// BuildInvokeTransaction mocks base method.
+func (m *MockInvoker) BuildInvokeTransaction(calls []rpc.InvokeFunctionCall) (rpc.BroadcastInvokeTxnV3, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildInvokeTransaction", calls)
+	ret0, _ := ret[0].(rpc.BroadcastInvokeTxnV3)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuildInvokeTransaction indicates an expected call of BuildInvokeTransaction.
+func (mr *MockInvokerMockRecorder) BuildInvokeTransaction(calls any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildInvokeTransaction", reflect.TypeOf((*MockInvoker)(nil).BuildInvokeTransaction), calls)
+}
+
+// Call mocks base method.
+func (m *MockInvoker) Call(call rpc.FunctionCall, blockID rpc.BlockID) ([]*felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Call", call, blockID)
+	ret0, _ := ret[0].([]*felt.Felt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Call indicates an expected call of Call.
+func (mr *MockInvokerMockRecorder) Call(call, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockInvoker)(nil).Call), call, blockID)
+}
+
+// EstimateFee mocks base method.
+func (m *MockInvoker) EstimateFee(txn *rpc.BroadcastInvokeTxnV3) (rpc.FeeEstimation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateFee", txn)
+	ret0, _ := ret[0].(rpc.FeeEstimation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateFee indicates an expected call of EstimateFee.
+func (mr *MockInvokerMockRecorder) EstimateFee(txn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateFee", reflect.TypeOf((*MockInvoker)(nil).EstimateFee), txn)
+}
+
+// InvokeTransaction mocks base method.
+func (m *MockInvoker) InvokeTransaction(txn *rpc.BroadcastInvokeTxnV3) (rpc.AddInvokeTransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvokeTransaction", txn)
+	ret0, _ := ret[0].(rpc.AddInvokeTransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InvokeTransaction indicates an expected call of InvokeTransaction.
+func (mr *MockInvokerMockRecorder) InvokeTransaction(txn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvokeTransaction", reflect.TypeOf((*MockInvoker)(nil).InvokeTransaction), txn)
+}
+
+// Nonce mocks base method.
+func (m *MockInvoker) Nonce() (*felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Nonce")
+	ret0, _ := ret[0].(*felt.Felt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Nonce indicates an expected call of Nonce.
+func (mr *MockInvokerMockRecorder) Nonce() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Nonce", reflect.TypeOf((*MockInvoker)(nil).Nonce))
+}
+
+// SignInvokeTransaction mocks base method.
+func (m *MockInvoker) SignInvokeTransaction(txn *rpc.BroadcastInvokeTxnV3) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignInvokeTransaction", txn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SignInvokeTransaction indicates an expected call of SignInvokeTransaction.
+func (mr *MockInvokerMockRecorder) SignInvokeTransaction(txn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignInvokeTransaction", reflect.TypeOf((*MockInvoker)(nil).SignInvokeTransaction), txn)
+}
+
+// SignTransaction mocks base method.
+func (m *MockInvoker) SignTransaction(txn *rpc.BroadcastInvokeTxnV3, epochID uint64) (*rpc.BroadcastInvokeTxnV3, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignTransaction", txn, epochID)
+	ret0, _ := ret[0].(*rpc.BroadcastInvokeTxnV3)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignTransaction indicates an expected call of SignTransaction.
+func (mr *MockInvokerMockRecorder) SignTransaction(txn, epochID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignTransaction", reflect.TypeOf((*MockInvoker)(nil).SignTransaction), txn, epochID)
+}
+
+// TransactionStatus mocks base method.
+func (m *MockInvoker) TransactionStatus(transactionHash *felt.Felt) (*rpc.TxnStatusResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransactionStatus", transactionHash)
+	ret0, _ := ret[0].(*rpc.TxnStatusResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransactionStatus indicates an expected call of TransactionStatus.
+func (mr *MockInvokerMockRecorder) TransactionStatus(transactionHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionStatus", reflect.TypeOf((*MockInvoker)(nil).TransactionStatus), transactionHash)
+}
+
+// ValidationContracts mocks base method.
+func (m *MockInvoker) ValidationContracts() *types.ValidationContracts {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidationContracts")
+	ret0, _ := ret[0].(*types.ValidationContracts)
+	return ret0
+}
+
+// ValidationContracts indicates an expected call of ValidationContracts.
+func (mr *MockInvokerMockRecorder) ValidationContracts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidationContracts", reflect.TypeOf((*MockInvoker)(nil).ValidationContracts))
+}
@@ -0,0 +1,155 @@
+// Package secret provides a string type for sensitive configuration values.
+//
+// A Secret never prints its value: it is redacted in logs, `String()` output and JSON
+// dumps. Its value can be given literally or as a reference resolved at startup:
+//
+//	file:/run/secrets/private_key   the content of a file (e.g. a Docker secret)
+//	env:SOME_VAR                    the value of an environment variable
+//	exec:pass show staking/key      the standard output of a command
+//
+// The command of an `exec:` reference is split on white space and run without a shell, so
+// quotes and escapes are not supported and rejected.
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	Redacted = "[REDACTED]"
+
+	filePrefix = "file:"
+	envPrefix  = "env:"
+	execPrefix = "exec:"
+)
+
+var _ fmt.GoStringer = Secret{}
+
+type Secret struct {
+	value string
+}
+
+func New(value string) Secret {
+	return Secret{value: value}
+}
+
+// Returns the secret value. Its use should be limited to where the value is consumed
+func (s Secret) Reveal() string {
+	return s.value
+}
+
+func (s Secret) IsZero() bool {
+	return s.value == ""
+}
+
+func (s Secret) String() string {
+	if s.IsZero() {
+		return ""
+	}
+
+	return Redacted
+}
+
+func (s Secret) GoString() string {
+	return s.String()
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s *Secret) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &s.value)
+}
+
+// Sets the secret value. Together with `Type` it allows using secrets as command flags
+func (s *Secret) Set(value string) error {
+	s.value = value
+
+	return nil
+}
+
+func (s *Secret) Type() string {
+	return "string"
+}
+
+// Replaces a `file:`, `env:` or `exec:` reference by the value it points to. Literal
+// values are left untouched. Errors never include the secret value
+func (s *Secret) Resolve(ctx context.Context) error {
+	value, err := resolve(ctx, s.value)
+	if err != nil {
+		return err
+	}
+	s.value = value
+
+	return nil
+}
+
+func resolve(ctx context.Context, value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, filePrefix):
+		path := strings.TrimPrefix(value, filePrefix)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("cannot read secret from file: %w", err)
+		}
+
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case strings.HasPrefix(value, envPrefix):
+		name := strings.TrimPrefix(value, envPrefix)
+		envValue, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("cannot read secret from env var %s: variable not set", name)
+		}
+
+		return envValue, nil
+	case strings.HasPrefix(value, execPrefix):
+		command := strings.TrimPrefix(value, execPrefix)
+		if strings.ContainsAny(command, `"'\`) {
+			return "", errors.New(
+				"cannot read secret from command: quotes and backslashes are not supported," +
+					" wrap the command in a script instead",
+			)
+		}
+		args := strings.Fields(command)
+		if len(args) == 0 {
+			return "", errors.New("cannot read secret from command: no command given")
+		}
+
+		//nolint:gosec // Running the command set by the user is the purpose of `exec:`
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Stderr = os.Stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("cannot read secret from command %s: %w", args[0], err)
+		}
+
+		return strings.TrimRight(string(output), "\r\n"), nil
+	default:
+		return value, nil
+	}
+}
+
+// Returns the scheme and host of a URL, dropping everything else (user info, path and
+// query) where access keys are usually placed. Useful for logging endpoints.
+func RedactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return Redacted
+	}
+
+	redacted := parsed.Scheme + "://" + parsed.Host
+	hasPath := parsed.Path != "" && parsed.Path != "/"
+	if hasPath || parsed.RawQuery != "" || parsed.User != nil {
+		redacted += "/" + Redacted
+	}
+
+	return redacted
+}
@@ -0,0 +1,139 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// Kind of account contract, setting how its constructor takes the public key
+type AccountType string
+
+const (
+	AccountTypeOpenZeppelin AccountType = "oz"
+	AccountTypeArgent       AccountType = "argent"
+	// Braavos accounts are deployed as a base account, upgraded to the actual Braavos
+	// account by its constructor
+	AccountTypeBraavos AccountType = "braavos"
+)
+
+// Class hashes deployed when no other is given: OpenZeppelin v0.8.1, Argent v0.4.0 and
+// the Braavos base account
+var defaultAccountClassHashes = map[AccountType]string{
+	AccountTypeOpenZeppelin: "0x05b4b537eaa2399e3aa99c4e2e0208ebd6c71bc1467938cd52c798c601e43564",
+	AccountTypeArgent:       "0x036078334509b514626504edc9fb252328d1a240e4e948bef8d0c08dff45927f",
+	AccountTypeBraavos:      "0x013bfe114fb1cf405bfc3a7f8dbe2d91db146c17521d40dcf57e16d6b59fa8e6",
+}
+
+func (t AccountType) Validate() error {
+	if _, ok := defaultAccountClassHashes[t]; !ok {
+		return fmt.Errorf(
+			"unknown account type %q, expected %q, %q or %q",
+			t, AccountTypeOpenZeppelin, AccountTypeArgent, AccountTypeBraavos,
+		)
+	}
+
+	return nil
+}
+
+// Account contract class an account is deployed from
+type AccountClass struct {
+	Type      AccountType
+	ClassHash *felt.Felt
+}
+
+// Returns the class of the account type. The class hash defaults to the one of the
+// type if not set
+func NewAccountClass(accountType AccountType, classHash *felt.Felt) (AccountClass, error) {
+	if err := accountType.Validate(); err != nil {
+		return AccountClass{}, err
+	}
+	if classHash == nil {
+		var err error
+		classHash, err = new(felt.Felt).SetString(defaultAccountClassHashes[accountType])
+		if err != nil {
+			return AccountClass{}, err
+		}
+	}
+
+	return AccountClass{Type: accountType, ClassHash: classHash}, nil
+}
+
+// Arguments of the account constructor, owned by the public key
+func (c *AccountClass) ConstructorCalldata(publicKey *felt.Felt) []*felt.Felt {
+	if c.Type == AccountTypeArgent {
+		// Starknet owner signer, as variant 0 of the `Signer` enum, and no guardian, as
+		// variant 1 of `Option`
+		return []*felt.Felt{new(felt.Felt), publicKey, new(felt.Felt).SetUint64(1)}
+	}
+
+	return []*felt.Felt{publicKey}
+}
+
+// Counterfactual address of the account owned by the public key, deployed with the salt
+func (c *AccountClass) Address(publicKey, salt *felt.Felt) *felt.Felt {
+	return account.PrecomputeAccountAddress(salt, c.ClassHash, c.ConstructorCalldata(publicKey))
+}
+
+// Calls changing the public key of the account, of the given type, from the old key to the
+// new one. The calls have to be sent by the account itself
+func KeyChangeCalls(
+	ctx context.Context,
+	accountType AccountType,
+	accountAddress, chainID, oldPublicKey *felt.Felt,
+	newKey KeyBackend,
+) ([]rpc.InvokeFunctionCall, error) {
+	newPublicKey := new(felt.Felt).SetBigInt(newKey.PublicKey())
+
+	switch accountType {
+	case AccountTypeOpenZeppelin, AccountTypeBraavos:
+		// Braavos accounts take their new Stark key by the same entrypoint
+		return []rpc.InvokeFunctionCall{{
+			ContractAddress: accountAddress,
+			FunctionName:    "set_public_key",
+			CallData:        []*felt.Felt{newPublicKey},
+		}}, nil
+	case AccountTypeArgent:
+		// The new owner proves it holds its key by signing the change. As in
+		// `assert_valid_new_owner_signature` of Argent v0.4.0, it is the Pedersen hash, on
+		// the array length, of the `change_owner` selector, the chain id, the account
+		// address and the guid of the old owner
+		oldOwnerGUID := curve.Poseidon(
+			new(felt.Felt).SetBytes([]byte("Starknet Signer")), oldPublicKey,
+		)
+		msgHash := curve.PedersenArray(
+			utils.GetSelectorFromNameFelt("change_owner"), chainID, accountAddress, oldOwnerGUID,
+		)
+		r, s, err := newKey.Sign(ctx, msgHash.BigInt(new(big.Int)))
+		if err != nil {
+			return nil, fmt.Errorf("cannot sign the owner change with the new key: %w", err)
+		}
+
+		// Signature of a Starknet signer, as variant 0 of the `SignerSignature` enum
+		return []rpc.InvokeFunctionCall{{
+			ContractAddress: accountAddress,
+			FunctionName:    "change_owner",
+			CallData: []*felt.Felt{
+				new(felt.Felt),
+				newPublicKey,
+				new(felt.Felt).SetBigInt(r),
+				new(felt.Felt).SetBigInt(s),
+			},
+		}}, nil
+	default:
+		return nil, accountType.Validate()
+	}
+}
+
+// Address of the account deployed by the transaction
+func deployedAddress(txn *rpc.DeployAccountTxnV3) *felt.Felt {
+	return account.PrecomputeAccountAddress(
+		txn.ContractAddressSalt, txn.ClassHash, txn.ConstructorCalldata,
+	)
+}
@@ -0,0 +1,108 @@
+package signer_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountClass(t *testing.T) {
+	publicKey := utils.HexToFelt(t, "0x566d69d8c99f62bc71118399bab25c1f03719463eab8d6a444cd11ece131616")
+	salt := utils.HexToFelt(t, "0xdeadbeef")
+
+	t.Run("OpenZeppelin account", func(t *testing.T) {
+		class, err := signer.NewAccountClass(signer.AccountTypeOpenZeppelin, nil)
+		require.NoError(t, err)
+		require.Equal(
+			t,
+			utils.HexToFelt(t, "0x05b4b537eaa2399e3aa99c4e2e0208ebd6c71bc1467938cd52c798c601e43564"),
+			class.ClassHash,
+		)
+		require.Equal(t, []*felt.Felt{publicKey}, class.ConstructorCalldata(publicKey))
+		require.Equal(
+			t,
+			account.PrecomputeAccountAddress(salt, class.ClassHash, []*felt.Felt{publicKey}),
+			class.Address(publicKey, salt),
+		)
+	})
+
+	t.Run("Argent account without guardian", func(t *testing.T) {
+		class, err := signer.NewAccountClass(signer.AccountTypeArgent, nil)
+		require.NoError(t, err)
+		require.Equal(
+			t,
+			[]*felt.Felt{new(felt.Felt), publicKey, new(felt.Felt).SetUint64(1)},
+			class.ConstructorCalldata(publicKey),
+		)
+	})
+
+	t.Run("Braavos account with a given class hash", func(t *testing.T) {
+		classHash := utils.HexToFelt(t, "0x123")
+		class, err := signer.NewAccountClass(signer.AccountTypeBraavos, classHash)
+		require.NoError(t, err)
+		require.Equal(t, classHash, class.ClassHash)
+		require.Equal(t, []*felt.Felt{publicKey}, class.ConstructorCalldata(publicKey))
+	})
+
+	t.Run("Unknown account type", func(t *testing.T) {
+		_, err := signer.NewAccountClass("other", nil)
+		require.ErrorContains(t, err, `unknown account type "other"`)
+	})
+}
+
+func TestKeyChangeCalls(t *testing.T) {
+	accountAddress := utils.HexToFelt(t, "0xabc")
+	chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+	oldPublicKey := utils.HexToFelt(t, "0x111")
+	newKey := signer.NewMemoryKey(big.NewInt(0x123))
+	newPublicKey := utils.HexToFelt(
+		t, "0x566d69d8c99f62bc71118399bab25c1f03719463eab8d6a444cd11ece131616",
+	)
+
+	t.Run("OpenZeppelin account sets its public key", func(t *testing.T) {
+		calls, err := signer.KeyChangeCalls(
+			t.Context(), signer.AccountTypeOpenZeppelin, accountAddress, chainID, oldPublicKey, newKey,
+		)
+		require.NoError(t, err)
+		require.Len(t, calls, 1)
+		require.Equal(t, accountAddress, calls[0].ContractAddress)
+		require.Equal(t, "set_public_key", calls[0].FunctionName)
+		require.Equal(t, []*felt.Felt{newPublicKey}, calls[0].CallData)
+	})
+
+	t.Run("Argent account changes its owner with a signature of the new key", func(t *testing.T) {
+		calls, err := signer.KeyChangeCalls(
+			t.Context(), signer.AccountTypeArgent, accountAddress, chainID, oldPublicKey, newKey,
+		)
+		require.NoError(t, err)
+		require.Len(t, calls, 1)
+		require.Equal(t, "change_owner", calls[0].FunctionName)
+		callData := calls[0].CallData
+		require.Len(t, callData, 4)
+		require.True(t, callData[0].IsZero())
+		require.Equal(t, newPublicKey, callData[1])
+
+		// Pedersen hash of the `change_owner` selector, the chain id, the account address
+		// and the guid of the old owner, 0x5febad5...a0a839, on the array length
+		msgHash := utils.HexToFelt(
+			t, "0x4255f526cd8603b4dd08a58297d09a4d06badcbdedbb9812232bb07c0374000",
+		)
+		requireValidSignature(t, msgHash, callData[2], callData[3], 0x123)
+	})
+
+	t.Run("Braavos account sets its public key", func(t *testing.T) {
+		calls, err := signer.KeyChangeCalls(
+			t.Context(), signer.AccountTypeBraavos, accountAddress, chainID, oldPublicKey, newKey,
+		)
+		require.NoError(t, err)
+		require.Len(t, calls, 1)
+		require.Equal(t, accountAddress, calls[0].ContractAddress)
+		require.Equal(t, "set_public_key", calls[0].FunctionName)
+		require.Equal(t, []*felt.Felt{newPublicKey}, calls[0].CallData)
+	})
+}
@@ -0,0 +1,403 @@
+package signer
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+const (
+	ReasonUnauthenticated ReasonCode = "UNAUTHENTICATED"
+	ReasonInvalidRequest  ReasonCode = "INVALID_REQUEST"
+)
+
+type AuditDecision string
+
+const (
+	DecisionSigned  AuditDecision = "signed"
+	DecisionRefused AuditDecision = "refused"
+	// The request was valid but an internal error prevented signing it
+	DecisionFailed AuditDecision = "failed"
+)
+
+// Hash used as the previous hash of the first entry of an audit log
+var genesisAuditHash = strings.Repeat("0", sha256.Size*2) //nolint:mnd // Hex chars per byte
+
+// Suffix of the file next to the audit log holding the hash of its last entry. Used to
+// detect the log was truncated
+const auditHeadSuffix = ".head"
+
+// A call of the transaction as decoded from its calldata
+type AuditCall struct {
+	ContractAddress *felt.Felt   `json:"contractAddress"`
+	Selector        *felt.Felt   `json:"selector"`
+	Function        string       `json:"function,omitempty"`
+	Calldata        []*felt.Felt `json:"calldata"`
+}
+
+// Record of a single signing request. `Hash` is the SHA-256 of the entry serialised
+// without it, which includes the hash of the previous entry
+type AuditEntry struct {
+	Seq           uint64     `json:"seq"`
+	Timestamp     time.Time  `json:"timestamp"`
+	Client        string     `json:"client,omitempty"`
+	RemoteAddress string     `json:"remoteAddress,omitempty"`
+	ChainID       *felt.Felt `json:"chainId,omitempty"`
+	Sender        *felt.Felt `json:"sender,omitempty"`
+	Nonce         *felt.Felt `json:"nonce,omitempty"`
+	// Class of the account deployed, for deploy account transactions
+	ClassHash   *felt.Felt    `json:"classHash,omitempty"`
+	TxHash      *felt.Felt    `json:"txHash,omitempty"`
+	Calls       []AuditCall   `json:"calls,omitempty"`
+	RawCalldata []*felt.Felt  `json:"rawCalldata,omitempty"`
+	Decision    AuditDecision `json:"decision"`
+	ReasonCode  ReasonCode    `json:"reasonCode,omitempty"`
+	Reason      string        `json:"reason,omitempty"`
+	PrevHash    string        `json:"prevHash"`
+	Hash        string        `json:"hash,omitempty"`
+}
+
+func (e *AuditEntry) computeHash() (string, error) {
+	unhashed := *e
+	unhashed.Hash = ""
+	data, err := json.Marshal(&unhashed)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Append-only, hash-chained log of every signing request
+type AuditLog struct {
+	mu       sync.Mutex
+	file     *os.File
+	headPath string
+	// Size of the log up to its last entry, a failed write being truncated back to it
+	size     int64
+	nextSeq  uint64
+	lastHash string
+	// Names of the functions shown for the calls of the transactions
+	knownSelectors map[felt.Felt]string
+	now            func() time.Time
+}
+
+// Opens the audit log at path, creating it if it doesn't exist. The existing entries
+// are verified before appending new ones.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	lastEntry, err := VerifyAuditLog(path, "")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("audit log %s failed verification: %w", path, err)
+	}
+
+	//nolint:mnd // Only readable by the signer
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open audit log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return nil, fmt.Errorf("cannot open audit log: %w", err)
+	}
+
+	auditLog := &AuditLog{
+		mu:       sync.Mutex{},
+		file:     file,
+		headPath: path + auditHeadSuffix,
+		size:     info.Size(),
+		nextSeq:  0,
+		lastHash: genesisAuditHash,
+		knownSelectors: map[felt.Felt]string{
+			attestSelector(): "attest",
+		},
+		now: time.Now,
+	}
+	if lastEntry != nil {
+		auditLog.nextSeq = lastEntry.Seq + 1
+		auditLog.lastHash = lastEntry.Hash
+	}
+
+	return auditLog, nil
+}
+
+func (l *AuditLog) Close() error {
+	return l.file.Close()
+}
+
+// Builds the entry recording the outcome of a signing request. `req` and `txnHash` are nil
+// when they couldn't be obtained from the request.
+func (l *AuditLog) newEntry(
+	source requestSource, req *Request, txnHash *felt.Felt, signErr error,
+) AuditEntry {
+	//nolint:exhaustruct // Seq and hashes are set when appending the entry
+	entry := AuditEntry{
+		Timestamp:     l.now().UTC(),
+		Client:        source.client,
+		RemoteAddress: source.remoteAddress,
+		TxHash:        txnHash,
+		Decision:      DecisionSigned,
+	}
+
+	if req != nil {
+		entry.ChainID = req.ChainID
+		entry.Sender = req.sender()
+		if req.DeployAccountTxnV3 != nil {
+			entry.Nonce = req.DeployAccountTxnV3.Nonce
+			entry.ClassHash = req.DeployAccountTxnV3.ClassHash
+		}
+		if req.InvokeTxnV3 != nil {
+			entry.Nonce = req.Nonce
+			if calls, ok := decodeCalls(req.Calldata, l.knownSelectors); ok {
+				entry.Calls = calls
+			} else {
+				entry.RawCalldata = req.Calldata
+			}
+		}
+	}
+
+	if signErr != nil {
+		if policyViolation, ok := AsPolicyViolation(signErr); ok {
+			entry.Decision = DecisionRefused
+			entry.ReasonCode = policyViolation.Code
+			entry.Reason = policyViolation.Reason
+		} else {
+			entry.Decision = DecisionFailed
+			entry.Reason = signErr.Error()
+		}
+	}
+
+	return entry
+}
+
+// Chains the entry to the previous one and durably appends it to the log
+func (l *AuditLog) Append(entry *AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry.Seq = l.nextSeq
+	entry.PrevHash = l.lastHash
+	entryHash, err := entry.computeHash()
+	if err != nil {
+		return err
+	}
+	entry.Hash = entryHash
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if _, err := l.file.Write(data); err != nil {
+		// Don't leave a partial entry the next ones would be appended to
+		_ = l.file.Truncate(l.size)
+
+		return fmt.Errorf("cannot write audit log: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		_ = l.file.Truncate(l.size)
+
+		return fmt.Errorf("cannot write audit log: %w", err)
+	}
+
+	// The entry is in the log, so the next one is chained to it even if the head isn't
+	// updated. A head one entry behind is repaired when the log is verified
+	l.size += int64(len(data))
+	l.nextSeq++
+	l.lastHash = entryHash
+
+	return writeAuditHead(l.headPath, entry)
+}
+
+func writeAuditHead(headPath string, entry *AuditEntry) error {
+	tmpPath := headPath + ".tmp"
+	head := fmt.Sprintf("%d %s\n", entry.Seq, entry.Hash)
+	//nolint:mnd // Only readable by the signer
+	if err := os.WriteFile(tmpPath, []byte(head), 0o600); err != nil {
+		return fmt.Errorf("cannot write audit log head: %w", err)
+	}
+	if err := os.Rename(tmpPath, headPath); err != nil {
+		return fmt.Errorf("cannot write audit log head: %w", err)
+	}
+
+	return nil
+}
+
+// Checks every entry of the audit log is unmodified and chained to the previous one. The
+// last entry is checked against the head file written next to the log and, if given,
+// against `expectedHead`, detecting truncations. Returns the last entry, nil if the log
+// is empty.
+// The log is repaired from an interrupted append: a trailing partial entry is removed,
+// and a head one entry behind the last one is updated.
+func VerifyAuditLog(path, expectedHead string) (*AuditEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	lastEntry, size, err := verifyAuditEntries(file)
+	if err != nil {
+		return nil, err
+	}
+
+	lastHash := genesisAuditHash
+	if lastEntry != nil {
+		lastHash = lastEntry.Hash
+	}
+
+	// The head is written after the entry, so it is one entry behind if the append was
+	// interrupted in between
+	repairHead := false
+	head, err := os.ReadFile(path + auditHeadSuffix)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if lastEntry != nil && lastEntry.Seq != 0 {
+			return nil, errors.New("audit log head file is missing")
+		}
+		repairHead = lastEntry != nil
+	case err != nil:
+		return nil, fmt.Errorf("cannot read audit log head: %w", err)
+	default:
+		var headSeq uint64
+		var headHash string
+		if _, err := fmt.Sscanf(string(head), "%d %s", &headSeq, &headHash); err != nil {
+			return nil, fmt.Errorf("malformed audit log head: %w", err)
+		}
+		isLast := lastEntry != nil && headSeq == lastEntry.Seq && headHash == lastHash
+		repairHead = lastEntry != nil && headSeq+1 == lastEntry.Seq &&
+			headHash == lastEntry.PrevHash
+		if !isLast && !repairHead {
+			return nil, fmt.Errorf(
+				"audit log was truncated: head is entry %d with hash %s", headSeq, headHash,
+			)
+		}
+	}
+
+	if expectedHead != "" && expectedHead != lastHash {
+		return nil, fmt.Errorf(
+			"audit log last hash %s doesn't match the expected %s", lastHash, expectedHead,
+		)
+	}
+
+	// Only repaired once the log is known to be valid
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read audit log: %w", err)
+	}
+	if info.Size() != size {
+		if err := os.Truncate(path, size); err != nil {
+			return nil, fmt.Errorf("cannot remove the partial entry of the audit log: %w", err)
+		}
+	}
+	if repairHead {
+		if err := writeAuditHead(path+auditHeadSuffix, lastEntry); err != nil {
+			return nil, err
+		}
+	}
+
+	return lastEntry, nil
+}
+
+// Returns the last entry and the size of the log up to it. A trailing line without its
+// newline is the partial entry of an interrupted append, and is left out.
+func verifyAuditEntries(reader io.Reader) (*AuditEntry, int64, error) {
+	var lastEntry *AuditEntry
+	var size int64
+	prevHash := genesisAuditHash
+
+	bufReader := bufio.NewReader(reader)
+	for line := 1; ; line++ {
+		data, err := bufReader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("cannot read audit log: %w", err)
+		}
+
+		var entry AuditEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, 0, fmt.Errorf("line %d: malformed entry: %w", line, err)
+		}
+
+		expectedSeq := uint64(0)
+		if lastEntry != nil {
+			expectedSeq = lastEntry.Seq + 1
+		}
+		if entry.Seq != expectedSeq {
+			return nil, 0, fmt.Errorf(
+				"line %d: expected entry %d, got entry %d", line, expectedSeq, entry.Seq,
+			)
+		}
+		if entry.PrevHash != prevHash {
+			return nil, 0, fmt.Errorf("line %d: entry is not chained to the previous one", line)
+		}
+		entryHash, err := entry.computeHash()
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		if entry.Hash != entryHash {
+			return nil, 0, fmt.Errorf("line %d: entry was modified", line)
+		}
+
+		prevHash = entry.Hash
+		lastEntry = &entry
+		size += int64(len(data))
+	}
+
+	return lastEntry, size, nil
+}
+
+// Decodes calldata made of a list of calls, each one encoded as:
+// [contract address, selector, calldata length, calldata...]. Returns false if the
+// calldata doesn't follow this format
+func decodeCalls(calldata []*felt.Felt, knownSelectors map[felt.Felt]string) ([]AuditCall, bool) {
+	const callHeaderLen = 3
+	if len(calldata) == 0 || calldata[0] == nil {
+		return nil, false
+	}
+
+	callsCount := calldata[0].Uint64()
+	calls := make([]AuditCall, 0, min(callsCount, uint64(len(calldata))))
+	offset := 1
+	for range callsCount {
+		if offset+callHeaderLen > len(calldata) {
+			return nil, false
+		}
+		contract, selector, argsLen := calldata[offset], calldata[offset+1], calldata[offset+2]
+		if contract == nil || selector == nil || argsLen == nil {
+			return nil, false
+		}
+		argsStart := offset + callHeaderLen
+		argsEnd := argsStart + int(argsLen.Uint64()) //nolint:gosec // Bounded below
+		if argsLen.Uint64() > uint64(len(calldata)) || argsEnd > len(calldata) {
+			return nil, false
+		}
+
+		calls = append(calls, AuditCall{
+			ContractAddress: contract,
+			Selector:        selector,
+			Function:        knownSelectors[*selector],
+			Calldata:        calldata[argsStart:argsEnd],
+		})
+		offset = argsEnd
+	}
+	if offset != len(calldata) {
+		return nil, false
+	}
+
+	return calls, true
+}
@@ -0,0 +1,232 @@
+package signer_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	auditLog, err := signer.OpenAuditLog(path)
+	require.NoError(t, err)
+
+	remoteSigner, err := signer.New("0x123", utils.NewNopZapLogger())
+	require.NoError(t, err)
+	policy, err := signer.NewPolicy(&signer.PolicyConfig{ChainIDs: []string{"SN_SEPOLIA"}})
+	require.NoError(t, err)
+	remoteSigner.SetPolicy(policy)
+	remoteSigner.SetAuditLog(auditLog)
+
+	send := func(t *testing.T, body []byte) int {
+		t.Helper()
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, signer.SignEndpoint, bytes.NewReader(body))
+		remoteSigner.Handler().ServeHTTP(recorder, request)
+
+		return recorder.Code
+	}
+
+	txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+	body, err := json.Marshal(&signer.Request{InvokeTxnV3: txn, ChainID: chainID})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, send(t, body))
+
+	mainnetBody, err := json.Marshal(&signer.Request{
+		InvokeTxnV3: txn, ChainID: new(felt.Felt).SetBytes([]byte("SN_MAIN")),
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusForbidden, send(t, mainnetBody))
+
+	require.Equal(t, http.StatusBadRequest, send(t, []byte("not json")))
+	require.NoError(t, auditLog.Close())
+
+	t.Run("Every request is recorded", func(t *testing.T) {
+		entries := readAuditEntries(t, path)
+		require.Len(t, entries, 3)
+
+		txHash, err := hash.TransactionHashInvokeV3(txn, chainID)
+		require.NoError(t, err)
+		signed := entries[0]
+		require.Equal(t, signer.DecisionSigned, signed.Decision)
+		require.Equal(t, txHash, signed.TxHash)
+		require.Equal(t, txn.SenderAddress, signed.Sender)
+		require.Equal(t, txn.Nonce, signed.Nonce)
+		require.Len(t, signed.Calls, 1)
+		require.Equal(t, "attest", signed.Calls[0].Function)
+		require.Equal(t, txn.Calldata[4:], signed.Calls[0].Calldata)
+
+		require.Equal(t, signer.DecisionRefused, entries[1].Decision)
+		require.Equal(t, signer.ReasonChainIDNotAllowed, entries[1].ReasonCode)
+		require.Equal(t, signer.DecisionRefused, entries[2].Decision)
+		require.Equal(t, signer.ReasonInvalidRequest, entries[2].ReasonCode)
+	})
+
+	t.Run("Verify untouched log", func(t *testing.T) {
+		lastEntry, err := signer.VerifyAuditLog(path, "")
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), lastEntry.Seq)
+
+		_, err = signer.VerifyAuditLog(path, lastEntry.Hash)
+		require.NoError(t, err)
+		_, err = signer.VerifyAuditLog(path, strings.Repeat("1", 64))
+		require.ErrorContains(t, err, "doesn't match the expected")
+	})
+
+	t.Run("Entries are chained across restarts", func(t *testing.T) {
+		reopened, err := signer.OpenAuditLog(path)
+		require.NoError(t, err)
+		remoteSigner.SetAuditLog(reopened)
+		require.Equal(t, http.StatusOK, send(t, body))
+		require.NoError(t, reopened.Close())
+
+		lastEntry, err := signer.VerifyAuditLog(path, "")
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), lastEntry.Seq)
+	})
+
+	t.Run("Detect modified entries", func(t *testing.T) {
+		tampered := copyAuditLog(t, path)
+		data, err := os.ReadFile(tampered)
+		require.NoError(t, err)
+		data = bytes.Replace(data, []byte(`"decision":"refused"`), []byte(`"decision":"signed"`), 1)
+		require.NoError(t, os.WriteFile(tampered, data, 0o600))
+
+		_, err = signer.VerifyAuditLog(tampered, "")
+		require.ErrorContains(t, err, "line 2: entry was modified")
+	})
+
+	t.Run("Detect removed entries", func(t *testing.T) {
+		tampered := copyAuditLog(t, path)
+		data, err := os.ReadFile(tampered)
+		require.NoError(t, err)
+		lines := strings.SplitAfter(string(data), "\n")
+		data = []byte(lines[0] + strings.Join(lines[2:], ""))
+		require.NoError(t, os.WriteFile(tampered, data, 0o600))
+
+		_, err = signer.VerifyAuditLog(tampered, "")
+		require.ErrorContains(t, err, "line 2: expected entry 1, got entry 2")
+	})
+
+	t.Run("Detect truncation", func(t *testing.T) {
+		tampered := copyAuditLog(t, path)
+		data, err := os.ReadFile(tampered)
+		require.NoError(t, err)
+		lines := strings.SplitAfter(string(data), "\n")
+		data = []byte(strings.Join(lines[:2], ""))
+		require.NoError(t, os.WriteFile(tampered, data, 0o600))
+
+		_, err = signer.VerifyAuditLog(tampered, "")
+		require.ErrorContains(t, err, "audit log was truncated")
+		_, err = signer.OpenAuditLog(tampered)
+		require.ErrorContains(t, err, "audit log was truncated")
+	})
+
+	// Appends an entry to the log, checking it is chained to the last one
+	appendEntry := func(t *testing.T, path string, expectedSeq uint64) {
+		t.Helper()
+
+		reopened, err := signer.OpenAuditLog(path)
+		require.NoError(t, err)
+		//nolint:exhaustruct // Only the decision matters
+		entry := signer.AuditEntry{Decision: signer.DecisionRefused}
+		require.NoError(t, reopened.Append(&entry))
+		require.NoError(t, reopened.Close())
+		require.Equal(t, expectedSeq, entry.Seq)
+
+		lastEntry, err := signer.VerifyAuditLog(path, "")
+		require.NoError(t, err)
+		require.Equal(t, expectedSeq, lastEntry.Seq)
+	}
+
+	t.Run("Repair head left one entry behind", func(t *testing.T) {
+		interrupted := copyAuditLog(t, path)
+		entries := readAuditEntries(t, interrupted)
+		previous := entries[len(entries)-2]
+		head := fmt.Sprintf("%d %s\n", previous.Seq, previous.Hash)
+		require.NoError(t, os.WriteFile(interrupted+".head", []byte(head), 0o600))
+
+		lastEntry, err := signer.VerifyAuditLog(interrupted, "")
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), lastEntry.Seq)
+		head = fmt.Sprintf("%d %s\n", lastEntry.Seq, lastEntry.Hash)
+		data, err := os.ReadFile(interrupted + ".head")
+		require.NoError(t, err)
+		require.Equal(t, head, string(data))
+
+		appendEntry(t, interrupted, 4)
+	})
+
+	t.Run("Head two entries behind is a modification", func(t *testing.T) {
+		tampered := copyAuditLog(t, path)
+		entries := readAuditEntries(t, tampered)
+		previous := entries[len(entries)-3]
+		head := fmt.Sprintf("%d %s\n", previous.Seq, previous.Hash)
+		require.NoError(t, os.WriteFile(tampered+".head", []byte(head), 0o600))
+
+		_, err := signer.VerifyAuditLog(tampered, "")
+		require.ErrorContains(t, err, "audit log was truncated")
+	})
+
+	t.Run("Remove partial entry of an interrupted write", func(t *testing.T) {
+		interrupted := copyAuditLog(t, path)
+		file, err := os.OpenFile(interrupted, os.O_WRONLY|os.O_APPEND, 0o600)
+		require.NoError(t, err)
+		_, err = file.WriteString(`{"seq":4,"timestamp":`)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		appendEntry(t, interrupted, 4)
+		require.Len(t, readAuditEntries(t, interrupted), 5)
+	})
+}
+
+func readAuditEntries(t *testing.T, path string) []signer.AuditEntry {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, file.Close()) }()
+
+	var entries []signer.AuditEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry signer.AuditEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+
+	return entries
+}
+
+// Copies the audit log and its head file to a new directory
+func copyAuditLog(t *testing.T, path string) string {
+	t.Helper()
+
+	copyPath := filepath.Join(t.TempDir(), filepath.Base(path))
+	for _, suffix := range []string{"", ".head"} {
+		data, err := os.ReadFile(path + suffix)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(copyPath+suffix, data, 0o600))
+	}
+
+	return copyPath
+}
@@ -0,0 +1,343 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/secret"
+	"google.golang.org/grpc/metadata"
+)
+
+// Headers used to authenticate HMAC signed requests
+const (
+	ClientIDHeader  = "X-Signer-Client"
+	TimestampHeader = "X-Signer-Timestamp"
+	NonceHeader     = "X-Signer-Nonce"
+	HMACHeader      = "X-Signer-Signature"
+
+	bearerPrefix = "Bearer "
+	nonceLen     = 16
+)
+
+// Default time a HMAC signed request is considered valid for
+const DefaultReplayWindow = 30 * time.Second
+
+var errUnauthenticated = errors.New("missing authentication")
+
+// Credentials a client uses to authenticate its requests to the signer. Either a bearer
+// token or a HMAC key, together with the client ID, should be set
+type ClientAuth struct {
+	ClientID string
+	Token    string
+	HMACKey  string
+}
+
+// Adds the authentication headers to a request whose body is `body`
+func (a *ClientAuth) Apply(req *http.Request, body []byte) error {
+	switch {
+	case a.Token != "":
+		req.Header.Set("Authorization", bearerPrefix+a.Token)
+	case a.HMACKey != "":
+		nonce := make([]byte, nonceLen)
+		if _, err := rand.Read(nonce); err != nil {
+			return fmt.Errorf("cannot generate request nonce: %w", err)
+		}
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		nonceHex := hex.EncodeToString(nonce)
+
+		req.Header.Set(ClientIDHeader, a.ClientID)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(NonceHeader, nonceHex)
+		req.Header.Set(
+			HMACHeader,
+			hex.EncodeToString(
+				requestMAC(a.HMACKey, req.Method, req.URL.Path, timestamp, nonceHex, body),
+			),
+		)
+	}
+
+	return nil
+}
+
+// Computes the HMAC-SHA256 over the canonical representation of a request
+func requestMAC(key, method, path, timestamp, nonce string, body []byte) []byte {
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(strings.Join(
+		[]string{method, path, timestamp, nonce, hex.EncodeToString(bodyHash[:])}, "\n",
+	)))
+
+	return mac.Sum(nil)
+}
+
+// A client allowed to request signatures, identified by its ID
+type ClientCredential struct {
+	ID      string        `json:"id"`
+	Token   secret.Secret `json:"token,omitempty"`
+	HMACKey secret.Secret `json:"hmacKey,omitempty"`
+}
+
+type AuthConfig struct {
+	Clients []ClientCredential `json:"clients"`
+}
+
+// Loads the clients allowed to use the signer from a JSON file. Client tokens and keys
+// can be given as `file:`, `env:` or `exec:` references
+func LoadAuthConfig(ctx context.Context, path string) (AuthConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return AuthConfig{}, err
+	}
+
+	var config AuthConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return AuthConfig{}, fmt.Errorf("cannot parse auth config %s: %w", path, err)
+	}
+
+	for i := range config.Clients {
+		client := &config.Clients[i]
+		if err := client.Token.Resolve(ctx); err != nil {
+			return AuthConfig{}, fmt.Errorf("resolving token of client %s: %w", client.ID, err)
+		}
+		if err := client.HMACKey.Resolve(ctx); err != nil {
+			return AuthConfig{}, fmt.Errorf("resolving hmac key of client %s: %w", client.ID, err)
+		}
+	}
+
+	return config, config.Check()
+}
+
+func (c *AuthConfig) Check() error {
+	ids := make(map[string]struct{}, len(c.Clients))
+	for _, client := range c.Clients {
+		if client.ID == "" {
+			return errors.New("auth config has a client without id")
+		}
+		if _, ok := ids[client.ID]; ok {
+			return fmt.Errorf("auth config has duplicated client id %s", client.ID)
+		}
+		ids[client.ID] = struct{}{}
+
+		if client.Token.IsZero() == client.HMACKey.IsZero() {
+			return fmt.Errorf("client %s should have exactly one of token or hmac key", client.ID)
+		}
+	}
+
+	return nil
+}
+
+type clientIdentityKey struct{}
+
+// Returns the ID of the authenticated client which sent the request. Empty if
+// authentication is disabled
+func ClientIdentity(ctx context.Context) string {
+	id, _ := ctx.Value(clientIdentityKey{}).(string)
+
+	return id
+}
+
+// Verifies requests come from one of the configured clients
+type Authenticator struct {
+	clients      []ClientCredential
+	replayWindow time.Duration
+	logger       *utils.ZapLogger
+	now          func() time.Time
+
+	mu sync.Mutex
+	// Nonces used, by the start of the replay window they were used in
+	seenNonces map[time.Time]map[string]struct{}
+}
+
+func NewAuthenticator(
+	config *AuthConfig, replayWindow time.Duration, logger *utils.ZapLogger,
+) *Authenticator {
+	return &Authenticator{
+		clients:      config.Clients,
+		replayWindow: replayWindow,
+		logger:       logger,
+		now:          time.Now,
+		mu:           sync.Mutex{},
+		seenNonces:   make(map[time.Time]map[string]struct{}),
+	}
+}
+
+// Rejects any request that doesn't carry valid credentials. Authenticated requests are
+// passed on with the client identity in their context
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return a.middleware(next, nil)
+}
+
+// Same as `Middleware`, calling `onReject`, if set, for every rejected request
+func (a *Authenticator) middleware(
+	next http.Handler, onReject func(r *http.Request, err error),
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, err := a.authenticate(r)
+		if err != nil {
+			a.logger.Warnw(
+				"rejected unauthenticated request",
+				"remote address", r.RemoteAddr,
+				"path", r.URL.Path,
+				"reason", err.Error(),
+			)
+			if onReject != nil {
+				onReject(r, err)
+			}
+			http.Error(w, "unauthorized: "+err.Error(), http.StatusUnauthorized)
+
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), clientIdentityKey{}, clientID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (a *Authenticator) authenticate(r *http.Request) (string, error) {
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		token, ok := strings.CutPrefix(authorization, bearerPrefix)
+		if !ok {
+			return "", errors.New("unsupported authorization scheme")
+		}
+
+		return a.authenticateToken(token)
+	}
+
+	if r.Header.Get(HMACHeader) != "" {
+		return a.authenticateHMAC(r)
+	}
+
+	return "", errUnauthenticated
+}
+
+// Same as `authenticate` for a gRPC request. Only bearer tokens are supported, as the
+// HMAC signature covers the HTTP request body
+func (a *Authenticator) authenticateGRPC(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if authorization := md.Get("authorization"); len(authorization) > 0 {
+		token, ok := strings.CutPrefix(authorization[0], bearerPrefix)
+		if !ok {
+			return "", errors.New("unsupported authorization scheme")
+		}
+
+		return a.authenticateToken(token)
+	}
+
+	if len(md.Get(HMACHeader)) > 0 {
+		return "", errors.New("hmac authentication is not supported over grpc")
+	}
+
+	return "", errUnauthenticated
+}
+
+func (a *Authenticator) authenticateToken(token string) (string, error) {
+	for _, client := range a.clients {
+		if client.Token.IsZero() {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(client.Token.Reveal()), []byte(token)) == 1 {
+			return client.ID, nil
+		}
+	}
+
+	return "", errors.New("invalid bearer token")
+}
+
+func (a *Authenticator) authenticateHMAC(r *http.Request) (string, error) {
+	clientID := r.Header.Get(ClientIDHeader)
+	timestamp := r.Header.Get(TimestampHeader)
+	nonce := r.Header.Get(NonceHeader)
+
+	var client *ClientCredential
+	for i := range a.clients {
+		if a.clients[i].ID == clientID && !a.clients[i].HMACKey.IsZero() {
+			client = &a.clients[i]
+
+			break
+		}
+	}
+	if client == nil {
+		return "", fmt.Errorf("unknown client %q", clientID)
+	}
+
+	unixTime, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", errors.New("invalid request timestamp")
+	}
+	now := a.now()
+	requestTime := time.Unix(unixTime, 0)
+	if requestTime.Before(now.Add(-a.replayWindow)) || requestTime.After(now.Add(a.replayWindow)) {
+		return "", errors.New("request timestamp outside of the replay window")
+	}
+	if nonce == "" {
+		return "", errors.New("missing request nonce")
+	}
+
+	signature, err := hex.DecodeString(r.Header.Get(HMACHeader))
+	if err != nil {
+		return "", errors.New("invalid request signature encoding")
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, MaxRequestBodySize))
+	if err != nil {
+		return "", fmt.Errorf("cannot read request body: %w", err)
+	}
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	expected := requestMAC(
+		client.HMACKey.Reveal(), r.Method, r.URL.Path, timestamp, nonce, body,
+	)
+	if !hmac.Equal(signature, expected) {
+		return "", errors.New("invalid request signature")
+	}
+
+	if !a.registerNonce(clientID+"/"+nonce, now) {
+		return "", errors.New("replayed request")
+	}
+
+	return client.ID, nil
+}
+
+// Records a nonce as used. Returns false if it was already used within the replay window
+func (a *Authenticator) registerNonce(nonce string, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// A request is accepted up to one window ahead of the signer clock, so its nonce is
+	// kept for two windows after the one it was used in
+	window := now.Truncate(a.replayWindow)
+	for start, nonces := range a.seenNonces {
+		if !now.Before(start.Add(3 * a.replayWindow)) {
+			delete(a.seenNonces, start)
+
+			continue
+		}
+		if _, ok := nonces[nonce]; ok {
+			return false
+		}
+	}
+
+	if a.seenNonces[window] == nil {
+		a.seenNonces[window] = make(map[string]struct{})
+	}
+	a.seenNonces[window][nonce] = struct{}{}
+
+	return true
+}
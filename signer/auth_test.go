@@ -0,0 +1,296 @@
+package signer_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/secret"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/stretchr/testify/require"
+)
+
+// Builds a sign request HMAC signed with the key, as described in the signer docs
+func hmacRequest(
+	t *testing.T, clientID, key string, timestamp time.Time, nonce string, body []byte,
+) *http.Request {
+	t.Helper()
+
+	unixTime := strconv.FormatInt(timestamp.Unix(), 10)
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(strings.Join([]string{
+		http.MethodPost, signer.SignEndpoint, unixTime, nonce, hex.EncodeToString(bodyHash[:]),
+	}, "\n")))
+
+	request := httptest.NewRequest(http.MethodPost, signer.SignEndpoint, bytes.NewReader(body))
+	request.Header.Set(signer.ClientIDHeader, clientID)
+	request.Header.Set(signer.TimestampHeader, unixTime)
+	request.Header.Set(signer.NonceHeader, nonce)
+	request.Header.Set(signer.HMACHeader, hex.EncodeToString(mac.Sum(nil)))
+
+	return request
+}
+
+func TestAuthenticator(t *testing.T) {
+	replayWindow := time.Minute
+	//nolint:exhaustruct // Only specifying used fields
+	authConfig := signer.AuthConfig{Clients: []signer.ClientCredential{
+		{ID: "bearer-client", Token: secret.New("some token")},
+		{ID: "hmac-client", HMACKey: secret.New("some key")},
+	}}
+	require.NoError(t, authConfig.Check())
+
+	newHandler := func(replayWindow time.Duration) http.Handler {
+		authenticator := signer.NewAuthenticator(
+			&authConfig, replayWindow, utils.NewNopZapLogger(),
+		)
+
+		return authenticator.Middleware(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(signer.ClientIdentity(r.Context())))
+			}))
+	}
+	handler := newHandler(replayWindow)
+
+	serve := func(handler http.Handler, request *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	body := []byte(`{"transaction": {}}`)
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		request func(t *testing.T) *http.Request
+		// Authenticated client, or the reason of the rejection when empty
+		client string
+		reason string
+	}{
+		{
+			name: "Valid bearer token",
+			request: func(t *testing.T) *http.Request {
+				request := httptest.NewRequest(http.MethodPost, signer.SignEndpoint, nil)
+				request.Header.Set("Authorization", "Bearer some token")
+
+				return request
+			},
+			client: "bearer-client",
+		},
+		{
+			name: "Unknown bearer token",
+			request: func(t *testing.T) *http.Request {
+				request := httptest.NewRequest(http.MethodPost, signer.SignEndpoint, nil)
+				request.Header.Set("Authorization", "Bearer other token")
+
+				return request
+			},
+			reason: "invalid bearer token",
+		},
+		{
+			name: "Unsupported authorization scheme",
+			request: func(t *testing.T) *http.Request {
+				request := httptest.NewRequest(http.MethodPost, signer.SignEndpoint, nil)
+				request.Header.Set("Authorization", "Basic c29tZTp0b2tlbg==")
+
+				return request
+			},
+			reason: "unsupported authorization scheme",
+		},
+		{
+			name: "Missing credentials",
+			request: func(t *testing.T) *http.Request {
+				return httptest.NewRequest(http.MethodPost, signer.SignEndpoint, nil)
+			},
+			reason: "missing authentication",
+		},
+		{
+			name: "Valid hmac signature",
+			request: func(t *testing.T) *http.Request {
+				return hmacRequest(t, "hmac-client", "some key", now, "nonce-1", body)
+			},
+			client: "hmac-client",
+		},
+		{
+			name: "Timestamp behind within the replay window",
+			request: func(t *testing.T) *http.Request {
+				timestamp := now.Add(-replayWindow / 2)
+
+				return hmacRequest(t, "hmac-client", "some key", timestamp, "nonce-2", body)
+			},
+			client: "hmac-client",
+		},
+		{
+			name: "Timestamp ahead within the replay window",
+			request: func(t *testing.T) *http.Request {
+				timestamp := now.Add(replayWindow / 2)
+
+				return hmacRequest(t, "hmac-client", "some key", timestamp, "nonce-3", body)
+			},
+			client: "hmac-client",
+		},
+		{
+			name: "Timestamp behind the replay window",
+			request: func(t *testing.T) *http.Request {
+				timestamp := now.Add(-2 * replayWindow)
+
+				return hmacRequest(t, "hmac-client", "some key", timestamp, "nonce-4", body)
+			},
+			reason: "request timestamp outside of the replay window",
+		},
+		{
+			name: "Timestamp ahead of the replay window",
+			request: func(t *testing.T) *http.Request {
+				timestamp := now.Add(2 * replayWindow)
+
+				return hmacRequest(t, "hmac-client", "some key", timestamp, "nonce-5", body)
+			},
+			reason: "request timestamp outside of the replay window",
+		},
+		{
+			name: "Unknown client id",
+			request: func(t *testing.T) *http.Request {
+				return hmacRequest(t, "other-client", "some key", now, "nonce-6", body)
+			},
+			reason: `unknown client "other-client"`,
+		},
+		{
+			name: "Client id of a bearer token client",
+			request: func(t *testing.T) *http.Request {
+				return hmacRequest(t, "bearer-client", "some key", now, "nonce-7", body)
+			},
+			reason: `unknown client "bearer-client"`,
+		},
+		{
+			name: "Wrong hmac key",
+			request: func(t *testing.T) *http.Request {
+				return hmacRequest(t, "hmac-client", "other key", now, "nonce-8", body)
+			},
+			reason: "invalid request signature",
+		},
+		{
+			name: "Body changed after signing",
+			request: func(t *testing.T) *http.Request {
+				request := hmacRequest(t, "hmac-client", "some key", now, "nonce-9", body)
+				changed := []byte(`{"transaction": {"nonce": "0x2"}}`)
+				request.Body = io.NopCloser(bytes.NewReader(changed))
+
+				return request
+			},
+			reason: "invalid request signature",
+		},
+		{
+			name: "Malformed timestamp",
+			request: func(t *testing.T) *http.Request {
+				request := hmacRequest(t, "hmac-client", "some key", now, "nonce-10", body)
+				request.Header.Set(signer.TimestampHeader, "yesterday")
+
+				return request
+			},
+			reason: "invalid request timestamp",
+		},
+		{
+			name: "Missing nonce",
+			request: func(t *testing.T) *http.Request {
+				return hmacRequest(t, "hmac-client", "some key", now, "", body)
+			},
+			reason: "missing request nonce",
+		},
+		{
+			name: "Malformed signature",
+			request: func(t *testing.T) *http.Request {
+				request := hmacRequest(t, "hmac-client", "some key", now, "nonce-11", body)
+				request.Header.Set(signer.HMACHeader, "not hex")
+
+				return request
+			},
+			reason: "invalid request signature encoding",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := serve(handler, test.request(t))
+
+			if test.reason != "" {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), test.reason)
+
+				return
+			}
+			require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+			require.Equal(t, test.client, recorder.Body.String())
+		})
+	}
+
+	t.Run("Replayed nonce", func(t *testing.T) {
+		request := func() *http.Request {
+			return hmacRequest(t, "hmac-client", "some key", now, "replayed", body)
+		}
+		require.Equal(t, http.StatusOK, serve(handler, request()).Code)
+
+		recorder := serve(handler, request())
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+		require.Contains(t, recorder.Body.String(), "replayed request")
+	})
+
+	t.Run("Request body too large", func(t *testing.T) {
+		large := bytes.Repeat([]byte(" "), signer.MaxRequestBodySize+1)
+		request := hmacRequest(t, "hmac-client", "some key", now, "large", large)
+
+		recorder := serve(handler, request)
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+		require.Contains(t, recorder.Body.String(), "request body too large")
+
+		// Also limited when the signer doesn't authenticate requests
+		remoteSigner := signer.NewWithKey(big.NewInt(0x123), utils.NewNopZapLogger())
+		request = httptest.NewRequest(http.MethodPost, signer.SignEndpoint, bytes.NewReader(large))
+		recorder = serve(remoteSigner.Handler(), request)
+		require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	})
+
+	t.Run("Same nonce from different clients", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		twoClients := signer.AuthConfig{Clients: []signer.ClientCredential{
+			{ID: "client-a", HMACKey: secret.New("key a")},
+			{ID: "client-b", HMACKey: secret.New("key b")},
+		}}
+		authenticator := signer.NewAuthenticator(
+			&twoClients, replayWindow, utils.NewNopZapLogger(),
+		)
+		handler := authenticator.Middleware(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {}))
+
+		request := hmacRequest(t, "client-a", "key a", now, "shared", body)
+		require.Equal(t, http.StatusOK, serve(handler, request).Code)
+		request = hmacRequest(t, "client-b", "key b", now, "shared", body)
+		require.Equal(t, http.StatusOK, serve(handler, request).Code)
+	})
+
+	t.Run("Expired nonce can't be replayed", func(t *testing.T) {
+		handler := newHandler(time.Second)
+		sent := hmacRequest(t, "hmac-client", "some key", time.Now(), "expired", body)
+		replayed := sent.Clone(t.Context())
+		replayed.Body = io.NopCloser(bytes.NewReader(body))
+		require.Equal(t, http.StatusOK, serve(handler, sent).Code)
+
+		// Once the nonce is forgotten, the request timestamp is out of the replay window
+		time.Sleep(2100 * time.Millisecond)
+		recorder := serve(handler, replayed)
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+		require.Contains(t, recorder.Body.String(), "outside of the replay window")
+	})
+}
@@ -0,0 +1,101 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/starknet-staking-v2/keystore"
+	"github.com/NethermindEth/starknet-staking-v2/secret"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/curve"
+)
+
+// Storage of a signing key. The signer only goes through this interface, so keys can be
+// held in memory, in an encrypted file or in a hardware token alike
+type KeyBackend interface {
+	// Stark public key matching the private key
+	PublicKey() *big.Int
+	// Signs the hash, returning the ECDSA `r` and `s` values
+	Sign(ctx context.Context, msgHash *big.Int) (*big.Int, *big.Int, error)
+	// Releases the resources held by the backend. The key cannot sign afterwards
+	Close() error
+}
+
+// Private key held in the signer memory
+type memoryKey struct {
+	publicKey *big.Int
+	keyStore  *account.MemKeystore
+}
+
+// Creates a backend holding the private key in memory
+func NewMemoryKey(privateKey *big.Int) KeyBackend {
+	publicKey, _ := curve.PrivateKeyToPoint(privateKey)
+
+	return &memoryKey{
+		publicKey: publicKey,
+		keyStore:  account.SetNewMemKeystore(publicKey.String(), privateKey),
+	}
+}
+
+func (k *memoryKey) PublicKey() *big.Int {
+	return k.publicKey
+}
+
+func (k *memoryKey) Sign(ctx context.Context, msgHash *big.Int) (*big.Int, *big.Int, error) {
+	return k.keyStore.Sign(ctx, k.publicKey.String(), msgHash)
+}
+
+func (k *memoryKey) Close() error {
+	return nil
+}
+
+// Creates a backend out of an encrypted JSON keystore. The key is decrypted once, when
+// the keystore is opened, and kept in memory
+func OpenKeystoreKey(path, password string) (KeyBackend, error) {
+	privateKey, err := keystore.Open(path, password)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open keystore %s: %w", path, err)
+	}
+
+	return NewMemoryKey(privateKey), nil
+}
+
+// Key held by a PKCS#11 token, such as an HSM or SoftHSM
+type PKCS11Config struct {
+	// Path to the PKCS#11 module of the token, e.g. /usr/lib/softhsm/libsofthsm2.so
+	Module     string        `json:"module"`
+	TokenLabel string        `json:"tokenLabel"`
+	PIN        secret.Secret `json:"pin"`
+	// Label of the Stark private key on the token. With a wrapped key, label of the AES
+	// key it is wrapped with
+	KeyLabel string `json:"keyLabel"`
+	// Path to the private key encrypted with the token AES key, as written by `signer
+	// pkcs11 wrap`. For tokens without Stark curve support: the token decrypts the key
+	// when the signer starts and signing is done in memory
+	WrappedKey string `json:"wrappedKey,omitempty"`
+}
+
+func (c *PKCS11Config) Validate() error {
+	switch {
+	case c.Module == "":
+		return errors.New("pkcs11 module is not set")
+	case c.TokenLabel == "":
+		return errors.New("pkcs11 token label is not set")
+	case c.KeyLabel == "":
+		return errors.New("pkcs11 key label is not set")
+	default:
+		return nil
+	}
+}
+
+// Closes every backend, returning the errors of the ones failing to
+func closeBackends(backends []KeyBackend) error {
+	errs := make([]error, 0, len(backends))
+	for _, backend := range backends {
+		errs = append(errs, backend.Close())
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,132 @@
+package signer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/keystore"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/stretchr/testify/require"
+)
+
+// Checks the backend signature is a valid Stark signature of the hash
+func requireValidBackendSignature(t *testing.T, key signer.KeyBackend, msgHash *big.Int) {
+	t.Helper()
+
+	r, s, err := key.Sign(t.Context(), msgHash)
+	require.NoError(t, err)
+	valid, err := curve.Verify(msgHash, r, s, key.PublicKey())
+	require.NoError(t, err)
+	require.True(t, valid)
+}
+
+func TestKeyBackends(t *testing.T) {
+	privateKey := big.NewInt(0x123)
+	publicKey, _ := curve.PrivateKeyToPoint(privateKey)
+	msgHash := utils.HexToFelt(t, "0x1234abcd").BigInt(new(big.Int))
+
+	t.Run("Memory key", func(t *testing.T) {
+		key := signer.NewMemoryKey(privateKey)
+		require.Equal(t, publicKey, key.PublicKey())
+		requireValidBackendSignature(t, key, msgHash)
+		require.NoError(t, key.Close())
+	})
+
+	t.Run("Keystore key", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keystore.json")
+		ks, err := keystore.Encrypt(privateKey, "password", keystore.LightScryptN)
+		require.NoError(t, err)
+		require.NoError(t, ks.Save(path))
+
+		key, err := signer.OpenKeystoreKey(path, "password")
+		require.NoError(t, err)
+		require.Equal(t, publicKey, key.PublicKey())
+		requireValidBackendSignature(t, key, msgHash)
+		require.NoError(t, key.Close())
+
+		_, err = signer.OpenKeystoreKey(path, "wrong")
+		require.Error(t, err)
+	})
+
+	t.Run("Signer signing through a backend", func(t *testing.T) {
+		remoteSigner := signer.NewWithKeyBackend(
+			signer.NewMemoryKey(privateKey), utils.NewNopZapLogger(),
+		)
+		defer func() { require.NoError(t, remoteSigner.Close()) }()
+
+		chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		body, err := json.Marshal(&signer.Request{InvokeTxnV3: txn, ChainID: chainID})
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, signer.SignEndpoint, bytes.NewReader(body))
+		remoteSigner.Handler().ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var resp signer.Response
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		require.Len(t, resp.Signature, 2)
+		txHash, err := hash.TransactionHashInvokeV3(txn, chainID)
+		require.NoError(t, err)
+		requireValidSignature(t, txHash, resp.Signature[0], resp.Signature[1], 0x123)
+	})
+
+	t.Run("Keys file with a keystore key", func(t *testing.T) {
+		dir := t.TempDir()
+		keystorePath := filepath.Join(dir, "keystore.json")
+		ks, err := keystore.Encrypt(privateKey, "password", keystore.LightScryptN)
+		require.NoError(t, err)
+		require.NoError(t, ks.Save(keystorePath))
+		passwordPath := filepath.Join(dir, "password")
+		require.NoError(t, os.WriteFile(passwordPath, []byte("password"), 0o600))
+
+		keysPath := filepath.Join(dir, "keys.json")
+		require.NoError(t, os.WriteFile(keysPath, []byte(`{"keys": [{
+            "account": "0xa",
+            "keystore": "`+keystorePath+`",
+            "keystorePasswordFile": "`+passwordPath+`"
+        }]}`), 0o600))
+
+		config, err := signer.LoadKeysConfig(keysPath)
+		require.NoError(t, err)
+		keys, err := signer.LoadAccountKeys(t.Context(), &config)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.NotNil(t, keys[0].Backend)
+		require.Equal(t, publicKey, keys[0].Backend.PublicKey())
+	})
+
+	t.Run("Keys file with an incomplete pkcs11 key", func(t *testing.T) {
+		keysPath := filepath.Join(t.TempDir(), "keys.json")
+		require.NoError(t, os.WriteFile(keysPath, []byte(`{"keys": [{
+            "account": "0xa",
+            "pkcs11": {"module": "/usr/lib/softhsm/libsofthsm2.so", "keyLabel": "stark"}
+        }]}`), 0o600))
+
+		_, err := signer.LoadKeysConfig(keysPath)
+		require.ErrorContains(t, err, "pkcs11 token label is not set")
+	})
+
+	t.Run("Keys file with both a private key and a pkcs11 key", func(t *testing.T) {
+		keysPath := filepath.Join(t.TempDir(), "keys.json")
+		require.NoError(t, os.WriteFile(keysPath, []byte(`{"keys": [{
+            "account": "0xa",
+            "privateKey": "0x123",
+            "pkcs11": {"module": "m.so", "tokenLabel": "token", "keyLabel": "stark"}
+        }]}`), 0o600))
+
+		_, err := signer.LoadKeysConfig(keysPath)
+		require.ErrorContains(t, err, "exactly one of private key, keystore or pkcs11")
+	})
+}
@@ -0,0 +1,260 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/starknet-staking-v2/signer/signerpb"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Prefix of the urls of signers served over gRPC
+const GRPCScheme = "grpc://"
+
+// Domain of the `google.rpc.ErrorInfo` detail of requests refused by the signing policy
+const PolicyViolationDomain = "signer.starknet-staking-v2"
+
+// Returns the host and port of the signer if the url is a `grpc://` one
+func GRPCAddress(url string) (string, bool) {
+	address, ok := strings.CutPrefix(url, GRPCScheme)
+
+	return address, ok
+}
+
+// Returns the refusal carried by the status of a gRPC error, if any
+func PolicyViolationFromStatus(err error) (*PolicyViolation, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.PermissionDenied {
+		return nil, false
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == PolicyViolationDomain {
+			return &PolicyViolation{Code: ReasonCode(info.GetReason()), Reason: st.Message()}, true
+		}
+	}
+
+	return nil, false
+}
+
+// Returns the gRPC server answering the same requests as `Handler`. Health is served
+// with the standard gRPC health service.
+func (s *Signer) GRPCServer() *grpc.Server {
+	options := []grpc.ServerOption{grpc.UnaryInterceptor(s.authenticateGRPC)}
+	if s.tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+	}
+
+	server := grpc.NewServer(options...)
+	//nolint:exhaustruct // Unimplemented methods are embedded
+	signerpb.RegisterSignerServer(server, &grpcSigner{signer: s})
+	//nolint:exhaustruct // Unimplemented methods are embedded
+	healthpb.RegisterHealthServer(server, &grpcHealth{signer: s})
+
+	return server
+}
+
+// Serves the gRPC interface of the signer at the address, a TCP or a `unix://` one,
+// until the context is done. Requests already being answered are given
+// `ShutdownTimeout` to finish.
+func (s *Signer) ServeGRPC(ctx context.Context, address string) error {
+	if _, ok := SocketPath(address); ok && s.tlsConfig != nil {
+		return errors.New("tls is not supported over a unix socket")
+	}
+
+	listener, err := listen(address)
+	if err != nil {
+		return err
+	}
+
+	server := s.GRPCServer()
+	serveErr := make(chan error, 1)
+	go func() {
+		s.logger.Infof("grpc server running at %s, tls: %t", address, s.tlsConfig != nil)
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.logger.Infof("shutting down grpc server, waiting for in-flight requests to finish")
+	s.shuttingDown.Store(true)
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(ShutdownTimeout):
+		server.Stop()
+
+		return errors.New("cannot shut down grpc server gracefully: timed out")
+	}
+	s.logger.Infof("grpc server stopped")
+
+	return <-serveErr
+}
+
+// Methods clients can call without authentication, as their HTTP counterparts
+var unauthenticatedGRPCMethods = []string{
+	signerpb.Signer_GetCapabilities_FullMethodName,
+	healthpb.Health_Check_FullMethodName,
+	healthpb.Health_List_FullMethodName,
+}
+
+// Rejects requests without valid credentials when authentication is enabled. Same as the
+// HTTP middleware
+func (s *Signer) authenticateGRPC(
+	ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	if s.authenticator == nil {
+		return handler(ctx, req)
+	}
+	for _, method := range unauthenticatedGRPCMethods {
+		if info.FullMethod == method {
+			return handler(ctx, req)
+		}
+	}
+
+	clientID, err := s.authenticator.authenticateGRPC(ctx)
+	if err != nil {
+		source := grpcSourceOf(ctx)
+		s.logger.Warnw(
+			"rejected unauthenticated request",
+			"remote address", source.remoteAddress,
+			"method", info.FullMethod,
+			"reason", err.Error(),
+		)
+		if info.FullMethod == signerpb.Signer_Sign_FullMethodName {
+			_ = s.record(source, nil, nil, violation(ReasonUnauthenticated, "%s", err), 0)
+		}
+
+		return nil, status.Error(codes.Unauthenticated, "unauthorized: "+err.Error())
+	}
+
+	return handler(context.WithValue(ctx, clientIdentityKey{}, clientID), req)
+}
+
+func grpcSourceOf(ctx context.Context) requestSource {
+	//nolint:exhaustruct // The remote address is set below when known
+	source := requestSource{client: ClientIdentity(ctx)}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		source.remoteAddress = p.Addr.String()
+	}
+
+	return source
+}
+
+type grpcSigner struct {
+	signerpb.UnimplementedSignerServer
+
+	signer *Signer
+}
+
+func (g *grpcSigner) Sign(
+	ctx context.Context, message *signerpb.SignRequest,
+) (*signerpb.SignResponse, error) {
+	start := time.Now()
+	source := grpcSourceOf(ctx)
+
+	req, err := RequestFromProto(message)
+	if err == nil {
+		err = req.validate()
+	}
+	if err != nil {
+		decodeErr := violation(ReasonInvalidRequest, "invalid request: %s", err)
+		_ = g.signer.record(source, nil, nil, decodeErr, 0)
+
+		return nil, status.Error(codes.InvalidArgument, "invalid request: "+err.Error())
+	}
+
+	resp, err := g.signer.answer(source, &req, start)
+	if err != nil {
+		return nil, grpcSignError(err)
+	}
+
+	return resp.ToProto(), nil
+}
+
+// Turns a signing error into the gRPC status answered to the client
+func grpcSignError(err error) error {
+	if policyViolation, ok := AsPolicyViolation(err); ok {
+		st := status.New(codes.PermissionDenied, policyViolation.Reason)
+		//nolint:exhaustruct // No metadata
+		detailed, detailsErr := st.WithDetails(&errdetails.ErrorInfo{
+			Reason: string(policyViolation.Code),
+			Domain: PolicyViolationDomain,
+		})
+		if detailsErr != nil {
+			return st.Err()
+		}
+
+		return detailed.Err()
+	}
+	if errors.Is(err, errNotRecorded) {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return status.Error(codes.Internal, "failed to sign tx: "+err.Error())
+}
+
+func (g *grpcSigner) GetPublicKeys(
+	context.Context, *signerpb.GetPublicKeysRequest,
+) (*signerpb.GetPublicKeysResponse, error) {
+	publicKeys := PublicKeysResponse{Keys: g.signer.keys.publicKeys()}
+
+	return publicKeys.ToProto(), nil
+}
+
+func (g *grpcSigner) GetCapabilities(
+	context.Context, *signerpb.GetCapabilitiesRequest,
+) (*signerpb.GetCapabilitiesResponse, error) {
+	capabilities := g.signer.capabilities()
+	// Deploy account transactions can't be sent in gRPC sign requests
+	capabilities.TransactionTypes = slices.DeleteFunc(
+		capabilities.TransactionTypes,
+		func(txnType rpc.TransactionType) bool {
+			return txnType == rpc.TransactionTypeDeployAccount
+		},
+	)
+
+	return capabilities.ToProto(), nil
+}
+
+// Serves the readiness of the signer, for the whole server or the signer service
+type grpcHealth struct {
+	healthpb.UnimplementedHealthServer
+
+	signer *Signer
+}
+
+func (g *grpcHealth) Check(
+	_ context.Context, req *healthpb.HealthCheckRequest,
+) (*healthpb.HealthCheckResponse, error) {
+	service := req.GetService()
+	if service != "" && service != signerpb.Signer_ServiceDesc.ServiceName {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", service)
+	}
+
+	//nolint:exhaustruct // Only the status is answered
+	resp := &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}
+	if g.signer.notReadyReason() != "" {
+		resp.Status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	return resp, nil
+}
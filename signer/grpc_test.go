@@ -0,0 +1,164 @@
+package signer_test
+
+import (
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/secret"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/signer/signerpb"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPC(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+	chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+
+	remoteSigner, err := signer.NewWithAccountKeys([]signer.AccountKey{
+		{Account: *utils.HexToFelt(t, "0x123"), PrivateKey: big.NewInt(0x123), Enabled: true},
+	}, logger)
+	require.NoError(t, err)
+	authConfig := signer.AuthConfig{Clients: []signer.ClientCredential{
+		{ID: "validator", Token: secret.New("some token")},
+	}}
+	remoteSigner.SetAuthenticator(signer.NewAuthenticator(&authConfig, time.Minute, logger))
+	policy, err := signer.NewPolicy(&signer.PolicyConfig{
+		AttestContracts: []string{constants.SepoliaAttestContractAddress},
+	})
+	require.NoError(t, err)
+	remoteSigner.SetPolicy(policy)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := remoteSigner.GRPCServer()
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient(
+		listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+	client := signerpb.NewSignerClient(conn)
+
+	authenticated := metadata.AppendToOutgoingContext(
+		t.Context(), "authorization", "Bearer some token",
+	)
+
+	t.Run("Sign a transaction", func(t *testing.T) {
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		req := signer.Request{Protocol: signer.ProtocolV2, InvokeTxnV3: txn, ChainID: chainID}
+
+		message, err := client.Sign(authenticated, req.ToProto())
+		require.NoError(t, err)
+		resp, err := signer.ResponseFromProto(message)
+		require.NoError(t, err)
+
+		txHash, err := hash.TransactionHashInvokeV3(txn, chainID)
+		require.NoError(t, err)
+		require.Equal(t, signer.ProtocolV2, resp.Protocol)
+		require.Equal(t, txHash, resp.TxHash)
+		requireValidSignature(t, txHash, resp.Signature[0], resp.Signature[1], 0x123)
+	})
+
+	t.Run("Request refused by the policy", func(t *testing.T) {
+		txn := newAttestTxn(t, "0x456", "attest")
+		req := signer.Request{InvokeTxnV3: txn, ChainID: chainID}
+
+		_, err := client.Sign(authenticated, req.ToProto())
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+		policyViolation, ok := signer.PolicyViolationFromStatus(err)
+		require.True(t, ok)
+		require.Equal(t, signer.ReasonContractNotAllowed, policyViolation.Code)
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		_, err := client.Sign(authenticated, &signerpb.SignRequest{ChainId: "0x1"})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		require.ErrorContains(t, err, "missing transaction or chain id")
+	})
+
+	t.Run("Unauthenticated request", func(t *testing.T) {
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		req := signer.Request{InvokeTxnV3: txn, ChainID: chainID}
+
+		_, err := client.Sign(t.Context(), req.ToProto())
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		_, err = client.GetPublicKeys(t.Context(), &signerpb.GetPublicKeysRequest{})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Public keys", func(t *testing.T) {
+		message, err := client.GetPublicKeys(authenticated, &signerpb.GetPublicKeysRequest{})
+		require.NoError(t, err)
+		resp, err := signer.PublicKeysResponseFromProto(message)
+		require.NoError(t, err)
+
+		publicKey, _ := curve.PrivateKeyToPoint(big.NewInt(0x123))
+		require.Len(t, resp.Keys, 1)
+		require.Equal(t, []*felt.Felt{new(felt.Felt).SetBigInt(publicKey)}, resp.Keys[0].PublicKeys)
+	})
+
+	t.Run("Capabilities and health don't require authentication", func(t *testing.T) {
+		message, err := client.GetCapabilities(t.Context(), &signerpb.GetCapabilitiesRequest{})
+		require.NoError(t, err)
+		capabilities := signer.CapabilitiesFromProto(message)
+		require.Equal(t, signer.SupportedProtocolVersions, capabilities.ProtocolVersions)
+
+		health, err := healthpb.NewHealthClient(conn).Check(
+			t.Context(), &healthpb.HealthCheckRequest{},
+		)
+		require.NoError(t, err)
+		require.Equal(t, healthpb.HealthCheckResponse_SERVING, health.GetStatus())
+	})
+}
+
+func TestProtoConversions(t *testing.T) {
+	chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+	epochID := uint64(7)
+
+	t.Run("Transaction request", func(t *testing.T) {
+		req := signer.Request{
+			Protocol:    signer.ProtocolV2,
+			InvokeTxnV3: newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest"),
+			ChainID:     chainID,
+			EpochID:     &epochID,
+		}
+
+		decoded, err := signer.RequestFromProto(req.ToProto())
+		require.NoError(t, err)
+		require.Equal(t, req, decoded)
+	})
+
+	t.Run("Hash request", func(t *testing.T) {
+		req := signer.Request{
+			Protocol: signer.ProtocolV2,
+			Mode:     signer.SignModeHash,
+			Hash:     utils.HexToFelt(t, "0xabc"),
+			Account:  utils.HexToFelt(t, "0x123"),
+		}
+
+		decoded, err := signer.RequestFromProto(req.ToProto())
+		require.NoError(t, err)
+		require.Equal(t, req, decoded)
+	})
+
+	t.Run("Invalid felt", func(t *testing.T) {
+		_, err := signer.RequestFromProto(&signerpb.SignRequest{ChainId: "not a felt"})
+		require.ErrorContains(t, err, "invalid chain id")
+	})
+}
@@ -0,0 +1,209 @@
+package signer
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+const (
+	ReasonNonceAlreadySigned  ReasonCode = "NONCE_ALREADY_SIGNED"
+	ReasonEpochTargetConflict ReasonCode = "EPOCH_TARGET_CONFLICT"
+	ReasonEpochMissing        ReasonCode = "EPOCH_MISSING"
+)
+
+// A transaction signed by the signer
+type SignedRecord struct {
+	ChainID      *felt.Felt `json:"chainId"`
+	Sender       *felt.Felt `json:"sender"`
+	Nonce        *felt.Felt `json:"nonce"`
+	TxHash       *felt.Felt `json:"txHash"`
+	CalldataHash string     `json:"calldataHash"`
+	// Only set for attest transactions
+	EpochID      *uint64    `json:"epochId,omitempty"`
+	AttestTarget *felt.Felt `json:"attestTarget,omitempty"`
+	SignedAt     time.Time  `json:"signedAt"`
+}
+
+func (r *SignedRecord) nonceKey() string {
+	return r.ChainID.String() + "/" + r.Sender.String() + "/" + r.Nonce.String()
+}
+
+func (r *SignedRecord) epochKey() string {
+	return fmt.Sprintf("%s/%s/%d", r.ChainID, r.Sender, *r.EpochID)
+}
+
+// Persistent record of the transactions signed, used to avoid double signing. It refuses
+// to sign a different transaction for a nonce already signed, unless both are attests and
+// it is either a fee bump (only resource bounds and tip changed) or an attest for a later
+// epoch, which replaces an attest that was never sent. It also refuses to attest to more
+// than one target per epoch, so attests have to be sent with their epoch.
+type History struct {
+	mu       sync.Mutex
+	file     *os.File
+	byNonce  map[string]*SignedRecord
+	byEpoch  map[string]*SignedRecord
+	selector felt.Felt
+}
+
+// Opens the history stored at path, creating it if it doesn't exist
+func OpenHistory(path string) (*History, error) {
+	//nolint:mnd // Only readable by the signer
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open signing history: %w", err)
+	}
+
+	history := &History{
+		mu:       sync.Mutex{},
+		file:     file,
+		byNonce:  make(map[string]*SignedRecord),
+		byEpoch:  make(map[string]*SignedRecord),
+		selector: attestSelector(),
+	}
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record SignedRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			_ = file.Close()
+
+			return nil, fmt.Errorf("corrupted signing history at line %d: %w", line, err)
+		}
+		history.index(&record)
+	}
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+
+		return nil, fmt.Errorf("cannot read signing history: %w", err)
+	}
+
+	return history, nil
+}
+
+func (h *History) Close() error {
+	return h.file.Close()
+}
+
+func (h *History) index(record *SignedRecord) {
+	h.byNonce[record.nonceKey()] = record
+	if record.EpochID != nil && record.AttestTarget != nil {
+		h.byEpoch[record.epochKey()] = record
+	}
+}
+
+// Checks the transaction doesn't conflict with a previously signed one and, if so,
+// durably records it. It has to be called before the signature is released.
+func (h *History) Register(
+	txn *rpc.InvokeTxnV3, chainID, txHash *felt.Felt, epochID *uint64,
+) error {
+	// Transactions with the query bit can't be executed, no need to track them
+	if txn.Version == rpc.TransactionV3WithQueryBit {
+		return nil
+	}
+
+	record := SignedRecord{
+		ChainID:      chainID,
+		Sender:       txn.SenderAddress,
+		Nonce:        txn.Nonce,
+		TxHash:       txHash,
+		CalldataHash: hashCalldata(txn.Calldata),
+		EpochID:      epochID,
+		AttestTarget: attestTarget(txn.Calldata, &h.selector),
+		SignedAt:     time.Now().UTC(),
+	}
+	if record.Sender == nil || record.Nonce == nil {
+		return violation(ReasonInvalidCalldata, "missing sender address or nonce")
+	}
+	if record.AttestTarget != nil && record.EpochID == nil {
+		return violation(ReasonEpochMissing, "attest transactions must be sent with their epoch")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if previous, ok := h.byNonce[record.nonceKey()]; ok {
+		if previous.TxHash.Equal(record.TxHash) {
+			return nil
+		}
+		if !replaceableAttest(&record, previous) {
+			return violation(
+				ReasonNonceAlreadySigned,
+				"a different transaction %s was already signed for nonce %s",
+				previous.TxHash, record.Nonce,
+			)
+		}
+	}
+
+	if record.AttestTarget != nil {
+		if previous, ok := h.byEpoch[record.epochKey()]; ok &&
+			!previous.AttestTarget.Equal(record.AttestTarget) {
+			return violation(
+				ReasonEpochTargetConflict,
+				"already attested to %s in epoch %d",
+				previous.AttestTarget, *record.EpochID,
+			)
+		}
+	}
+
+	data, err := json.Marshal(&record)
+	if err != nil {
+		return err
+	}
+	if _, err := h.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("cannot write signing history: %w", err)
+	}
+	if err := h.file.Sync(); err != nil {
+		return fmt.Errorf("cannot write signing history: %w", err)
+	}
+	h.index(&record)
+
+	return nil
+}
+
+// Whether both records are attests and the new one is a fee bump of the previous one
+// or attests for a later epoch
+func replaceableAttest(record, previous *SignedRecord) bool {
+	if record.AttestTarget == nil || previous.AttestTarget == nil ||
+		record.EpochID == nil || previous.EpochID == nil {
+		return false
+	}
+
+	return record.CalldataHash == previous.CalldataHash || *record.EpochID > *previous.EpochID
+}
+
+func hashCalldata(calldata []*felt.Felt) string {
+	hash := sha256.New()
+	for _, item := range calldata {
+		if item == nil {
+			item = &felt.Zero
+		}
+		bytes := item.Bytes()
+		hash.Write(bytes[:])
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Returns the block hash attested to if the calldata is a single attest call
+func attestTarget(calldata []*felt.Felt, selector *felt.Felt) *felt.Felt {
+	if len(calldata) != attestCalldataLen || calldata[2] == nil || calldata[4] == nil {
+		return nil
+	}
+	if !calldata[2].Equal(selector) {
+		return nil
+	}
+
+	return calldata[4]
+}
@@ -0,0 +1,109 @@
+package signer_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+
+	history, err := signer.OpenHistory(path)
+	require.NoError(t, err)
+
+	register := func(history *signer.History, txn *rpc.InvokeTxnV3, epochID uint64) error {
+		txHash, err := hash.TransactionHashInvokeV3(txn, chainID)
+		require.NoError(t, err)
+
+		return history.Register(txn, chainID, txHash, &epochID)
+	}
+
+	attestTxn := func(blockHash string, nonce uint64) *rpc.InvokeTxnV3 {
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		txn.Calldata[4] = utils.HexToFelt(t, blockHash)
+		txn.Nonce = new(felt.Felt).SetUint64(nonce)
+
+		return txn
+	}
+
+	txn := attestTxn("0xabc", 1)
+	require.NoError(t, register(history, txn, 10))
+
+	t.Run("Signing the same transaction again", func(t *testing.T) {
+		require.NoError(t, register(history, txn, 10))
+	})
+
+	t.Run("Fee bump of the same transaction", func(t *testing.T) {
+		bumped := attestTxn("0xabc", 1)
+		bumped.Tip = "0x20"
+		bumped.ResourceBounds.L2Gas.MaxAmount = "0x2000"
+		require.NoError(t, register(history, bumped, 10))
+	})
+
+	t.Run("Fee estimation transactions are not tracked", func(t *testing.T) {
+		estimate := attestTxn("0xdef", 1)
+		estimate.Version = rpc.TransactionV3WithQueryBit
+		require.NoError(t, register(history, estimate, 10))
+	})
+
+	t.Run("Different transaction for a nonce already signed", func(t *testing.T) {
+		err := register(history, attestTxn("0xdef", 1), 10)
+		requireViolation(t, err, signer.ReasonNonceAlreadySigned)
+	})
+
+	t.Run("Different attest target in the same epoch", func(t *testing.T) {
+		err := register(history, attestTxn("0xdef", 2), 10)
+		requireViolation(t, err, signer.ReasonEpochTargetConflict)
+	})
+
+	t.Run("Attest for a later epoch reusing a nonce never sent", func(t *testing.T) {
+		require.NoError(t, register(history, attestTxn("0xdef", 1), 11))
+	})
+
+	t.Run("Attest without its epoch", func(t *testing.T) {
+		withoutEpoch := attestTxn("0x456", 4)
+		txHash, err := hash.TransactionHashInvokeV3(withoutEpoch, chainID)
+		require.NoError(t, err)
+
+		err = history.Register(withoutEpoch, chainID, txHash, nil)
+		requireViolation(t, err, signer.ReasonEpochMissing)
+	})
+
+	t.Run("Only attests can be fee bumped", func(t *testing.T) {
+		other := newAttestTxn(t, constants.SepoliaAttestContractAddress, "transfer")
+		other.Nonce = new(felt.Felt).SetUint64(5)
+		require.NoError(t, register(history, other, 11))
+
+		bumped := newAttestTxn(t, constants.SepoliaAttestContractAddress, "transfer")
+		bumped.Nonce = new(felt.Felt).SetUint64(5)
+		bumped.Tip = "0x20"
+		err := register(history, bumped, 11)
+		requireViolation(t, err, signer.ReasonNonceAlreadySigned)
+
+		// Nor replaced by a later epoch
+		err = register(history, attestTxn("0x456", 5), 12)
+		requireViolation(t, err, signer.ReasonNonceAlreadySigned)
+	})
+
+	t.Run("History is kept across restarts", func(t *testing.T) {
+		require.NoError(t, history.Close())
+
+		reopened, err := signer.OpenHistory(path)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, reopened.Close()) }()
+
+		err = register(reopened, attestTxn("0x123", 3), 11)
+		requireViolation(t, err, signer.ReasonEpochTargetConflict)
+		err = register(reopened, attestTxn("0x123", 1), 11)
+		requireViolation(t, err, signer.ReasonNonceAlreadySigned)
+	})
+}
@@ -0,0 +1,342 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet-staking-v2/keystore"
+	"github.com/NethermindEth/starknet-staking-v2/secret"
+)
+
+const (
+	ReasonUnknownSender ReasonCode = "UNKNOWN_SENDER"
+	ReasonKeyDisabled   ReasonCode = "KEY_DISABLED"
+)
+
+// How the signatures of the keys of an account are combined into the transaction
+// signature
+type SignatureFormat string
+
+const (
+	// `r` and `s` of every key, one after the other: [r1, s1, r2, s2, ...]. The default,
+	// giving the usual [r, s] for a single key. Also used by accounts with a guardian,
+	// listing the owner key first
+	SignatureFormatPairs SignatureFormat = "pairs"
+	// Public key, `r` and `s` of every key: [pk1, r1, s1, pk2, r2, s2, ...]. Used by
+	// multisig accounts
+	SignatureFormatKeyed SignatureFormat = "keyed"
+)
+
+func (f SignatureFormat) Validate() error {
+	switch f {
+	case "", SignatureFormatPairs, SignatureFormatKeyed:
+		return nil
+	default:
+		return fmt.Errorf(
+			"unknown signature format %q, expected %q or %q",
+			f, SignatureFormatPairs, SignatureFormatKeyed,
+		)
+	}
+}
+
+// Signing key bound to the account it signs for. The private key is either given
+// directly, loaded from an encrypted keystore or held by a PKCS#11 token. Several keys
+// for the same account are combined, in the order they are listed, into a single
+// signature.
+type KeyConfig struct {
+	Account              string          `json:"account"`
+	PrivateKey           secret.Secret   `json:"privateKey"`
+	Keystore             string          `json:"keystore,omitempty"`
+	KeystorePasswordFile string          `json:"keystorePasswordFile,omitempty"`
+	PKCS11               *PKCS11Config   `json:"pkcs11,omitempty"`
+	Disabled             bool            `json:"disabled,omitempty"`
+	SignatureFormat      SignatureFormat `json:"signatureFormat,omitempty"`
+}
+
+type KeysConfig struct {
+	Keys []KeyConfig `json:"keys"`
+}
+
+func LoadKeysConfig(path string) (KeysConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return KeysConfig{}, err
+	}
+
+	var config KeysConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return KeysConfig{}, fmt.Errorf("cannot parse keys config %s: %w", path, err)
+	}
+
+	accounts := make(map[felt.Felt]*KeyConfig, len(config.Keys))
+	for i := range config.Keys {
+		key := &config.Keys[i]
+		address, err := new(felt.Felt).SetString(key.Account)
+		if err != nil {
+			return KeysConfig{}, fmt.Errorf("invalid key account %q: %w", key.Account, err)
+		}
+		if err := key.SignatureFormat.Validate(); err != nil {
+			return KeysConfig{}, fmt.Errorf("key for account %s: %w", key.Account, err)
+		}
+		if first, ok := accounts[*address]; ok {
+			if first.Disabled != key.Disabled || first.SignatureFormat != key.SignatureFormat {
+				return KeysConfig{}, fmt.Errorf(
+					"keys for account %s should share the same disabled state and signature"+
+						" format",
+					key.Account,
+				)
+			}
+		} else {
+			accounts[*address] = key
+		}
+
+		sources := 0
+		for _, set := range []bool{
+			!key.PrivateKey.IsZero(), key.Keystore != "", key.PKCS11 != nil,
+		} {
+			if set {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return KeysConfig{}, fmt.Errorf(
+				"key for account %s should have exactly one of private key, keystore or pkcs11",
+				key.Account,
+			)
+		}
+		if key.PKCS11 != nil {
+			if err := key.PKCS11.Validate(); err != nil {
+				return KeysConfig{}, fmt.Errorf("key for account %s: %w", key.Account, err)
+			}
+		}
+	}
+
+	return config, nil
+}
+
+// Signing key bound to the account it signs for. The key is either a private key kept
+// in memory or, when set, the backend holding it
+type AccountKey struct {
+	Account         felt.Felt
+	PrivateKey      *big.Int
+	Backend         KeyBackend
+	Enabled         bool
+	SignatureFormat SignatureFormat
+}
+
+func (k *AccountKey) backend() KeyBackend {
+	if k.Backend != nil {
+		return k.Backend
+	}
+
+	return NewMemoryKey(k.PrivateKey)
+}
+
+// Opens the keys of the configuration, resolving secret references, decrypting
+// keystores and logging into PKCS#11 tokens. The backends opened are closed on error.
+func LoadAccountKeys(ctx context.Context, config *KeysConfig) ([]AccountKey, error) {
+	keys := make([]AccountKey, 0, len(config.Keys))
+	for i := range config.Keys {
+		keyConfig := &config.Keys[i]
+		key, err := loadAccountKey(ctx, keyConfig)
+		if err != nil {
+			backends := make([]KeyBackend, 0, len(keys))
+			for j := range keys {
+				if keys[j].Backend != nil {
+					backends = append(backends, keys[j].Backend)
+				}
+			}
+
+			return nil, errors.Join(err, closeBackends(backends))
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func loadAccountKey(ctx context.Context, keyConfig *KeyConfig) (AccountKey, error) {
+	address, err := new(felt.Felt).SetString(keyConfig.Account)
+	if err != nil {
+		return AccountKey{}, fmt.Errorf("invalid key account %q: %w", keyConfig.Account, err)
+	}
+
+	var backend KeyBackend
+	switch {
+	case keyConfig.PKCS11 != nil:
+		backend, err = OpenPKCS11Key(ctx, keyConfig.PKCS11)
+		if err != nil {
+			return AccountKey{}, fmt.Errorf("key for account %s: %w", keyConfig.Account, err)
+		}
+	case keyConfig.Keystore != "":
+		passwordSource := keystore.PasswordSource{
+			File: keyConfig.KeystorePasswordFile,
+			Env:  "",
+			Prompt: fmt.Sprintf(
+				"Enter password for keystore %s (account %s): ",
+				keyConfig.Keystore, keyConfig.Account,
+			),
+		}
+		password, err := passwordSource.Read()
+		if err != nil {
+			return AccountKey{}, fmt.Errorf("key for account %s: %w", keyConfig.Account, err)
+		}
+		backend, err = OpenKeystoreKey(keyConfig.Keystore, password)
+		if err != nil {
+			return AccountKey{}, err
+		}
+	default:
+		if err := keyConfig.PrivateKey.Resolve(ctx); err != nil {
+			return AccountKey{}, fmt.Errorf(
+				"resolving private key of %s: %w", keyConfig.Account, err,
+			)
+		}
+		privKey, ok := new(big.Int).SetString(keyConfig.PrivateKey.Reveal(), 0)
+		if !ok {
+			// The private key value is purposely left out of the error
+			return AccountKey{}, fmt.Errorf(
+				"invalid private key for account %s", keyConfig.Account,
+			)
+		}
+
+		return AccountKey{
+			Account:         *address,
+			PrivateKey:      privKey,
+			Backend:         nil,
+			Enabled:         !keyConfig.Disabled,
+			SignatureFormat: keyConfig.SignatureFormat,
+		}, nil
+	}
+
+	return AccountKey{
+		Account:         *address,
+		PrivateKey:      nil,
+		Backend:         backend,
+		Enabled:         !keyConfig.Disabled,
+		SignatureFormat: keyConfig.SignatureFormat,
+	}, nil
+}
+
+// Keys signing for the same account
+type accountKeys struct {
+	keys    []KeyBackend
+	format  SignatureFormat
+	enabled bool
+}
+
+// Signs the hash with every key, combining the signatures as set by the format
+func (a *accountKeys) sign(ctx context.Context, msgHash *big.Int) ([]*felt.Felt, error) {
+	signature := make([]*felt.Felt, 0, len(a.keys)*3) //nolint:mnd // Up to 3 felts per key
+	for _, key := range a.keys {
+		r, s, err := key.Sign(ctx, msgHash)
+		if err != nil {
+			return nil, err
+		}
+		if a.format == SignatureFormatKeyed {
+			signature = append(signature, new(felt.Felt).SetBigInt(key.PublicKey()))
+		}
+		signature = append(signature, new(felt.Felt).SetBigInt(r), new(felt.Felt).SetBigInt(s))
+	}
+
+	return signature, nil
+}
+
+// Keys held by the signer, indexed by the account they sign for. The fallback key,
+// used when the signer is started with a single key, signs for any account.
+type keyRing struct {
+	mu        sync.RWMutex
+	byAccount map[felt.Felt]*accountKeys
+	fallback  *accountKeys
+}
+
+// Groups the keys by the account they sign for, keeping the order they are given in
+func newKeyRing(keys []AccountKey) (*keyRing, error) {
+	ring := &keyRing{
+		mu:        sync.RWMutex{},
+		byAccount: make(map[felt.Felt]*accountKeys, len(keys)),
+		fallback:  nil,
+	}
+	for i := range keys {
+		key := &keys[i]
+		if err := key.SignatureFormat.Validate(); err != nil {
+			return nil, fmt.Errorf("key for account %s: %w", &key.Account, err)
+		}
+
+		signers, ok := ring.byAccount[key.Account]
+		if !ok {
+			signers = &accountKeys{keys: nil, format: key.SignatureFormat, enabled: key.Enabled}
+			ring.byAccount[key.Account] = signers
+		} else if signers.format != key.SignatureFormat || signers.enabled != key.Enabled {
+			return nil, fmt.Errorf(
+				"keys for account %s should share the same enabled state and signature format",
+				&key.Account,
+			)
+		}
+
+		newKey := key.backend()
+		if slices.ContainsFunc(signers.keys, func(other KeyBackend) bool {
+			return other.PublicKey().Cmp(newKey.PublicKey()) == 0
+		}) {
+			return nil, fmt.Errorf("same key listed twice for account %s", &key.Account)
+		}
+		signers.keys = append(signers.keys, newKey)
+	}
+
+	return ring, nil
+}
+
+// Closes the backends of every key
+func (r *keyRing) close() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var backends []KeyBackend
+	if r.fallback != nil {
+		backends = append(backends, r.fallback.keys...)
+	}
+	for _, keys := range r.byAccount {
+		backends = append(backends, keys.keys...)
+	}
+
+	return closeBackends(backends)
+}
+
+func (r *keyRing) keyFor(sender *felt.Felt) (*accountKeys, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if sender != nil {
+		if key, ok := r.byAccount[*sender]; ok {
+			if !key.enabled {
+				return nil, violation(ReasonKeyDisabled, "key for account %s is disabled", sender)
+			}
+
+			return key, nil
+		}
+	}
+	if r.fallback != nil {
+		return r.fallback, nil
+	}
+
+	return nil, violation(ReasonUnknownSender, "no key for account %s", sender)
+}
+
+func (r *keyRing) setEnabled(accountAddress *felt.Felt, enabled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.byAccount[*accountAddress]
+	if !ok {
+		return fmt.Errorf("no key for account %s", accountAddress)
+	}
+	key.enabled = enabled
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package signer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiKeySigner(t *testing.T) {
+	chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+	accountA := utils.HexToFelt(t, "0xa")
+	accountB := utils.HexToFelt(t, "0xb")
+
+	remoteSigner, err := signer.NewWithAccountKeys([]signer.AccountKey{
+		{Account: *accountA, PrivateKey: big.NewInt(0x123), Enabled: true},
+		{Account: *accountB, PrivateKey: big.NewInt(0x456), Enabled: false},
+	}, utils.NewNopZapLogger())
+	require.NoError(t, err)
+
+	sign := func(t *testing.T, sender *felt.Felt) (*httptest.ResponseRecorder, *rpc.InvokeTxnV3) {
+		t.Helper()
+
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		txn.SenderAddress = sender
+		body, err := json.Marshal(&signer.Request{InvokeTxnV3: txn, ChainID: chainID})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, signer.SignEndpoint, bytes.NewReader(body))
+		remoteSigner.Handler().ServeHTTP(recorder, request)
+
+		return recorder, txn
+	}
+
+	requireRefusal := func(t *testing.T, recorder *httptest.ResponseRecorder, code signer.ReasonCode) {
+		t.Helper()
+
+		require.Equal(t, http.StatusForbidden, recorder.Code)
+		var policyViolation signer.PolicyViolation
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &policyViolation))
+		require.Equal(t, code, policyViolation.Code)
+	}
+
+	t.Run("Sign with the key of the sender", func(t *testing.T) {
+		recorder, txn := sign(t, accountA)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var resp signer.Response
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		require.Len(t, resp.Signature, 2)
+
+		txHash, err := hash.TransactionHashInvokeV3(txn, chainID)
+		require.NoError(t, err)
+		requireValidSignature(t, txHash, resp.Signature[0], resp.Signature[1], 0x123)
+	})
+
+	t.Run("Refuse unknown senders", func(t *testing.T) {
+		recorder, _ := sign(t, utils.HexToFelt(t, "0xc"))
+		requireRefusal(t, recorder, signer.ReasonUnknownSender)
+	})
+
+	t.Run("Refuse senders whose key is disabled", func(t *testing.T) {
+		recorder, _ := sign(t, accountB)
+		requireRefusal(t, recorder, signer.ReasonKeyDisabled)
+	})
+
+	t.Run("Enable and disable keys at runtime", func(t *testing.T) {
+		require.NoError(t, remoteSigner.SetKeyEnabled(accountB, true))
+		recorder, _ := sign(t, accountB)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		require.NoError(t, remoteSigner.SetKeyEnabled(accountA, false))
+		recorder, _ = sign(t, accountA)
+		requireRefusal(t, recorder, signer.ReasonKeyDisabled)
+
+		require.Error(t, remoteSigner.SetKeyEnabled(utils.HexToFelt(t, "0xc"), true))
+	})
+}
+
+func TestMultisigSigner(t *testing.T) {
+	chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+	guardedAccount := utils.HexToFelt(t, "0xa")
+	multisigAccount := utils.HexToFelt(t, "0xb")
+
+	remoteSigner, err := signer.NewWithAccountKeys([]signer.AccountKey{
+		{Account: *guardedAccount, PrivateKey: big.NewInt(0x123), Enabled: true},
+		{Account: *multisigAccount, PrivateKey: big.NewInt(0x789), Enabled: true,
+			SignatureFormat: signer.SignatureFormatKeyed},
+		{Account: *guardedAccount, PrivateKey: big.NewInt(0x456), Enabled: true},
+		{Account: *multisigAccount, PrivateKey: big.NewInt(0xabc), Enabled: true,
+			SignatureFormat: signer.SignatureFormatKeyed},
+	}, utils.NewNopZapLogger())
+	require.NoError(t, err)
+
+	sign := func(t *testing.T, sender *felt.Felt) ([]*felt.Felt, *felt.Felt) {
+		t.Helper()
+
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		txn.SenderAddress = sender
+		body, err := json.Marshal(&signer.Request{InvokeTxnV3: txn, ChainID: chainID})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, signer.SignEndpoint, bytes.NewReader(body))
+		remoteSigner.Handler().ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var resp signer.Response
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		txHash, err := hash.TransactionHashInvokeV3(txn, chainID)
+		require.NoError(t, err)
+		require.Equal(t, txHash, resp.TxHash)
+
+		return resp.Signature, txHash
+	}
+
+	t.Run("Signatures of every key one after the other", func(t *testing.T) {
+		signature, txHash := sign(t, guardedAccount)
+		require.Len(t, signature, 4)
+		requireValidSignature(t, txHash, signature[0], signature[1], 0x123)
+		requireValidSignature(t, txHash, signature[2], signature[3], 0x456)
+	})
+
+	t.Run("Signatures of every key preceded by its public key", func(t *testing.T) {
+		signature, txHash := sign(t, multisigAccount)
+		require.Len(t, signature, 6)
+		for i, privateKey := range []int64{0x789, 0xabc} {
+			publicKey, _ := curve.PrivateKeyToPoint(big.NewInt(privateKey))
+			require.Equal(t, new(felt.Felt).SetBigInt(publicKey), signature[i*3])
+			requireValidSignature(t, txHash, signature[i*3+1], signature[i*3+2], privateKey)
+		}
+	})
+
+	t.Run("Keys of an account with different formats", func(t *testing.T) {
+		_, err := signer.NewWithAccountKeys([]signer.AccountKey{
+			{Account: *guardedAccount, PrivateKey: big.NewInt(0x123), Enabled: true},
+			{Account: *guardedAccount, PrivateKey: big.NewInt(0x456), Enabled: true,
+				SignatureFormat: signer.SignatureFormatKeyed},
+		}, utils.NewNopZapLogger())
+		require.ErrorContains(t, err, "should share the same enabled state and signature format")
+	})
+}
+
+func requireValidSignature(t *testing.T, txHash, r, s *felt.Felt, privateKey int64) {
+	t.Helper()
+
+	publicKey, _ := curve.PrivateKeyToPoint(big.NewInt(privateKey))
+	valid, err := curve.VerifyFelts(txHash, r, s, new(felt.Felt).SetBigInt(publicKey))
+	require.NoError(t, err)
+	require.True(t, valid)
+}
+
+func TestLoadKeys(t *testing.T) {
+	writeKeysFile := func(t *testing.T, content string) string {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), "keys.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		return path
+	}
+
+	t.Run("Load keys given directly or as references", func(t *testing.T) {
+		t.Setenv("SIGNER_TEST_KEY", "0x456")
+		path := writeKeysFile(t, `{"keys": [
+            {"account": "0xa", "privateKey": "0x123"},
+            {"account": "0xb", "privateKey": "env:SIGNER_TEST_KEY", "disabled": true}
+        ]}`)
+
+		config, err := signer.LoadKeysConfig(path)
+		require.NoError(t, err)
+		keys, err := signer.LoadAccountKeys(t.Context(), &config)
+		require.NoError(t, err)
+
+		require.Equal(t, []signer.AccountKey{
+			{Account: *utils.HexToFelt(t, "0xa"), PrivateKey: big.NewInt(0x123), Enabled: true},
+			{Account: *utils.HexToFelt(t, "0xb"), PrivateKey: big.NewInt(0x456), Enabled: false},
+		}, keys)
+	})
+
+	t.Run("Keys for the same account with different formats", func(t *testing.T) {
+		path := writeKeysFile(t, `{"keys": [
+            {"account": "0xa", "privateKey": "0x123"},
+            {"account": "0x0a", "privateKey": "0x456", "signatureFormat": "keyed"}
+        ]}`)
+
+		_, err := signer.LoadKeysConfig(path)
+		require.ErrorContains(t, err, "should share the same disabled state and signature format")
+	})
+
+	t.Run("Unknown signature format", func(t *testing.T) {
+		path := writeKeysFile(t, `{"keys": [
+            {"account": "0xa", "privateKey": "0x123", "signatureFormat": "other"}
+        ]}`)
+
+		_, err := signer.LoadKeysConfig(path)
+		require.ErrorContains(t, err, "unknown signature format")
+	})
+
+	t.Run("Key without private key nor keystore", func(t *testing.T) {
+		path := writeKeysFile(t, `{"keys": [{"account": "0xa"}]}`)
+
+		_, err := signer.LoadKeysConfig(path)
+		require.ErrorContains(t, err, "exactly one of private key, keystore or pkcs11")
+	})
+}
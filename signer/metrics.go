@@ -0,0 +1,117 @@
+package signer
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const MetricsEndpoint = "/metrics"
+
+// Outcome of a signing request as reported in the metrics
+type requestOutcome string
+
+const (
+	outcomeSigned          requestOutcome = "signed"
+	outcomeBadRequest      requestOutcome = "bad_request"
+	outcomeUnauthenticated requestOutcome = "unauthenticated"
+	outcomeRefused         requestOutcome = "policy_refusal"
+	outcomeInternalError   requestOutcome = "internal_error"
+)
+
+func outcomeOf(signErr error) requestOutcome {
+	if signErr == nil {
+		return outcomeSigned
+	}
+	policyViolation, ok := AsPolicyViolation(signErr)
+	if !ok {
+		return outcomeInternalError
+	}
+
+	switch policyViolation.Code {
+	case ReasonInvalidRequest:
+		return outcomeBadRequest
+	case ReasonUnauthenticated:
+		return outcomeUnauthenticated
+	default:
+		return outcomeRefused
+	}
+}
+
+// Prometheus metrics of the signing requests
+type Metrics struct {
+	registry               *prometheus.Registry
+	requestCount           *prometheus.CounterVec
+	signingDuration        *prometheus.HistogramVec
+	signatureCount         *prometheus.CounterVec
+	lastSignatureTimestamp prometheus.Gauge
+}
+
+func NewMetrics() *Metrics {
+	registry := prometheus.NewRegistry()
+
+	//nolint:exhaustruct // Only specifying used fields
+	m := &Metrics{
+		registry: registry,
+		requestCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "signer_request_count",
+				Help: "The total number of signing requests received, by outcome",
+			},
+			[]string{"outcome"},
+		),
+		signingDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "signer_signing_duration_seconds",
+				Help:    "The time taken to check and sign a transaction, by outcome",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"outcome"},
+		),
+		signatureCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "signer_signature_count",
+				Help: "The total number of transactions signed, by sender",
+			},
+			[]string{"sender"},
+		),
+		lastSignatureTimestamp: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "signer_last_signature_timestamp_seconds",
+				Help: "The Unix timestamp (in seconds) of the last transaction signed",
+			},
+		),
+	}
+
+	registry.MustRegister(
+		m.requestCount,
+		m.signingDuration,
+		m.signatureCount,
+		m.lastSignatureTimestamp,
+	)
+
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	//nolint:exhaustruct // Using default values
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Records the outcome of a signing request. `req` is nil when the request couldn't be
+// decoded, in which case only the request count is updated.
+func (m *Metrics) recordRequest(req *Request, signErr error, duration time.Duration) {
+	outcome := outcomeOf(signErr)
+	m.requestCount.WithLabelValues(string(outcome)).Inc()
+	if req == nil {
+		return
+	}
+
+	m.signingDuration.WithLabelValues(string(outcome)).Observe(duration.Seconds())
+	if outcome == outcomeSigned {
+		m.signatureCount.WithLabelValues(req.sender().String()).Inc()
+		m.lastSignatureTimestamp.SetToCurrentTime()
+	}
+}
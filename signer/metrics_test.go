@@ -0,0 +1,67 @@
+package signer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+	account := utils.HexToFelt(t, "0xa")
+
+	remoteSigner, err := signer.NewWithAccountKeys([]signer.AccountKey{
+		{Account: *account, PrivateKey: big.NewInt(0x123), Enabled: true},
+	}, utils.NewNopZapLogger())
+	require.NoError(t, err)
+	remoteSigner.SetMetrics(signer.NewMetrics())
+
+	send := func(t *testing.T, body []byte) {
+		t.Helper()
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, signer.SignEndpoint, bytes.NewReader(body))
+		remoteSigner.Handler().ServeHTTP(recorder, request)
+	}
+	signRequest := func(t *testing.T, sender *felt.Felt) []byte {
+		t.Helper()
+
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		txn.SenderAddress = sender
+		body, err := json.Marshal(&signer.Request{InvokeTxnV3: txn, ChainID: chainID})
+		require.NoError(t, err)
+
+		return body
+	}
+
+	send(t, signRequest(t, account))
+	send(t, signRequest(t, account))
+	send(t, signRequest(t, utils.HexToFelt(t, "0xb")))
+	send(t, []byte("not json"))
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, signer.MetricsEndpoint, nil)
+	remoteSigner.Handler().ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	metrics := recorder.Body.String()
+	for _, expected := range []string{
+		`signer_request_count{outcome="signed"} 2`,
+		`signer_request_count{outcome="policy_refusal"} 1`,
+		`signer_request_count{outcome="bad_request"} 1`,
+		`signer_signing_duration_seconds_count{outcome="signed"} 2`,
+		`signer_signature_count{sender="0xa"} 2`,
+		`signer_last_signature_timestamp_seconds`,
+	} {
+		require.Contains(t, metrics, expected)
+	}
+}
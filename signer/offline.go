@@ -0,0 +1,135 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// Names of the functions shown for the calls of offline transactions: the staker
+// operations of the staking contract and the STRK approval preceding stake increases
+var offlineFunctions = []string{
+	"approve",
+	"stake",
+	"increase_stake",
+	"claim_rewards",
+	"unstake_intent",
+	"unstake_action",
+	"change_reward_address",
+	"change_operational_address",
+	"declare_operational_address",
+	"set_open_for_delegation",
+	"set_commission",
+}
+
+// Invoke transaction signed on a machine without network access, e.g. by the staker
+// key. It is built unsigned with `validator tx build`, signed with `signer sign-file` and
+// sent with `validator tx submit`, travelling between them as a JSON file.
+type OfflineTransaction struct {
+	ChainID     *felt.Felt                `json:"chain_id"`
+	Transaction *rpc.BroadcastInvokeTxnV3 `json:"transaction"`
+}
+
+// Reads and checks the offline transaction held by the file
+func ReadOfflineTransaction(path string) (*OfflineTransaction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read transaction file: %w", err)
+	}
+
+	var txn OfflineTransaction
+	if err := json.Unmarshal(data, &txn); err != nil {
+		return nil, fmt.Errorf("cannot decode transaction file %s: %w", path, err)
+	}
+	if err := txn.validate(); err != nil {
+		return nil, fmt.Errorf("invalid transaction file %s: %w", path, err)
+	}
+
+	return &txn, nil
+}
+
+// Writes the offline transaction to the file, replacing it if it exists
+func (t *OfflineTransaction) WriteFile(path string) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	//nolint:mnd // Only readable by its owner
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("cannot write transaction file: %w", err)
+	}
+
+	return nil
+}
+
+func (t *OfflineTransaction) validate() error {
+	txn := t.Transaction
+	switch {
+	case t.ChainID == nil:
+		return errors.New("missing chain id")
+	case txn == nil:
+		return errors.New("missing transaction")
+	case txn.Type != rpc.TransactionTypeInvoke:
+		return fmt.Errorf("transaction of type %s, expected %s", txn.Type, rpc.TransactionTypeInvoke)
+	case txn.Version != rpc.TransactionV3:
+		return fmt.Errorf("transaction of version %s, expected %s", txn.Version, rpc.TransactionV3)
+	case txn.SenderAddress == nil:
+		return errors.New("missing sender address")
+	case txn.Nonce == nil:
+		return errors.New("missing nonce")
+	case txn.ResourceBounds == nil:
+		return errors.New("missing resource bounds")
+	}
+
+	return nil
+}
+
+// Hash of the transaction, the one signed
+func (t *OfflineTransaction) Hash() (*felt.Felt, error) {
+	return hash.TransactionHashInvokeV3(t.Transaction, t.ChainID)
+}
+
+func (t *OfflineTransaction) Signed() bool {
+	return len(t.Transaction.Signature) > 0
+}
+
+// Signs the transaction with the key, setting its signature. Returns the transaction hash
+func (t *OfflineTransaction) Sign(ctx context.Context, key KeyBackend) (*felt.Felt, error) {
+	if t.Signed() {
+		return nil, errors.New("the transaction is already signed")
+	}
+
+	txnHash, err := t.Hash()
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute the transaction hash: %w", err)
+	}
+	r, s, err := key.Sign(ctx, txnHash.BigInt(new(big.Int)))
+	if err != nil {
+		return nil, err
+	}
+	t.Transaction.Signature = []*felt.Felt{
+		new(felt.Felt).SetBigInt(r), new(felt.Felt).SetBigInt(s),
+	}
+
+	return txnHash, nil
+}
+
+// Calls of the transaction as decoded from its calldata. Returns false if the calldata
+// doesn't follow the format of calls made by an account
+func (t *OfflineTransaction) Calls() ([]AuditCall, bool) {
+	knownSelectors := make(map[felt.Felt]string, len(offlineFunctions))
+	for _, function := range offlineFunctions {
+		knownSelectors[*utils.GetSelectorFromNameFelt(function)] = function
+	}
+
+	return decodeCalls(t.Transaction.Calldata, knownSelectors)
+}
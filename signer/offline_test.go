@@ -0,0 +1,86 @@
+package signer_test
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineTransaction(t *testing.T) {
+	chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+
+	newOfflineTxn := func(t *testing.T, function string) *signer.OfflineTransaction {
+		t.Helper()
+
+		return &signer.OfflineTransaction{
+			ChainID:     chainID,
+			Transaction: newAttestTxn(t, constants.SepoliaStakingContractAddress, function),
+		}
+	}
+
+	t.Run("Sign and write the transaction", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "txn.json")
+		require.NoError(t, newOfflineTxn(t, "claim_rewards").WriteFile(path))
+
+		txn, err := signer.ReadOfflineTransaction(path)
+		require.NoError(t, err)
+		require.False(t, txn.Signed())
+
+		txHash, err := txn.Sign(t.Context(), signer.NewMemoryKey(big.NewInt(0x123)))
+		require.NoError(t, err)
+		expectedHash, err := hash.TransactionHashInvokeV3(txn.Transaction, chainID)
+		require.NoError(t, err)
+		require.Equal(t, expectedHash, txHash)
+		requireValidSignature(
+			t, txHash, txn.Transaction.Signature[0], txn.Transaction.Signature[1], 0x123,
+		)
+		require.NoError(t, txn.WriteFile(path))
+
+		signed, err := signer.ReadOfflineTransaction(path)
+		require.NoError(t, err)
+		require.True(t, signed.Signed())
+		require.Equal(t, txn, signed)
+	})
+
+	t.Run("Signed transactions are not signed again", func(t *testing.T) {
+		txn := newOfflineTxn(t, "claim_rewards")
+		_, err := txn.Sign(t.Context(), signer.NewMemoryKey(big.NewInt(0x123)))
+		require.NoError(t, err)
+
+		_, err = txn.Sign(t.Context(), signer.NewMemoryKey(big.NewInt(0x123)))
+		require.ErrorContains(t, err, "already signed")
+	})
+
+	t.Run("Decode staker calls", func(t *testing.T) {
+		calls, ok := newOfflineTxn(t, "claim_rewards").Calls()
+		require.True(t, ok)
+		require.Len(t, calls, 1)
+		require.Equal(t, "claim_rewards", calls[0].Function)
+
+		calls, ok = newOfflineTxn(t, "unknown_function").Calls()
+		require.True(t, ok)
+		require.Empty(t, calls[0].Function)
+	})
+
+	t.Run("Invalid transaction file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "txn.json")
+		txn := newOfflineTxn(t, "claim_rewards")
+		txn.Transaction.Version = rpc.TransactionV3WithQueryBit
+		require.NoError(t, txn.WriteFile(path))
+
+		_, err := signer.ReadOfflineTransaction(path)
+		require.ErrorContains(t, err, "transaction of version")
+
+		require.NoError(t, os.WriteFile(path, []byte(`{"transaction": {}}`), 0o600))
+		_, err = signer.ReadOfflineTransaction(path)
+		require.ErrorContains(t, err, "missing chain id")
+	})
+}
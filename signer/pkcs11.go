@@ -0,0 +1,410 @@
+//go:build cgo
+
+package signer
+
+import (
+	"context"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/miekg/pkcs11"
+)
+
+const (
+	// Size of the IV prefixing the wrapped key
+	wrappedKeyIVSize = 12
+	// Size of the GCM authentication tag, in bits
+	wrappedKeyTagBits = 128
+	// Size of Stark field elements and of the `r` and `s` signature values
+	starkElementSize = 32
+	// Tokens may produce `r` or `s` values out of the range accepted by Starknet, in which
+	// case the hash is signed again
+	pkcs11SignAttempts = 8
+)
+
+// Upper bound, excluded, of the `r` and `s` values accepted by Starknet accounts
+var maxSignatureValue = new(big.Int).Lsh(big.NewInt(1), 251)
+
+// Modules loaded, indexed by path. A module is initialised once per process, so it is
+// shared by every key held by its tokens
+var (
+	pkcs11ModulesMu sync.Mutex
+	pkcs11Modules   = make(map[string]*pkcs11Module)
+)
+
+type pkcs11Module struct {
+	ctx *pkcs11.Ctx
+	// Number of sessions opened with the module
+	refs int
+	// Whether the module was initialised by the signer, rather than by another user of
+	// the module in the process. Only then is it finalised once the last session closes
+	owned bool
+}
+
+func loadPKCS11Module(path string) (*pkcs11.Ctx, error) {
+	pkcs11ModulesMu.Lock()
+	defer pkcs11ModulesMu.Unlock()
+
+	if module, ok := pkcs11Modules[path]; ok {
+		module.refs++
+
+		return module.ctx, nil
+	}
+
+	ctx := pkcs11.New(path)
+	if ctx == nil {
+		return nil, fmt.Errorf("cannot load pkcs11 module %s", path)
+	}
+	err := ctx.Initialize()
+	owned := err == nil
+	if err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED)) {
+		ctx.Destroy()
+
+		return nil, fmt.Errorf("cannot initialise pkcs11 module %s: %w", path, err)
+	}
+	pkcs11Modules[path] = &pkcs11Module{ctx: ctx, refs: 1, owned: owned}
+
+	return ctx, nil
+}
+
+func releasePKCS11Module(path string) error {
+	pkcs11ModulesMu.Lock()
+	defer pkcs11ModulesMu.Unlock()
+
+	module, ok := pkcs11Modules[path]
+	if !ok {
+		return nil
+	}
+	module.refs--
+	if module.refs > 0 {
+		return nil
+	}
+	delete(pkcs11Modules, path)
+	if !module.owned {
+		return nil
+	}
+	err := module.ctx.Finalize()
+	module.ctx.Destroy()
+
+	return err
+}
+
+// Session logged into the token holding a key
+type pkcs11Session struct {
+	module string
+	ctx    *pkcs11.Ctx
+	handle pkcs11.SessionHandle
+}
+
+func openPKCS11Session(ctx context.Context, config *PKCS11Config) (*pkcs11Session, error) {
+	if err := config.PIN.Resolve(ctx); err != nil {
+		return nil, fmt.Errorf("resolving pkcs11 pin: %w", err)
+	}
+
+	module, err := loadPKCS11Module(config.Module)
+	if err != nil {
+		return nil, err
+	}
+	session, err := openTokenSession(module, config)
+	if err != nil {
+		return nil, errors.Join(err, releasePKCS11Module(config.Module))
+	}
+
+	return &pkcs11Session{module: config.Module, ctx: module, handle: session}, nil
+}
+
+func openTokenSession(
+	module *pkcs11.Ctx, config *PKCS11Config,
+) (pkcs11.SessionHandle, error) {
+	slots, err := module.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("cannot list pkcs11 slots: %w", err)
+	}
+	for _, slot := range slots {
+		token, err := module.GetTokenInfo(slot)
+		if err != nil || token.Label != config.TokenLabel {
+			continue
+		}
+
+		session, err := module.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+		if err != nil {
+			return 0, fmt.Errorf("cannot open session with token %s: %w", config.TokenLabel, err)
+		}
+		err = module.Login(session, pkcs11.CKU_USER, config.PIN.Reveal())
+		if err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN)) {
+			return 0, errors.Join(
+				fmt.Errorf("cannot log into token %s: %w", config.TokenLabel, err),
+				module.CloseSession(session),
+			)
+		}
+
+		return session, nil
+	}
+
+	return 0, fmt.Errorf("no pkcs11 token labelled %s", config.TokenLabel)
+}
+
+// Returns the only object of the class with the label
+func (s *pkcs11Session) findObject(class uint, label string) (pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := s.ctx.FindObjectsInit(s.handle, template); err != nil {
+		return 0, fmt.Errorf("cannot search pkcs11 objects: %w", err)
+	}
+	objects, _, err := s.ctx.FindObjects(s.handle, 2) //nolint:mnd // Enough to spot duplicates
+	if finalErr := s.ctx.FindObjectsFinal(s.handle); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("cannot search pkcs11 objects: %w", err)
+	}
+
+	switch len(objects) {
+	case 0:
+		return 0, fmt.Errorf("no pkcs11 object labelled %s", label)
+	case 1:
+		return objects[0], nil
+	default:
+		return 0, fmt.Errorf("several pkcs11 objects labelled %s", label)
+	}
+}
+
+func (s *pkcs11Session) close() error {
+	return errors.Join(s.ctx.CloseSession(s.handle), releasePKCS11Module(s.module))
+}
+
+// Stark private key held by a token supporting the Stark curve. The key never leaves
+// the token
+type pkcs11Key struct {
+	// Guards the session, which handles a single operation at a time
+	mu        sync.Mutex
+	session   *pkcs11Session
+	key       pkcs11.ObjectHandle
+	publicKey *big.Int
+}
+
+// Opens the key held by the PKCS#11 token. With a wrapped key, the token decrypts it
+// and the key is kept in memory. Otherwise the token signs with its Stark private key,
+// whose public key is read from the public key object of the same label.
+func OpenPKCS11Key(ctx context.Context, config *PKCS11Config) (KeyBackend, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	session, err := openPKCS11Session(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.WrappedKey != "" {
+		privateKey, err := session.unwrapKey(config.KeyLabel, config.WrappedKey)
+		if closeErr := session.close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return NewMemoryKey(privateKey), nil
+	}
+
+	key, err := session.openStarkKey(config.KeyLabel)
+	if err != nil {
+		return nil, errors.Join(err, session.close())
+	}
+
+	return key, nil
+}
+
+func (s *pkcs11Session) openStarkKey(label string) (*pkcs11Key, error) {
+	privateKey, err := s.findObject(pkcs11.CKO_PRIVATE_KEY, label)
+	if err != nil {
+		return nil, err
+	}
+	publicKeyObject, err := s.findObject(pkcs11.CKO_PUBLIC_KEY, label)
+	if err != nil {
+		return nil, err
+	}
+	attributes, err := s.ctx.GetAttributeValue(
+		s.handle, publicKeyObject, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil)},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read public key %s: %w", label, err)
+	}
+	publicKey, err := parseECPoint(attributes[0].Value)
+	if err != nil {
+		return nil, fmt.Errorf("public key %s: %w", label, err)
+	}
+
+	return &pkcs11Key{
+		mu:        sync.Mutex{},
+		session:   s,
+		key:       privateKey,
+		publicKey: publicKey,
+	}, nil
+}
+
+// Returns the x coordinate of the point, given as an uncompressed point, either raw or
+// wrapped in a DER octet string as most tokens do
+func parseECPoint(value []byte) (*big.Int, error) {
+	const uncompressedSize = 1 + 2*starkElementSize
+	if len(value) != uncompressedSize {
+		var point []byte
+		if _, err := asn1.Unmarshal(value, &point); err != nil {
+			return nil, fmt.Errorf("cannot parse ec point: %w", err)
+		}
+		value = point
+	}
+	if len(value) != uncompressedSize || value[0] != 0x04 {
+		return nil, errors.New("ec point is not an uncompressed Stark curve point")
+	}
+
+	return new(big.Int).SetBytes(value[1 : 1+starkElementSize]), nil
+}
+
+func (k *pkcs11Key) PublicKey() *big.Int {
+	return k.publicKey
+}
+
+func (k *pkcs11Key) Sign(_ context.Context, msgHash *big.Int) (*big.Int, *big.Int, error) {
+	// ECDSA tokens keep the leftmost bits of the hash, as many as the curve order has.
+	// The Stark order having 252 bits, the hash is shifted so they hold its value
+	const orderBits = 252
+	if msgHash.Sign() < 0 || msgHash.BitLen() > orderBits {
+		return nil, nil, fmt.Errorf("hash %#x is out of the Stark field", msgHash)
+	}
+	digest := make([]byte, starkElementSize)
+	new(big.Int).Lsh(msgHash, starkElementSize*8-orderBits).FillBytes(digest)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for range pkcs11SignAttempts {
+		r, s, err := k.signDigest(digest)
+		if err != nil {
+			return nil, nil, err
+		}
+		if r.Cmp(maxSignatureValue) >= 0 || s.Cmp(maxSignatureValue) >= 0 {
+			continue
+		}
+		// Catches tokens handling the hash differently than expected
+		valid, err := curve.Verify(msgHash, r, s, k.publicKey)
+		if err != nil || !valid {
+			return nil, nil, errors.New("pkcs11 token produced an invalid Stark signature")
+		}
+
+		return r, s, nil
+	}
+
+	return nil, nil, errors.New("pkcs11 token kept producing out of range signatures")
+}
+
+func (k *pkcs11Key) signDigest(digest []byte) (*big.Int, *big.Int, error) {
+	ctx, session := k.session.ctx, k.session.handle
+	mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}
+	if err := ctx.SignInit(session, mechanism, k.key); err != nil {
+		return nil, nil, fmt.Errorf("pkcs11 sign init: %w", err)
+	}
+	signature, err := ctx.Sign(session, digest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("pkcs11 sign: %w", err)
+	}
+	if len(signature) != 2*starkElementSize {
+		return nil, nil, fmt.Errorf("unexpected pkcs11 signature length %d", len(signature))
+	}
+
+	return new(big.Int).SetBytes(signature[:starkElementSize]),
+		new(big.Int).SetBytes(signature[starkElementSize:]),
+		nil
+}
+
+func (k *pkcs11Key) Close() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return k.session.close()
+}
+
+// Decrypts the wrapped key file with the AES key of the label
+func (s *pkcs11Session) unwrapKey(label, path string) (*big.Int, error) {
+	wrapped, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read wrapped key: %w", err)
+	}
+	if len(wrapped) <= wrappedKeyIVSize {
+		return nil, fmt.Errorf("wrapped key %s is too short", path)
+	}
+	wrappingKey, err := s.findObject(pkcs11.CKO_SECRET_KEY, label)
+	if err != nil {
+		return nil, err
+	}
+
+	params := pkcs11.NewGCMParams(wrapped[:wrappedKeyIVSize], nil, wrappedKeyTagBits)
+	defer params.Free()
+	mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}
+	if err := s.ctx.DecryptInit(s.handle, mechanism, wrappingKey); err != nil {
+		return nil, fmt.Errorf("pkcs11 decrypt init: %w", err)
+	}
+	plainText, err := s.ctx.Decrypt(s.handle, wrapped[wrappedKeyIVSize:])
+	if err != nil {
+		return nil, fmt.Errorf("cannot unwrap key %s: %w", path, err)
+	}
+
+	return new(big.Int).SetBytes(plainText), nil
+}
+
+// Encrypts the private key with the AES key of the token, for it to be opened with
+// OpenPKCS11Key. The wrapped key is the GCM IV followed by the encrypted key and its tag
+func WrapPKCS11Key(
+	ctx context.Context, config *PKCS11Config, privateKey *big.Int,
+) ([]byte, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if privateKey.Sign() <= 0 || privateKey.BitLen() > starkElementSize*8 {
+		return nil, errors.New("private key is out of range")
+	}
+
+	session, err := openPKCS11Session(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := session.wrapKey(config.KeyLabel, privateKey)
+
+	return wrapped, errors.Join(err, session.close())
+}
+
+func (s *pkcs11Session) wrapKey(label string, privateKey *big.Int) ([]byte, error) {
+	wrappingKey, err := s.findObject(pkcs11.CKO_SECRET_KEY, label)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := s.ctx.GenerateRandom(s.handle, wrappedKeyIVSize)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11 random: %w", err)
+	}
+
+	params := pkcs11.NewGCMParams(iv, nil, wrappedKeyTagBits)
+	defer params.Free()
+	mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}
+	if err := s.ctx.EncryptInit(s.handle, mechanism, wrappingKey); err != nil {
+		return nil, fmt.Errorf("pkcs11 encrypt init: %w", err)
+	}
+	cipherText, err := s.ctx.Encrypt(s.handle, privateKey.FillBytes(make([]byte, starkElementSize)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot wrap key: %w", err)
+	}
+	// Some tokens pick the IV themselves
+	if actualIV := params.IV(); len(actualIV) == wrappedKeyIVSize {
+		iv = actualIV
+	}
+
+	return append(iv, cipherText...), nil
+}
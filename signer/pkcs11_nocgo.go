@@ -0,0 +1,20 @@
+//go:build !cgo
+
+package signer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+)
+
+// PKCS#11 modules are C libraries, only loadable by cgo builds
+var errPKCS11NoCgo = errors.New("pkcs11 keys are not supported by signers built without cgo")
+
+func OpenPKCS11Key(_ context.Context, _ *PKCS11Config) (KeyBackend, error) {
+	return nil, errPKCS11NoCgo
+}
+
+func WrapPKCS11Key(_ context.Context, _ *PKCS11Config, _ *big.Int) ([]byte, error) {
+	return nil, errPKCS11NoCgo
+}
@@ -0,0 +1,112 @@
+//go:build cgo
+
+package signer_test
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/starknet-staking-v2/secret"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/require"
+)
+
+// Runs against a SoftHSM token initialised beforehand, e.g. with
+// `softhsm2-util --init-token --free --label staking --pin 1234 --so-pin 1234`
+func TestPKCS11WrappedKey(t *testing.T) {
+	module := os.Getenv("SOFTHSM2_MODULE")
+	if module == "" {
+		t.Skip("SOFTHSM2_MODULE is not set")
+	}
+	config := signer.PKCS11Config{
+		Module:     module,
+		TokenLabel: os.Getenv("PKCS11_TOKEN_LABEL"),
+		PIN:        secret.New(os.Getenv("PKCS11_PIN")),
+		KeyLabel:   "staking-test-wrapping-key",
+		WrappedKey: filepath.Join(t.TempDir(), "wrapped.key"),
+	}
+	generateAESKey(t, &config)
+
+	privateKey := big.NewInt(0x123)
+	publicKey, _ := curve.PrivateKeyToPoint(privateKey)
+
+	wrapped, err := signer.WrapPKCS11Key(t.Context(), &config, privateKey)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(config.WrappedKey, wrapped, 0o600))
+
+	t.Run("Open the wrapped key", func(t *testing.T) {
+		key, err := signer.OpenPKCS11Key(t.Context(), &config)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, key.Close()) }()
+
+		require.Equal(t, publicKey, key.PublicKey())
+		requireValidBackendSignature(t, key, big.NewInt(0xabc))
+	})
+
+	t.Run("Tampered wrapped key", func(t *testing.T) {
+		tampered := append([]byte{}, wrapped...)
+		tampered[len(tampered)-1] ^= 1
+		tamperedConfig := config
+		tamperedConfig.WrappedKey = filepath.Join(t.TempDir(), "tampered.key")
+		require.NoError(t, os.WriteFile(tamperedConfig.WrappedKey, tampered, 0o600))
+
+		_, err := signer.OpenPKCS11Key(t.Context(), &tamperedConfig)
+		require.ErrorContains(t, err, "cannot unwrap key")
+	})
+
+	t.Run("Unknown token", func(t *testing.T) {
+		unknownConfig := config
+		unknownConfig.TokenLabel = "unknown"
+
+		_, err := signer.OpenPKCS11Key(t.Context(), &unknownConfig)
+		require.ErrorContains(t, err, "no pkcs11 token labelled unknown")
+	})
+}
+
+// Creates the AES wrapping key as a session object, removed once the test is over
+func generateAESKey(t *testing.T, config *signer.PKCS11Config) {
+	t.Helper()
+
+	ctx := pkcs11.New(config.Module)
+	require.NotNil(t, ctx)
+	require.NoError(t, ctx.Initialize())
+	t.Cleanup(func() {
+		_ = ctx.Finalize()
+		ctx.Destroy()
+	})
+
+	slots, err := ctx.GetSlotList(true)
+	require.NoError(t, err)
+	for _, slot := range slots {
+		token, err := ctx.GetTokenInfo(slot)
+		require.NoError(t, err)
+		if token.Label != config.TokenLabel {
+			continue
+		}
+
+		session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ctx.CloseSession(session) })
+		require.NoError(t, ctx.Login(session, pkcs11.CKU_USER, config.PIN.Reveal()))
+
+		_, err = ctx.GenerateKey(
+			session,
+			[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_KEY_GEN, nil)},
+			[]*pkcs11.Attribute{
+				pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+				pkcs11.NewAttribute(pkcs11.CKA_LABEL, config.KeyLabel),
+				pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, 32),
+				pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+				pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+			},
+		)
+		require.NoError(t, err)
+
+		return
+	}
+	t.Fatalf("no token labelled %s", config.TokenLabel)
+}
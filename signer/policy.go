@@ -0,0 +1,457 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/cockroachdb/errors"
+)
+
+// Identifies why a signing request was refused by the policy
+type ReasonCode string
+
+const (
+	ReasonChainIDNotAllowed      ReasonCode = "CHAIN_ID_NOT_ALLOWED"
+	ReasonSenderNotAllowed       ReasonCode = "SENDER_NOT_ALLOWED"
+	ReasonInvalidCalldata        ReasonCode = "INVALID_CALLDATA"
+	ReasonContractNotAllowed     ReasonCode = "CONTRACT_NOT_ALLOWED"
+	ReasonResourceBoundsExceeded ReasonCode = "RESOURCE_BOUNDS_EXCEEDED"
+	ReasonTipExceeded            ReasonCode = "TIP_EXCEEDED"
+	ReasonRateLimited            ReasonCode = "RATE_LIMITED"
+	ReasonDeployNotAllowed       ReasonCode = "DEPLOY_ACCOUNT_NOT_ALLOWED"
+)
+
+// Length of the calldata of a transaction made of a single attest call
+const attestCalldataLen = 5
+
+// Error returned when a signing request is refused by the policy
+type PolicyViolation struct {
+	Code   ReasonCode `json:"code"`
+	Reason string     `json:"reason"`
+}
+
+func (v *PolicyViolation) Error() string {
+	return fmt.Sprintf("policy violation %s: %s", v.Code, v.Reason)
+}
+
+func violation(code ReasonCode, format string, args ...any) *PolicyViolation {
+	return &PolicyViolation{Code: code, Reason: fmt.Sprintf(format, args...)}
+}
+
+// Limits the number of transactions signed within a time window, for each epoch, or both
+type RateLimit struct {
+	MaxSignings int    `json:"maxSignings"`
+	Window      string `json:"window"`
+	// Most transactions signed for a single epoch, as given by the epoch id of the requests
+	MaxPerEpoch int `json:"maxPerEpoch"`
+}
+
+// Restrictions on the transactions the signer accepts to sign. Empty fields don't
+// restrict anything, except for the attestation contracts which default to the
+// Mainnet and Sepolia ones.
+type PolicyConfig struct {
+	// Chain IDs given either by name (e.g. SN_MAIN) or as a hex felt
+	ChainIDs        []string `json:"chainIds"`
+	SenderAddresses []string `json:"senderAddresses"`
+	AttestContracts []string `json:"attestContracts"`
+	// Max amount and max price per unit accepted for each resource
+	MaxResourceBounds rpc.ResourceBoundsMapping `json:"maxResourceBounds"`
+	MaxTip            rpc.U64                   `json:"maxTip"`
+	RateLimit         RateLimit                 `json:"rateLimit"`
+	// Whether deploy account transactions are signed. The account deployed has to be one
+	// of the sender addresses, if set
+	AllowDeployAccount bool `json:"allowDeployAccount"`
+}
+
+func LoadPolicyConfig(path string) (PolicyConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PolicyConfig{}, err
+	}
+
+	var config PolicyConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return PolicyConfig{}, fmt.Errorf("cannot parse policy config %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+type resourceLimit struct {
+	name            string
+	maxAmount       *uint64
+	maxPricePerUnit *big.Int
+}
+
+// Checks signing requests against a `PolicyConfig`
+type Policy struct {
+	chainIDs        []felt.Felt
+	senderAddresses []felt.Felt
+	attestContracts []felt.Felt
+	attestSelector  felt.Felt
+	resourceLimits  [3]resourceLimit
+	maxTip          *uint64
+	allowDeploy     bool
+
+	maxSignings int
+	window      time.Duration
+	maxPerEpoch int
+	now         func() time.Time
+
+	mu            sync.Mutex
+	signings      []time.Time
+	epochSignings map[uint64]int
+}
+
+func NewPolicy(config *PolicyConfig) (*Policy, error) {
+	chainIDs := make([]felt.Felt, len(config.ChainIDs))
+	for i, chainID := range config.ChainIDs {
+		if strings.HasPrefix(chainID, "0x") {
+			parsed, err := new(felt.Felt).SetString(chainID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid chain id %s: %w", chainID, err)
+			}
+			chainIDs[i] = *parsed
+		} else {
+			chainIDs[i] = *new(felt.Felt).SetBytes([]byte(chainID))
+		}
+	}
+
+	senderAddresses, err := parseFelts(config.SenderAddresses)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	attestContracts := config.AttestContracts
+	if len(attestContracts) == 0 {
+		attestContracts = []string{
+			constants.MainnetAttestContractAddress, constants.SepoliaAttestContractAddress,
+		}
+	}
+	attestContractFelts, err := parseFelts(attestContracts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attest contract address: %w", err)
+	}
+
+	resourceLimits := [3]resourceLimit{}
+	bounds := []struct {
+		name   string
+		bounds rpc.ResourceBounds
+	}{
+		{"l1 gas", config.MaxResourceBounds.L1Gas},
+		{"l1 data gas", config.MaxResourceBounds.L1DataGas},
+		{"l2 gas", config.MaxResourceBounds.L2Gas},
+	}
+	for i, b := range bounds {
+		limit := resourceLimit{name: b.name, maxAmount: nil, maxPricePerUnit: nil}
+		if b.bounds.MaxAmount != "" {
+			maxAmount, err := b.bounds.MaxAmount.ToUint64()
+			if err != nil {
+				return nil, fmt.Errorf("invalid max amount for %s: %w", b.name, err)
+			}
+			limit.maxAmount = &maxAmount
+		}
+		if b.bounds.MaxPricePerUnit != "" {
+			maxPrice, err := b.bounds.MaxPricePerUnit.ToBigInt()
+			if err != nil {
+				return nil, fmt.Errorf("invalid max price per unit for %s: %w", b.name, err)
+			}
+			limit.maxPricePerUnit = maxPrice
+		}
+		resourceLimits[i] = limit
+	}
+
+	var maxTip *uint64
+	if config.MaxTip != "" {
+		tip, err := config.MaxTip.ToUint64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid max tip: %w", err)
+		}
+		maxTip = &tip
+	}
+
+	var window time.Duration
+	if config.RateLimit.MaxSignings > 0 {
+		window, err = time.ParseDuration(config.RateLimit.Window)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid rate limit window %q", config.RateLimit.Window)
+		}
+	}
+
+	return &Policy{
+		chainIDs:        chainIDs,
+		senderAddresses: senderAddresses,
+		attestContracts: attestContractFelts,
+		attestSelector:  attestSelector(),
+		resourceLimits:  resourceLimits,
+		maxTip:          maxTip,
+		allowDeploy:     config.AllowDeployAccount,
+		maxSignings:     config.RateLimit.MaxSignings,
+		window:          window,
+		maxPerEpoch:     config.RateLimit.MaxPerEpoch,
+		now:             time.Now,
+		mu:              sync.Mutex{},
+		signings:        nil,
+		epochSignings:   make(map[uint64]int),
+	}, nil
+}
+
+func attestSelector() felt.Felt {
+	return *utils.GetSelectorFromNameFelt("attest")
+}
+
+func parseFelts(values []string) ([]felt.Felt, error) {
+	felts := make([]felt.Felt, len(values))
+	for i, value := range values {
+		parsed, err := new(felt.Felt).SetString(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", value, err)
+		}
+		felts[i] = *parsed
+	}
+
+	return felts, nil
+}
+
+// Verifies the transaction satisfies the policy. The rate limits are applied separately,
+// by `Reserve`, once the transaction is about to be signed.
+func (p *Policy) Check(txn *rpc.InvokeTxnV3, chainID *felt.Felt) error {
+	if txn == nil || chainID == nil {
+		return violation(ReasonInvalidCalldata, "missing transaction or chain id")
+	}
+	if err := p.checkSender(txn.SenderAddress, chainID); err != nil {
+		return err
+	}
+	if err := p.checkCalldata(txn.Calldata); err != nil {
+		return err
+	}
+
+	return p.checkFees(txn.ResourceBounds, txn.Tip)
+}
+
+// Verifies the deploy account transaction satisfies the policy. The account deployed is
+// checked as the sender.
+func (p *Policy) CheckDeployAccount(txn *rpc.DeployAccountTxnV3, chainID *felt.Felt) error {
+	if txn == nil || chainID == nil {
+		return violation(ReasonInvalidCalldata, "missing transaction or chain id")
+	}
+	if !p.allowDeploy {
+		return violation(ReasonDeployNotAllowed, "deploy account transactions are not allowed")
+	}
+	if err := p.checkSender(deployedAddress(txn), chainID); err != nil {
+		return err
+	}
+
+	return p.checkFees(txn.ResourceBounds, txn.Tip)
+}
+
+// Counts the transaction, sent for the epoch, towards the rate limits. The slot taken
+// has to be given back with the returned function if the transaction ends up not being
+// signed. Transactions signed only for fee estimation (i.e. with the query bit version)
+// are not counted.
+func (p *Policy) Reserve(txn *rpc.InvokeTxnV3, epochID *uint64) (func(), error) {
+	if txn.Version == rpc.TransactionV3WithQueryBit {
+		return func() {}, nil
+	}
+	if p.maxPerEpoch > 0 && epochID == nil {
+		return nil, violation(
+			ReasonEpochMissing, "transactions must be sent with their epoch to be rate limited",
+		)
+	}
+
+	return p.reserve(epochID)
+}
+
+// Same as `Reserve` for deploy account transactions. They aren't sent for an epoch, so
+// only the time window applies to them.
+func (p *Policy) ReserveDeployAccount(txn *rpc.DeployAccountTxnV3) (func(), error) {
+	if txn.Version == rpc.TransactionV3WithQueryBit {
+		return func() {}, nil
+	}
+
+	return p.reserve(nil)
+}
+
+func (p *Policy) checkSender(sender, chainID *felt.Felt) error {
+	if len(p.chainIDs) > 0 && !containsFelt(p.chainIDs, chainID) {
+		return violation(ReasonChainIDNotAllowed, "chain id %s is not allowed", chainID)
+	}
+	if len(p.senderAddresses) > 0 &&
+		(sender == nil || !containsFelt(p.senderAddresses, sender)) {
+		return violation(ReasonSenderNotAllowed, "sender address %s is not allowed", sender)
+	}
+
+	return nil
+}
+
+// The calldata has to be a single `attest` call, with the block hash as its only
+// argument, to one of the allowed attestation contracts. Calls are encoded as:
+// [calls count, contract address, selector, calldata length, calldata...]
+func (p *Policy) checkCalldata(calldata []*felt.Felt) error {
+	if len(calldata) != attestCalldataLen {
+		return violation(
+			ReasonInvalidCalldata, "expected a single attest call, got %d calldata items",
+			len(calldata),
+		)
+	}
+	for _, item := range calldata {
+		if item == nil {
+			return violation(ReasonInvalidCalldata, "calldata has empty items")
+		}
+	}
+
+	callsCount, contract, selector, argsCount := calldata[0], calldata[1], calldata[2], calldata[3]
+	if !callsCount.Equal(new(felt.Felt).SetUint64(1)) {
+		return violation(ReasonInvalidCalldata, "expected a single call, got %s", callsCount)
+	}
+	if !selector.Equal(&p.attestSelector) {
+		return violation(ReasonInvalidCalldata, "call selector %s is not attest", selector)
+	}
+	if !argsCount.Equal(new(felt.Felt).SetUint64(1)) {
+		return violation(
+			ReasonInvalidCalldata, "expected a single attest argument, got %s", argsCount,
+		)
+	}
+	if !containsFelt(p.attestContracts, contract) {
+		return violation(
+			ReasonContractNotAllowed, "attest contract %s is not allowed", contract,
+		)
+	}
+
+	return nil
+}
+
+func (p *Policy) checkFees(resourceBounds *rpc.ResourceBoundsMapping, tip rpc.U64) error {
+	if resourceBounds == nil {
+		return violation(ReasonResourceBoundsExceeded, "missing resource bounds")
+	}
+
+	bounds := [3]rpc.ResourceBounds{
+		resourceBounds.L1Gas, resourceBounds.L1DataGas, resourceBounds.L2Gas,
+	}
+	for i, limit := range p.resourceLimits {
+		if limit.maxAmount != nil {
+			amount, err := bounds[i].MaxAmount.ToUint64()
+			if err != nil || amount > *limit.maxAmount {
+				return violation(
+					ReasonResourceBoundsExceeded,
+					"%s max amount %s is above %d", limit.name, bounds[i].MaxAmount, *limit.maxAmount,
+				)
+			}
+		}
+		if limit.maxPricePerUnit != nil {
+			price, err := bounds[i].MaxPricePerUnit.ToBigInt()
+			if err != nil || price.Cmp(limit.maxPricePerUnit) > 0 {
+				return violation(
+					ReasonResourceBoundsExceeded,
+					"%s max price per unit %s is above %s",
+					limit.name, bounds[i].MaxPricePerUnit, limit.maxPricePerUnit,
+				)
+			}
+		}
+	}
+
+	if p.maxTip != nil {
+		tipValue, err := tip.ToUint64()
+		if err != nil || tipValue > *p.maxTip {
+			return violation(ReasonTipExceeded, "tip %s is above %d", tip, *p.maxTip)
+		}
+	}
+
+	return nil
+}
+
+func (p *Policy) reserve(epochID *uint64) (func(), error) {
+	countEpoch := p.maxPerEpoch > 0 && epochID != nil
+	if p.maxSignings <= 0 && !countEpoch {
+		return func() {}, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if p.maxSignings > 0 {
+		recent := p.signings[:0]
+		for _, signedAt := range p.signings {
+			if now.Sub(signedAt) < p.window {
+				recent = append(recent, signedAt)
+			}
+		}
+		p.signings = recent
+
+		if len(p.signings) >= p.maxSignings {
+			return nil, violation(
+				ReasonRateLimited,
+				"already signed %d transactions in the last %s", len(p.signings), p.window,
+			)
+		}
+	}
+	if countEpoch && p.epochSignings[*epochID] >= p.maxPerEpoch {
+		return nil, violation(
+			ReasonRateLimited,
+			"already signed %d transactions in epoch %d", p.epochSignings[*epochID], *epochID,
+		)
+	}
+
+	if p.maxSignings > 0 {
+		p.signings = append(p.signings, now)
+	}
+	if countEpoch {
+		p.epochSignings[*epochID]++
+		// Attestations are only accepted for the current epoch, older ones aren't needed
+		for epoch := range p.epochSignings {
+			if epoch+1 < *epochID {
+				delete(p.epochSignings, epoch)
+			}
+		}
+	}
+
+	return func() { p.release(now, epochID, countEpoch) }, nil
+}
+
+// Gives back a slot reserved at the time, for the epoch if counted
+func (p *Policy) release(reservedAt time.Time, epochID *uint64, countEpoch bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.maxSignings > 0 {
+		for i := len(p.signings) - 1; i >= 0; i-- {
+			if p.signings[i].Equal(reservedAt) {
+				p.signings = append(p.signings[:i], p.signings[i+1:]...)
+
+				break
+			}
+		}
+	}
+	if countEpoch && p.epochSignings[*epochID] > 0 {
+		p.epochSignings[*epochID]--
+	}
+}
+
+func containsFelt(felts []felt.Felt, value *felt.Felt) bool {
+	for i := range felts {
+		if felts[i].Equal(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the policy violation wrapped in err, if any
+func AsPolicyViolation(err error) (*PolicyViolation, bool) {
+	var v *PolicyViolation
+	ok := errors.As(err, &v)
+
+	return v, ok
+}
@@ -0,0 +1,311 @@
+package signer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	snUtils "github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func newAttestTxn(t *testing.T, contract, function string) *rpc.InvokeTxnV3 {
+	t.Helper()
+
+	calls := snUtils.InvokeFuncCallsToFunctionCalls([]rpc.InvokeFunctionCall{{
+		ContractAddress: utils.HexToFelt(t, contract),
+		FunctionName:    function,
+		CallData:        []*felt.Felt{utils.HexToFelt(t, "0xabc")},
+	}})
+
+	return &rpc.InvokeTxnV3{
+		Type:          rpc.TransactionTypeInvoke,
+		SenderAddress: utils.HexToFelt(t, "0x123"),
+		Calldata:      account.FmtCallDataCairo2(calls),
+		Version:       rpc.TransactionV3,
+		Signature:     []*felt.Felt{},
+		Nonce:         new(felt.Felt).SetUint64(1),
+		ResourceBounds: &rpc.ResourceBoundsMapping{
+			L1Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x100"},
+			L1DataGas: rpc.ResourceBounds{MaxAmount: "0x100", MaxPricePerUnit: "0x100"},
+			L2Gas:     rpc.ResourceBounds{MaxAmount: "0x1000", MaxPricePerUnit: "0x100"},
+		},
+		Tip:                   "0x10",
+		PayMasterData:         []*felt.Felt{},
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         rpc.DAModeL1,
+		FeeMode:               rpc.DAModeL1,
+	}
+}
+
+// Deploy account transaction of an OpenZeppelin account owned by the key "0x123". Returns
+// it along with the account address
+func newDeployAccountTxn(t *testing.T) (*rpc.DeployAccountTxnV3, *felt.Felt) {
+	t.Helper()
+
+	// Public key for private key "0x123"
+	publicKey := utils.HexToFelt(
+		t, "0x566d69d8c99f62bc71118399bab25c1f03719463eab8d6a444cd11ece131616",
+	)
+	accountClass, err := signer.NewAccountClass(signer.AccountTypeOpenZeppelin, nil)
+	require.NoError(t, err)
+
+	return &rpc.DeployAccountTxnV3{
+		Type:                rpc.TransactionTypeDeployAccount,
+		Version:             rpc.TransactionV3,
+		Signature:           []*felt.Felt{},
+		Nonce:               new(felt.Felt),
+		ContractAddressSalt: publicKey,
+		ConstructorCalldata: accountClass.ConstructorCalldata(publicKey),
+		ClassHash:           accountClass.ClassHash,
+		ResourceBounds: &rpc.ResourceBoundsMapping{
+			L1Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x100"},
+			L1DataGas: rpc.ResourceBounds{MaxAmount: "0x100", MaxPricePerUnit: "0x100"},
+			L2Gas:     rpc.ResourceBounds{MaxAmount: "0x1000", MaxPricePerUnit: "0x100"},
+		},
+		Tip:           "0x10",
+		PayMasterData: []*felt.Felt{},
+		NonceDataMode: rpc.DAModeL1,
+		FeeMode:       rpc.DAModeL1,
+	}, accountClass.Address(publicKey, publicKey)
+}
+
+func requireViolation(t *testing.T, err error, code signer.ReasonCode) {
+	t.Helper()
+
+	policyViolation, ok := signer.AsPolicyViolation(err)
+	require.True(t, ok, "expected a policy violation, got %v", err)
+	require.Equal(t, code, policyViolation.Code)
+}
+
+func TestPolicy(t *testing.T) {
+	sepoliaChainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+
+	//nolint:exhaustruct // Only specifying used fields
+	policy, err := signer.NewPolicy(&signer.PolicyConfig{
+		ChainIDs:        []string{"SN_SEPOLIA"},
+		SenderAddresses: []string{"0x123"},
+		MaxResourceBounds: rpc.ResourceBoundsMapping{
+			L2Gas: rpc.ResourceBounds{MaxAmount: "0x1000", MaxPricePerUnit: "0x100"},
+		},
+		MaxTip: "0x10",
+	})
+	require.NoError(t, err)
+
+	t.Run("Attest transaction is accepted", func(t *testing.T) {
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		require.NoError(t, policy.Check(txn, sepoliaChainID))
+	})
+
+	t.Run("Chain id not allowed", func(t *testing.T) {
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		mainnetChainID := new(felt.Felt).SetBytes([]byte("SN_MAIN"))
+		requireViolation(t, policy.Check(txn, mainnetChainID), signer.ReasonChainIDNotAllowed)
+	})
+
+	t.Run("Sender not allowed", func(t *testing.T) {
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		txn.SenderAddress = utils.HexToFelt(t, "0x456")
+		requireViolation(t, policy.Check(txn, sepoliaChainID), signer.ReasonSenderNotAllowed)
+	})
+
+	t.Run("Call to a function other than attest", func(t *testing.T) {
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "transfer")
+		requireViolation(t, policy.Check(txn, sepoliaChainID), signer.ReasonInvalidCalldata)
+	})
+
+	t.Run("Attest call to a contract not allowed", func(t *testing.T) {
+		txn := newAttestTxn(t, constants.StrkContractAddress, "attest")
+		requireViolation(t, policy.Check(txn, sepoliaChainID), signer.ReasonContractNotAllowed)
+	})
+
+	t.Run("More than one call", func(t *testing.T) {
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		txn.Calldata = append(txn.Calldata, txn.Calldata[1:]...)
+		txn.Calldata[0] = new(felt.Felt).SetUint64(2)
+		requireViolation(t, policy.Check(txn, sepoliaChainID), signer.ReasonInvalidCalldata)
+	})
+
+	t.Run("Resource bounds above the limit", func(t *testing.T) {
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		txn.ResourceBounds.L2Gas.MaxPricePerUnit = "0x101"
+		requireViolation(
+			t, policy.Check(txn, sepoliaChainID), signer.ReasonResourceBoundsExceeded,
+		)
+	})
+
+	t.Run("Tip above the limit", func(t *testing.T) {
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		txn.Tip = "0x11"
+		requireViolation(t, policy.Check(txn, sepoliaChainID), signer.ReasonTipExceeded)
+	})
+}
+
+func TestPolicyDeployAccount(t *testing.T) {
+	sepoliaChainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+	txn, address := newDeployAccountTxn(t)
+
+	t.Run("Deploy account not allowed", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		policy, err := signer.NewPolicy(&signer.PolicyConfig{})
+		require.NoError(t, err)
+		requireViolation(
+			t, policy.CheckDeployAccount(txn, sepoliaChainID), signer.ReasonDeployNotAllowed,
+		)
+	})
+
+	t.Run("Deploy of an allowed sender address", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		policy, err := signer.NewPolicy(&signer.PolicyConfig{
+			SenderAddresses:    []string{address.String()},
+			AllowDeployAccount: true,
+		})
+		require.NoError(t, err)
+		require.NoError(t, policy.CheckDeployAccount(txn, sepoliaChainID))
+	})
+
+	t.Run("Deploy of another address", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		policy, err := signer.NewPolicy(&signer.PolicyConfig{
+			SenderAddresses:    []string{"0x123"},
+			AllowDeployAccount: true,
+		})
+		require.NoError(t, err)
+		requireViolation(
+			t, policy.CheckDeployAccount(txn, sepoliaChainID), signer.ReasonSenderNotAllowed,
+		)
+	})
+
+	t.Run("Deploy with a tip above the limit", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		policy, err := signer.NewPolicy(&signer.PolicyConfig{
+			MaxTip:             "0xf",
+			AllowDeployAccount: true,
+		})
+		require.NoError(t, err)
+		requireViolation(t, policy.CheckDeployAccount(txn, sepoliaChainID), signer.ReasonTipExceeded)
+	})
+}
+
+func TestPolicyRateLimit(t *testing.T) {
+	chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+	txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+	epoch := func(epochID uint64) *uint64 { return &epochID }
+
+	t.Run("Signings within the time window", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		policy, err := signer.NewPolicy(&signer.PolicyConfig{
+			RateLimit: signer.RateLimit{MaxSignings: 2, Window: "1h"},
+		})
+		require.NoError(t, err)
+
+		// Signings for fee estimation are not counted
+		estimateTxn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		estimateTxn.Version = rpc.TransactionV3WithQueryBit
+		for range 3 {
+			_, err := policy.Reserve(estimateTxn, nil)
+			require.NoError(t, err)
+		}
+
+		_, err = policy.Reserve(txn, nil)
+		require.NoError(t, err)
+		_, err = policy.Reserve(txn, nil)
+		require.NoError(t, err)
+		_, err = policy.Reserve(txn, nil)
+		requireViolation(t, err, signer.ReasonRateLimited)
+	})
+
+	t.Run("Signings per epoch", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		policy, err := signer.NewPolicy(&signer.PolicyConfig{
+			RateLimit: signer.RateLimit{MaxPerEpoch: 1},
+		})
+		require.NoError(t, err)
+
+		_, err = policy.Reserve(txn, epoch(10))
+		require.NoError(t, err)
+		_, err = policy.Reserve(txn, epoch(10))
+		requireViolation(t, err, signer.ReasonRateLimited)
+		_, err = policy.Reserve(txn, epoch(11))
+		require.NoError(t, err)
+
+		_, err = policy.Reserve(txn, nil)
+		requireViolation(t, err, signer.ReasonEpochMissing)
+	})
+
+	t.Run("Released slots can be used again", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		policy, err := signer.NewPolicy(&signer.PolicyConfig{
+			RateLimit: signer.RateLimit{MaxSignings: 1, Window: "1h", MaxPerEpoch: 1},
+		})
+		require.NoError(t, err)
+
+		release, err := policy.Reserve(txn, epoch(10))
+		require.NoError(t, err)
+		release()
+
+		_, err = policy.Reserve(txn, epoch(10))
+		require.NoError(t, err)
+		_, err = policy.Reserve(txn, epoch(10))
+		requireViolation(t, err, signer.ReasonRateLimited)
+	})
+
+	t.Run("Refused requests don't use up the rate limit", func(t *testing.T) {
+		account := utils.HexToFelt(t, "0x123")
+		remoteSigner, err := signer.NewWithAccountKeys([]signer.AccountKey{
+			{Account: *account, PrivateKey: big.NewInt(0x123), Enabled: true},
+		}, utils.NewNopZapLogger())
+		require.NoError(t, err)
+		//nolint:exhaustruct // Only specifying used fields
+		policy, err := signer.NewPolicy(&signer.PolicyConfig{
+			RateLimit: signer.RateLimit{MaxSignings: 1, Window: "1h"},
+		})
+		require.NoError(t, err)
+		remoteSigner.SetPolicy(policy)
+
+		sign := func(t *testing.T, sender *felt.Felt) int {
+			t.Helper()
+
+			txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+			txn.SenderAddress = sender
+			body, err := json.Marshal(&signer.Request{InvokeTxnV3: txn, ChainID: chainID})
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, signer.SignEndpoint, bytes.NewReader(body))
+			remoteSigner.Handler().ServeHTTP(recorder, request)
+
+			return recorder.Code
+		}
+
+		// No key for the sender
+		require.Equal(t, http.StatusForbidden, sign(t, utils.HexToFelt(t, "0x456")))
+		require.Equal(t, http.StatusOK, sign(t, account))
+		require.Equal(t, http.StatusForbidden, sign(t, account))
+	})
+}
+
+func TestNewPolicy(t *testing.T) {
+	t.Run("Rate limit without a window", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		_, err := signer.NewPolicy(&signer.PolicyConfig{
+			RateLimit: signer.RateLimit{MaxSignings: 1},
+		})
+		require.ErrorContains(t, err, "invalid rate limit window")
+	})
+
+	t.Run("Invalid sender address", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		_, err := signer.NewPolicy(&signer.PolicyConfig{SenderAddresses: []string{"0xzz"}})
+		require.ErrorContains(t, err, "invalid sender address")
+	})
+}
@@ -0,0 +1,322 @@
+package signer
+
+import (
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet-staking-v2/signer/signerpb"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// Conversions between the signer types and their gRPC messages
+
+func feltToProto(value *felt.Felt) string {
+	if value == nil {
+		return ""
+	}
+
+	return value.String()
+}
+
+// Returns nil for an empty value
+func feltFromProto(value, name string) (*felt.Felt, error) {
+	if value == "" {
+		return nil, nil //nolint:nilnil // Fields not set in the message
+	}
+	parsed, err := new(felt.Felt).SetString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	return parsed, nil
+}
+
+func feltsToProto(values []*felt.Felt) []string {
+	encoded := make([]string, len(values))
+	for i, value := range values {
+		encoded[i] = feltToProto(value)
+	}
+
+	return encoded
+}
+
+func feltsFromProto(values []string, name string) ([]*felt.Felt, error) {
+	parsed := make([]*felt.Felt, len(values))
+	for i, value := range values {
+		var err error
+		if parsed[i], err = feltFromProto(value, name); err != nil {
+			return nil, err
+		}
+	}
+
+	return parsed, nil
+}
+
+var dataAvailabilityModes = map[rpc.DataAvailabilityMode]signerpb.DataAvailabilityMode{
+	rpc.DAModeL1: signerpb.DataAvailabilityMode_DATA_AVAILABILITY_MODE_L1,
+	rpc.DAModeL2: signerpb.DataAvailabilityMode_DATA_AVAILABILITY_MODE_L2,
+}
+
+func dataAvailabilityModeFromProto(
+	mode signerpb.DataAvailabilityMode,
+) (rpc.DataAvailabilityMode, error) {
+	for rpcMode, protoMode := range dataAvailabilityModes {
+		if protoMode == mode {
+			return rpcMode, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid data availability mode %s", mode)
+}
+
+func resourceBoundsToProto(bounds *rpc.ResourceBounds) *signerpb.ResourceBounds {
+	return &signerpb.ResourceBounds{
+		MaxAmount:       string(bounds.MaxAmount),
+		MaxPricePerUnit: string(bounds.MaxPricePerUnit),
+	}
+}
+
+func resourceBoundsFromProto(bounds *signerpb.ResourceBounds) rpc.ResourceBounds {
+	return rpc.ResourceBounds{
+		MaxAmount:       rpc.U64(bounds.GetMaxAmount()),
+		MaxPricePerUnit: rpc.U128(bounds.GetMaxPricePerUnit()),
+	}
+}
+
+func transactionToProto(txn *rpc.InvokeTxnV3) *signerpb.InvokeTransactionV3 {
+	if txn == nil {
+		return nil
+	}
+
+	//nolint:exhaustruct // Resource bounds are only set when present
+	message := &signerpb.InvokeTransactionV3{
+		SenderAddress:             feltToProto(txn.SenderAddress),
+		Calldata:                  feltsToProto(txn.Calldata),
+		Version:                   string(txn.Version),
+		Signature:                 feltsToProto(txn.Signature),
+		Nonce:                     feltToProto(txn.Nonce),
+		Tip:                       string(txn.Tip),
+		PaymasterData:             feltsToProto(txn.PayMasterData),
+		AccountDeploymentData:     feltsToProto(txn.AccountDeploymentData),
+		NonceDataAvailabilityMode: dataAvailabilityModes[txn.NonceDataMode],
+		FeeDataAvailabilityMode:   dataAvailabilityModes[txn.FeeMode],
+	}
+	if txn.ResourceBounds != nil {
+		message.ResourceBounds = &signerpb.ResourceBoundsMapping{
+			L1Gas:     resourceBoundsToProto(&txn.ResourceBounds.L1Gas),
+			L1DataGas: resourceBoundsToProto(&txn.ResourceBounds.L1DataGas),
+			L2Gas:     resourceBoundsToProto(&txn.ResourceBounds.L2Gas),
+		}
+	}
+
+	return message
+}
+
+func transactionFromProto(message *signerpb.InvokeTransactionV3) (*rpc.InvokeTxnV3, error) {
+	if message == nil {
+		return nil, nil //nolint:nilnil // Not set in hash mode
+	}
+
+	var err error
+	//nolint:exhaustruct // Remaining fields are parsed below
+	txn := &rpc.InvokeTxnV3{
+		Type:    rpc.TransactionTypeInvoke,
+		Version: rpc.TransactionVersion(message.GetVersion()),
+		Tip:     rpc.U64(message.GetTip()),
+	}
+	txn.SenderAddress, err = feltFromProto(message.GetSenderAddress(), "sender address")
+	if err != nil {
+		return nil, err
+	}
+	if txn.Calldata, err = feltsFromProto(message.GetCalldata(), "calldata"); err != nil {
+		return nil, err
+	}
+	if txn.Signature, err = feltsFromProto(message.GetSignature(), "signature"); err != nil {
+		return nil, err
+	}
+	if txn.Nonce, err = feltFromProto(message.GetNonce(), "nonce"); err != nil {
+		return nil, err
+	}
+	txn.PayMasterData, err = feltsFromProto(message.GetPaymasterData(), "paymaster data")
+	if err != nil {
+		return nil, err
+	}
+	txn.AccountDeploymentData, err = feltsFromProto(
+		message.GetAccountDeploymentData(), "account deployment data",
+	)
+	if err != nil {
+		return nil, err
+	}
+	if txn.NonceDataMode, err = dataAvailabilityModeFromProto(
+		message.GetNonceDataAvailabilityMode(),
+	); err != nil {
+		return nil, err
+	}
+	if txn.FeeMode, err = dataAvailabilityModeFromProto(
+		message.GetFeeDataAvailabilityMode(),
+	); err != nil {
+		return nil, err
+	}
+	if bounds := message.GetResourceBounds(); bounds != nil {
+		txn.ResourceBounds = &rpc.ResourceBoundsMapping{
+			L1Gas:     resourceBoundsFromProto(bounds.GetL1Gas()),
+			L1DataGas: resourceBoundsFromProto(bounds.GetL1DataGas()),
+			L2Gas:     resourceBoundsFromProto(bounds.GetL2Gas()),
+		}
+	}
+
+	return txn, nil
+}
+
+var signModes = map[SignMode]signerpb.SignMode{
+	"":                  signerpb.SignMode_SIGN_MODE_UNSPECIFIED,
+	SignModeTransaction: signerpb.SignMode_SIGN_MODE_TRANSACTION,
+	SignModeHash:        signerpb.SignMode_SIGN_MODE_HASH,
+}
+
+func (r *Request) ToProto() *signerpb.SignRequest {
+	return &signerpb.SignRequest{
+		ProtocolVersion: uint32(r.Protocol), //nolint:gosec // Small positive version numbers
+		Transaction:     transactionToProto(r.InvokeTxnV3),
+		ChainId:         feltToProto(r.ChainID),
+		EpochId:         r.EpochID,
+		Mode:            signModes[r.Mode],
+		Hash:            feltToProto(r.Hash),
+		Account:         feltToProto(r.Account),
+	}
+}
+
+func RequestFromProto(message *signerpb.SignRequest) (Request, error) {
+	var err error
+	//nolint:exhaustruct // Remaining fields are parsed below
+	req := Request{
+		Protocol: ProtocolVersion(message.GetProtocolVersion()),
+		EpochID:  message.EpochId,
+	}
+	for mode, protoMode := range signModes {
+		if protoMode == message.GetMode() {
+			req.Mode = mode
+		}
+	}
+	if req.InvokeTxnV3, err = transactionFromProto(message.GetTransaction()); err != nil {
+		return Request{}, err
+	}
+	if req.ChainID, err = feltFromProto(message.GetChainId(), "chain id"); err != nil {
+		return Request{}, err
+	}
+	if req.Hash, err = feltFromProto(message.GetHash(), "hash"); err != nil {
+		return Request{}, err
+	}
+	if req.Account, err = feltFromProto(message.GetAccount(), "account"); err != nil {
+		return Request{}, err
+	}
+
+	return req, nil
+}
+
+func (r *Response) ToProto() *signerpb.SignResponse {
+	return &signerpb.SignResponse{
+		ProtocolVersion: uint32(r.Protocol), //nolint:gosec // Small positive version numbers
+		Signature:       feltsToProto(r.Signature),
+		TransactionHash: feltToProto(r.TxHash),
+	}
+}
+
+func ResponseFromProto(message *signerpb.SignResponse) (Response, error) {
+	signature, err := feltsFromProto(message.GetSignature(), "signature")
+	if err != nil {
+		return Response{}, err
+	}
+	txHash, err := feltFromProto(message.GetTransactionHash(), "transaction hash")
+	if err != nil {
+		return Response{}, err
+	}
+
+	return Response{
+		Protocol:  ProtocolVersion(message.GetProtocolVersion()),
+		Signature: signature,
+		TxHash:    txHash,
+	}, nil
+}
+
+func (r *PublicKeysResponse) ToProto() *signerpb.GetPublicKeysResponse {
+	keys := make([]*signerpb.AccountPublicKeys, len(r.Keys))
+	for i := range r.Keys {
+		keys[i] = &signerpb.AccountPublicKeys{
+			Account:         feltToProto(r.Keys[i].Account),
+			PublicKeys:      feltsToProto(r.Keys[i].PublicKeys),
+			SignatureFormat: string(r.Keys[i].SignatureFormat),
+			Enabled:         r.Keys[i].Enabled,
+		}
+	}
+
+	return &signerpb.GetPublicKeysResponse{Keys: keys}
+}
+
+func PublicKeysResponseFromProto(
+	message *signerpb.GetPublicKeysResponse,
+) (PublicKeysResponse, error) {
+	keys := make([]AccountPublicKeys, len(message.GetKeys()))
+	for i, accountKeys := range message.GetKeys() {
+		account, err := feltFromProto(accountKeys.GetAccount(), "account")
+		if err != nil {
+			return PublicKeysResponse{}, err
+		}
+		publicKeys, err := feltsFromProto(accountKeys.GetPublicKeys(), "public key")
+		if err != nil {
+			return PublicKeysResponse{}, err
+		}
+		keys[i] = AccountPublicKeys{
+			Account:         account,
+			PublicKeys:      publicKeys,
+			SignatureFormat: SignatureFormat(accountKeys.GetSignatureFormat()),
+			Enabled:         accountKeys.GetEnabled(),
+		}
+	}
+
+	return PublicKeysResponse{Keys: keys}, nil
+}
+
+func (c *Capabilities) ToProto() *signerpb.GetCapabilitiesResponse {
+	message := &signerpb.GetCapabilitiesResponse{
+		ProtocolVersions:         make([]uint32, len(c.ProtocolVersions)),
+		VariableLengthSignatures: c.VariableLengthSignatures,
+		HashOnly:                 c.HashOnly,
+		TransactionTypes:         make([]string, len(c.TransactionTypes)),
+		TransactionVersions:      make([]string, len(c.TransactionVersions)),
+	}
+	for i, version := range c.ProtocolVersions {
+		message.ProtocolVersions[i] = uint32(version) //nolint:gosec // Small positive numbers
+	}
+	for i, txnType := range c.TransactionTypes {
+		message.TransactionTypes[i] = string(txnType)
+	}
+	for i, version := range c.TransactionVersions {
+		message.TransactionVersions[i] = string(version)
+	}
+
+	return message
+}
+
+func CapabilitiesFromProto(message *signerpb.GetCapabilitiesResponse) Capabilities {
+	capabilities := Capabilities{
+		ProtocolVersions:         make([]ProtocolVersion, len(message.GetProtocolVersions())),
+		VariableLengthSignatures: message.GetVariableLengthSignatures(),
+		HashOnly:                 message.GetHashOnly(),
+		TransactionTypes:         make([]rpc.TransactionType, len(message.GetTransactionTypes())),
+		TransactionVersions: make(
+			[]rpc.TransactionVersion, len(message.GetTransactionVersions()),
+		),
+	}
+	for i, version := range message.GetProtocolVersions() {
+		capabilities.ProtocolVersions[i] = ProtocolVersion(version)
+	}
+	for i, txnType := range message.GetTransactionTypes() {
+		capabilities.TransactionTypes[i] = rpc.TransactionType(txnType)
+	}
+	for i, version := range message.GetTransactionVersions() {
+		capabilities.TransactionVersions[i] = rpc.TransactionVersion(version)
+	}
+
+	return capabilities
+}
@@ -0,0 +1,188 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+const CapabilitiesEndpoint = "/capabilities"
+
+const ReasonHashOnlyNotAllowed ReasonCode = "HASH_ONLY_NOT_ALLOWED"
+
+// Version of the `/sign` request and response format
+type ProtocolVersion int
+
+const (
+	// The transaction is always sent in full. Requests without a version are v1
+	ProtocolV1 ProtocolVersion = 1
+	// Adds the protocol version to requests and responses, the hash-only mode and
+	// deploy account transactions
+	ProtocolV2 ProtocolVersion = 2
+)
+
+// Protocol versions understood by this signer, from oldest to newest
+var SupportedProtocolVersions = []ProtocolVersion{ProtocolV1, ProtocolV2}
+
+// What a v2 request asks the signer to sign
+type SignMode string
+
+const (
+	// Hash and sign the transaction. The default
+	SignModeTransaction SignMode = "transaction"
+	// Sign the given hash for the given account, without seeing the transaction. The
+	// signing policy and history can't be applied to it.
+	SignModeHash SignMode = "hash"
+)
+
+// Features supported by a signer, returned by its capabilities endpoint
+type Capabilities struct {
+	ProtocolVersions []ProtocolVersion `json:"protocol_versions"`
+	// Signatures can be made of any number of felts, not only `r` and `s`
+	VariableLengthSignatures bool                     `json:"variable_length_signatures"`
+	HashOnly                 bool                     `json:"hash_only"`
+	TransactionTypes         []rpc.TransactionType    `json:"transaction_types"`
+	TransactionVersions      []rpc.TransactionVersion `json:"transaction_versions"`
+}
+
+// Capabilities assumed for signers without a capabilities endpoint
+func V1Capabilities() Capabilities {
+	return Capabilities{
+		ProtocolVersions:         []ProtocolVersion{ProtocolV1},
+		VariableLengthSignatures: false,
+		HashOnly:                 false,
+		TransactionTypes:         []rpc.TransactionType{rpc.TransactionTypeInvoke},
+		TransactionVersions: []rpc.TransactionVersion{
+			rpc.TransactionV3, rpc.TransactionV3WithQueryBit,
+		},
+	}
+}
+
+// Returns the newest protocol version supported by both the signer and the client, false
+// if they have none in common
+func (c *Capabilities) Negotiate(clientVersions []ProtocolVersion) (ProtocolVersion, bool) {
+	var negotiated ProtocolVersion
+	for _, version := range c.ProtocolVersions {
+		if version > negotiated && slices.Contains(clientVersions, version) {
+			negotiated = version
+		}
+	}
+
+	return negotiated, negotiated != 0
+}
+
+func (c *Capabilities) SupportsTransaction(
+	txnType rpc.TransactionType, version rpc.TransactionVersion,
+) bool {
+	return slices.Contains(c.TransactionTypes, txnType) &&
+		slices.Contains(c.TransactionVersions, version)
+}
+
+func (r *Request) protocolVersion() ProtocolVersion {
+	if r.Protocol == 0 {
+		return ProtocolV1
+	}
+
+	return r.Protocol
+}
+
+// Checks the request has the fields required by its protocol version and mode
+func (r *Request) validate() error {
+	version := r.protocolVersion()
+	if !slices.Contains(SupportedProtocolVersions, version) {
+		return fmt.Errorf("unsupported protocol version %d", version)
+	}
+
+	switch r.Mode {
+	case "", SignModeTransaction:
+		if r.DeployAccountTxnV3 != nil {
+			return r.validateDeployAccount(version)
+		}
+		if r.InvokeTxnV3 == nil || r.ChainID == nil {
+			return errors.New("missing transaction or chain id")
+		}
+		if version >= ProtocolV2 {
+			capabilities := V1Capabilities()
+			txnVersion := r.InvokeTxnV3.Version
+			if !capabilities.SupportsTransaction(rpc.TransactionTypeInvoke, txnVersion) {
+				return fmt.Errorf("unsupported transaction version %s", txnVersion)
+			}
+		}
+	case SignModeHash:
+		if version < ProtocolV2 {
+			return fmt.Errorf("sign mode %q requires protocol version %d", r.Mode, ProtocolV2)
+		}
+		if r.Hash == nil || r.Account == nil {
+			return errors.New("missing hash or account")
+		}
+	default:
+		return fmt.Errorf("unknown sign mode %q", r.Mode)
+	}
+
+	return nil
+}
+
+func (r *Request) validateDeployAccount(version ProtocolVersion) error {
+	if version < ProtocolV2 {
+		return fmt.Errorf(
+			"deploy account transactions require protocol version %d", ProtocolV2,
+		)
+	}
+	if r.InvokeTxnV3 != nil {
+		return errors.New("request has both an invoke and a deploy account transaction")
+	}
+	if r.ChainID == nil {
+		return errors.New("missing chain id")
+	}
+	txn := r.DeployAccountTxnV3
+	if txn.ClassHash == nil || txn.ContractAddressSalt == nil {
+		return errors.New("missing class hash or contract address salt")
+	}
+	if !slices.Contains(V1Capabilities().TransactionVersions, txn.Version) {
+		return fmt.Errorf("unsupported transaction version %s", txn.Version)
+	}
+
+	return nil
+}
+
+// Account the request signs for. For deploy account transactions, the account being
+// deployed
+func (r *Request) sender() *felt.Felt {
+	if r.Mode == SignModeHash {
+		return r.Account
+	}
+	if r.DeployAccountTxnV3 != nil {
+		return deployedAddress(r.DeployAccountTxnV3)
+	}
+	if r.InvokeTxnV3 == nil {
+		return nil
+	}
+
+	return r.SenderAddress
+}
+
+func (s *Signer) capabilities() Capabilities {
+	capabilities := V1Capabilities()
+	capabilities.ProtocolVersions = SupportedProtocolVersions
+	capabilities.VariableLengthSignatures = true
+	capabilities.HashOnly = s.hashOnlyAllowed()
+	capabilities.TransactionTypes = append(
+		capabilities.TransactionTypes, rpc.TransactionTypeDeployAccount,
+	)
+
+	return capabilities
+}
+
+// Hash-only requests can't be checked against the policy nor the history, so they are
+// refused when either is set
+func (s *Signer) hashOnlyAllowed() bool {
+	return s.allowHashOnly && s.policy == nil && s.history == nil
+}
+
+func (s *Signer) capabilitiesHandler(w http.ResponseWriter, _ *http.Request) {
+	s.writeJSON(w, http.StatusOK, s.capabilities())
+}
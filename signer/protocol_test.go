@@ -0,0 +1,184 @@
+package signer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocolVersions(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+	chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+	account := utils.HexToFelt(t, "0x123")
+	msgHash := utils.HexToFelt(t, "0xabcdef")
+
+	newSigner := func(t *testing.T) *signer.Signer {
+		t.Helper()
+
+		remoteSigner, err := signer.NewWithAccountKeys([]signer.AccountKey{
+			{Account: *account, PrivateKey: big.NewInt(0x123), Enabled: true},
+		}, logger)
+		require.NoError(t, err)
+
+		return &remoteSigner
+	}
+
+	send := func(
+		t *testing.T, remoteSigner *signer.Signer, req *signer.Request,
+	) *httptest.ResponseRecorder {
+		t.Helper()
+
+		body, err := json.Marshal(req)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, signer.SignEndpoint, bytes.NewReader(body))
+		remoteSigner.Handler().ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	decode := func(t *testing.T, recorder *httptest.ResponseRecorder) signer.Response {
+		t.Helper()
+
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		var resp signer.Response
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+
+		return resp
+	}
+
+	t.Run("Capabilities", func(t *testing.T) {
+		remoteSigner := newSigner(t)
+		remoteSigner.SetAllowHashOnly(true)
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, signer.CapabilitiesEndpoint, nil)
+		remoteSigner.Handler().ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var capabilities signer.Capabilities
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &capabilities))
+		require.Equal(t, signer.SupportedProtocolVersions, capabilities.ProtocolVersions)
+		require.True(t, capabilities.VariableLengthSignatures)
+		require.True(t, capabilities.HashOnly)
+		require.True(t, capabilities.SupportsTransaction(
+			rpc.TransactionTypeInvoke, rpc.TransactionV3WithQueryBit,
+		))
+		require.True(t, capabilities.SupportsTransaction(
+			rpc.TransactionTypeDeployAccount, rpc.TransactionV3,
+		))
+
+		version, ok := capabilities.Negotiate([]signer.ProtocolVersion{signer.ProtocolV1})
+		require.True(t, ok)
+		require.Equal(t, signer.ProtocolV1, version)
+		_, ok = capabilities.Negotiate([]signer.ProtocolVersion{3})
+		require.False(t, ok)
+	})
+
+	t.Run("V1 request", func(t *testing.T) {
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		resp := decode(t, send(t, newSigner(t), &signer.Request{InvokeTxnV3: txn, ChainID: chainID}))
+
+		txHash, err := hash.TransactionHashInvokeV3(txn, chainID)
+		require.NoError(t, err)
+		require.Zero(t, resp.Protocol)
+		require.Equal(t, txHash, resp.TxHash)
+		requireValidSignature(t, txHash, resp.Signature[0], resp.Signature[1], 0x123)
+	})
+
+	t.Run("V2 request", func(t *testing.T) {
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		resp := decode(t, send(t, newSigner(t), &signer.Request{
+			Protocol: signer.ProtocolV2, InvokeTxnV3: txn, ChainID: chainID,
+		}))
+		require.Equal(t, signer.ProtocolV2, resp.Protocol)
+	})
+
+	t.Run("Unsupported protocol version", func(t *testing.T) {
+		txn := newAttestTxn(t, constants.SepoliaAttestContractAddress, "attest")
+		recorder := send(t, newSigner(t), &signer.Request{
+			Protocol: 3, InvokeTxnV3: txn, ChainID: chainID,
+		})
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		require.Contains(t, recorder.Body.String(), "unsupported protocol version 3")
+	})
+
+	t.Run("Hash only", func(t *testing.T) {
+		remoteSigner := newSigner(t)
+		remoteSigner.SetAllowHashOnly(true)
+
+		resp := decode(t, send(t, remoteSigner, &signer.Request{
+			Protocol: signer.ProtocolV2, Mode: signer.SignModeHash, Hash: msgHash, Account: account,
+		}))
+		require.Equal(t, msgHash, resp.TxHash)
+		requireValidSignature(t, msgHash, resp.Signature[0], resp.Signature[1], 0x123)
+	})
+
+	t.Run("Hash only not allowed", func(t *testing.T) {
+		recorder := send(t, newSigner(t), &signer.Request{
+			Protocol: signer.ProtocolV2, Mode: signer.SignModeHash, Hash: msgHash, Account: account,
+		})
+		require.Equal(t, http.StatusForbidden, recorder.Code)
+		require.Contains(t, recorder.Body.String(), string(signer.ReasonHashOnlyNotAllowed))
+	})
+
+	t.Run("Hash only with a policy", func(t *testing.T) {
+		remoteSigner := newSigner(t)
+		remoteSigner.SetAllowHashOnly(true)
+		policy, err := signer.NewPolicy(&signer.PolicyConfig{ChainIDs: []string{"SN_SEPOLIA"}})
+		require.NoError(t, err)
+		remoteSigner.SetPolicy(policy)
+
+		recorder := send(t, remoteSigner, &signer.Request{
+			Protocol: signer.ProtocolV2, Mode: signer.SignModeHash, Hash: msgHash, Account: account,
+		})
+		require.Equal(t, http.StatusForbidden, recorder.Code)
+		require.Contains(t, recorder.Body.String(), string(signer.ReasonHashOnlyNotAllowed))
+	})
+
+	t.Run("Hash only requires v2", func(t *testing.T) {
+		remoteSigner := newSigner(t)
+		remoteSigner.SetAllowHashOnly(true)
+
+		recorder := send(t, remoteSigner, &signer.Request{
+			Mode: signer.SignModeHash, Hash: msgHash, Account: account,
+		})
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		require.Contains(t, recorder.Body.String(), "requires protocol version 2")
+	})
+
+	t.Run("Deploy account", func(t *testing.T) {
+		txn, address := newDeployAccountTxn(t)
+		remoteSigner, err := signer.NewWithAccountKeys([]signer.AccountKey{
+			{Account: *address, PrivateKey: big.NewInt(0x123), Enabled: true},
+		}, logger)
+		require.NoError(t, err)
+
+		resp := decode(t, send(t, &remoteSigner, &signer.Request{
+			Protocol: signer.ProtocolV2, DeployAccountTxnV3: txn, ChainID: chainID,
+		}))
+
+		txHash, err := hash.TransactionHashDeployAccountV3(txn, address, chainID)
+		require.NoError(t, err)
+		require.Equal(t, txHash, resp.TxHash)
+		requireValidSignature(t, txHash, resp.Signature[0], resp.Signature[1], 0x123)
+	})
+
+	t.Run("Deploy account requires v2", func(t *testing.T) {
+		txn, _ := newDeployAccountTxn(t)
+		recorder := send(t, newSigner(t), &signer.Request{DeployAccountTxnV3: txn, ChainID: chainID})
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+		require.Contains(t, recorder.Body.String(), "require protocol version 2")
+	})
+}
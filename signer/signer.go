@@ -2,17 +2,17 @@ package signer
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"math/big"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/utils"
-	"github.com/NethermindEth/starknet.go/account"
-	"github.com/NethermindEth/starknet.go/curve"
 	"github.com/NethermindEth/starknet.go/hash"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/cockroachdb/errors"
@@ -20,95 +20,316 @@ import (
 
 const SignEndpoint = "/sign"
 
+// Largest request body accepted, well above the size of any transaction to sign
+const MaxRequestBodySize = 1 << 20
+
+// Time given to in-flight requests to finish when shutting down
+const ShutdownTimeout = 15 * time.Second
+
+var errNotRecorded = errors.New("failed to record signature")
+
 type Request struct {
-	*rpc.InvokeTxnV3 `json:"transaction"`
-	ChainID          *felt.Felt `json:"chain_id"`
+	// Not set by v1 clients
+	Protocol         ProtocolVersion `json:"version,omitempty"`
+	*rpc.InvokeTxnV3 `json:"transaction,omitempty"`
+	ChainID          *felt.Felt `json:"chain_id,omitempty"`
+	// Epoch the transaction attests for. Used to refuse conflicting attestations
+	EpochID *uint64 `json:"epoch_id,omitempty"`
+	// From v2, requests in hash mode give the hash to sign and the account signing it
+	// instead of the transaction
+	Mode    SignMode   `json:"mode,omitempty"`
+	Hash    *felt.Felt `json:"hash,omitempty"`
+	Account *felt.Felt `json:"account,omitempty"`
+	// From v2, a deploy account transaction can be sent instead of an invoke one
+	DeployAccountTxnV3 *rpc.DeployAccountTxnV3 `json:"deploy_account_transaction,omitempty"`
 }
 
 type Response struct {
-	Signature [2]*felt.Felt `json:"signature"`
+	// Same as the request's. Not set in v1 responses
+	Protocol ProtocolVersion `json:"version,omitempty"`
+	// Usually the ECDSA `r` and `s` values, longer for accounts combining several keys
+	Signature []*felt.Felt `json:"signature"`
+	// Hash the signature is for, letting clients cross-check it with their own
+	TxHash *felt.Felt `json:"transaction_hash,omitempty"`
 }
 
 func (r *Response) String() string {
-	return fmt.Sprintf(
-		`{r: %s, s: %s}`,
-		r.Signature[0],
-		r.Signature[1],
-	)
+	return fmt.Sprintf(`{signature: %s, hash: %s}`, r.Signature, r.TxHash)
 }
 
 type Signer struct {
-	logger    *utils.ZapLogger
-	keyStore  *account.MemKeystore
-	publicKey string
+	logger *utils.ZapLogger
+	keys   *keyRing
+	// If set, only authenticated clients can request signatures
+	authenticator *Authenticator
+	// If set, the signer is served over TLS
+	tlsConfig *tls.Config
+	// If set, only transactions satisfying the policy are signed
+	policy *Policy
+	// If set, transactions conflicting with already signed ones are refused
+	history *History
+	// If set, every signing request is recorded in it
+	auditLog *AuditLog
+	// If set, signing requests are reported in it
+	metrics *Metrics
+	// Whether hash mode requests are accepted
+	allowHashOnly bool
+	// Set once the server starts shutting down
+	shuttingDown *atomic.Bool
 }
 
 func New(privateKey string, logger *utils.ZapLogger) (Signer, error) {
 	privKey, ok := new(big.Int).SetString(privateKey, 0)
 	if !ok {
-		return Signer{}, errors.Errorf("cannot turn private key %s into a big int", privateKey)
+		// The private key value is purposely left out of the error
+		return Signer{}, errors.New("cannot turn private key into a big int")
+	}
+
+	return NewWithKey(privKey, logger), nil
+}
+
+// Creates a signer from an already parsed private key, e.g. one loaded from a keystore.
+// The key signs transactions of any sender.
+func NewWithKey(privateKey *big.Int, logger *utils.ZapLogger) Signer {
+	return NewWithKeyBackend(NewMemoryKey(privateKey), logger)
+}
+
+// Creates a signer from the key held by the backend, e.g. a PKCS#11 token. The key signs
+// transactions of any sender.
+func NewWithKeyBackend(key KeyBackend, logger *utils.ZapLogger) Signer {
+	//nolint:exhaustruct // Only specifying used fields
+	fallback := &accountKeys{keys: []KeyBackend{key}, enabled: true}
+
+	//nolint:exhaustruct // Only specifying used fields
+	return newSigner(&keyRing{fallback: fallback}, logger)
+}
+
+// Creates a signer holding several keys, each one signing only for its account.
+// Requests from any other sender are refused. Keys of the same account are combined
+// into a single signature.
+func NewWithAccountKeys(keys []AccountKey, logger *utils.ZapLogger) (Signer, error) {
+	ring, err := newKeyRing(keys)
+	if err != nil {
+		return Signer{}, err
 	}
 
-	publicKey, _ := curve.PrivateKeyToPoint(privKey)
-	publicKeyStr := publicKey.String()
-	ks := account.SetNewMemKeystore(publicKeyStr, privKey)
+	return newSigner(ring, logger), nil
+}
 
+func newSigner(keys *keyRing, logger *utils.ZapLogger) Signer {
 	return Signer{
-		logger:    logger,
-		keyStore:  ks,
-		publicKey: publicKeyStr,
-	}, nil
+		logger:        logger,
+		keys:          keys,
+		authenticator: nil,
+		tlsConfig:     nil,
+		policy:        nil,
+		history:       nil,
+		auditLog:      nil,
+		metrics:       nil,
+		allowHashOnly: false,
+		shuttingDown:  new(atomic.Bool),
+	}
 }
 
-// Listen for requests of the type `POST` at `<address>/sign`. The request
-// should include the hash of the transaction being signed.
-func (s *Signer) Listen(address string) error {
+// Releases the key backends. The signer cannot sign afterwards
+func (s *Signer) Close() error {
+	return s.keys.close()
+}
+
+// Enables or disables signing for an account, taking effect on the next request
+func (s *Signer) SetKeyEnabled(accountAddress *felt.Felt, enabled bool) error {
+	return s.keys.setEnabled(accountAddress, enabled)
+}
+
+// Requires every request to be authenticated by one of the authenticator clients
+func (s *Signer) SetAuthenticator(authenticator *Authenticator) {
+	s.authenticator = authenticator
+}
+
+// Serves the signer over TLS with the given configuration
+func (s *Signer) SetTLSConfig(config *tls.Config) {
+	s.tlsConfig = config
+}
+
+// Refuses to sign transactions that don't satisfy the policy
+func (s *Signer) SetPolicy(policy *Policy) {
+	s.policy = policy
+}
+
+// Records every signed transaction, refusing the ones conflicting with the history
+func (s *Signer) SetHistory(history *History) {
+	s.history = history
+}
+
+// Records every signing request, whether it is signed or refused, in the audit log
+func (s *Signer) SetAuditLog(auditLog *AuditLog) {
+	s.auditLog = auditLog
+}
+
+// Reports signing requests in the metrics, served at `/metrics`
+func (s *Signer) SetMetrics(metrics *Metrics) {
+	s.metrics = metrics
+}
+
+// Accepts requests in hash mode, signing a hash without seeing the transaction. They are
+// still refused when a policy or a history is set.
+func (s *Signer) SetAllowHashOnly(allow bool) {
+	s.allowHashOnly = allow
+}
+
+// Returns the handler serving all the signer endpoints. Health, readiness, version,
+// capabilities and metrics endpoints don't require authentication.
+func (s *Signer) Handler() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc(SignEndpoint, s.handler)
+	mux.HandleFunc("GET "+PublicKeyEndpoint, s.publicKeysHandler)
+
+	var handler http.Handler = mux
+	if s.authenticator != nil {
+		handler = s.authenticator.middleware(mux, func(r *http.Request, err error) {
+			if r.URL.Path == SignEndpoint {
+				unauthenticated := violation(ReasonUnauthenticated, "%s", err)
+				_ = s.record(sourceOf(r), nil, nil, unauthenticated, 0)
+			}
+		})
+	}
+
+	root := http.NewServeMux()
+	root.HandleFunc("GET "+HealthEndpoint, s.healthHandler)
+	root.HandleFunc("GET "+ReadyEndpoint, s.readyHandler)
+	root.HandleFunc("GET "+VersionEndpoint, s.versionHandler)
+	root.HandleFunc("GET "+CapabilitiesEndpoint, s.capabilitiesHandler)
+	if s.metrics != nil {
+		root.Handle("GET "+MetricsEndpoint, s.metrics.Handler())
+	}
+	root.Handle("/", handler)
+
+	return root
+}
+
+// Listen for requests of the type `POST` at `<address>/sign`. The request
+// should include the hash of the transaction being signed. The address is either a TCP
+// one or a `unix:///path/to/socket` one, to serve over a Unix domain socket.
+func (s *Signer) Listen(address string) error {
+	return s.Serve(context.Background(), address)
+}
 
+// Same as `Listen`, shutting down once the context is done. Requests already being
+// answered are given `ShutdownTimeout` to finish.
+func (s *Signer) Serve(ctx context.Context, address string) error {
 	//nolint:exhaustruct // Only specifying used fields
 	server := &http.Server{
 		Addr:         address,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
-		Handler:      mux,
+		Handler:      s.Handler(),
+		TLSConfig:    s.tlsConfig,
 	}
 
-	s.logger.Infof("server running at %s", address)
+	_, unixSocket := SocketPath(address)
+	if unixSocket && s.tlsConfig != nil {
+		return errors.New("tls is not supported over a unix socket")
+	}
+
+	if s.authenticator == nil {
+		s.logger.Warnf("authentication is disabled, any client reaching the signer can use it")
+	}
+
+	listener, err := listen(address)
+	if err != nil {
+		return err
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if s.tlsConfig == nil {
+			s.logger.Infof("server running at %s", address)
+			serveErr <- server.Serve(listener)
+
+			return
+		}
 
-	return server.ListenAndServe()
+		s.logger.Infof(
+			"server running at %s over tls, client certificates required: %t",
+			address,
+			s.tlsConfig.ClientAuth == tls.RequireAndVerifyClientCert,
+		)
+		// Certificates are already part of the server tls config
+		serveErr <- server.ServeTLS(listener, "", "")
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.logger.Infof("shutting down, waiting for in-flight requests to finish")
+	s.shuttingDown.Store(true)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("cannot shut down gracefully: %w", err)
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	s.logger.Infof("server stopped")
+
+	return nil
 }
 
 // Decodes the request and returns ECDSA `r` and `s` signature values via http
 func (s *Signer) handler(w http.ResponseWriter, r *http.Request) {
 	s.logger.Debugw("receiving http request", "request", r)
+	start := time.Now()
 
 	defer func() { _ = r.Body.Close() }()
 
-	body, err := io.ReadAll(r.Body)
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxRequestBodySize))
 	if err != nil {
-		http.Error(w, "failed to read request body: "+err.Error(), http.StatusBadRequest)
+		readErr := violation(ReasonInvalidRequest, "failed to read request body: %s", err)
+		_ = s.record(sourceOf(r), nil, nil, readErr, 0)
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, "failed to read request body: "+err.Error(), status)
 
 		return
 	}
 
 	var req Request
 	err = json.Unmarshal(body, &req)
+	if err == nil {
+		err = req.validate()
+	}
 	if err != nil {
+		decodeErr := violation(ReasonInvalidRequest, "failed to decode request body: %s", err)
+		_ = s.record(sourceOf(r), nil, nil, decodeErr, 0)
 		http.Error(w, "failed to decode request body: "+err.Error(), http.StatusBadRequest)
 
 		return
 	}
 
-	signature, err := s.hashAndSign(req.InvokeTxnV3, req.ChainID)
+	resp, err := s.answer(sourceOf(r), &req, start)
 	if err != nil {
-		http.Error(w, "failed to sign tx: "+err.Error(), http.StatusInternalServerError)
+		policyViolation, refused := AsPolicyViolation(err)
+		switch {
+		case refused:
+			s.refuse(w, policyViolation)
+		case errors.Is(err, errNotRecorded):
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		default:
+			http.Error(w, "failed to sign tx: "+err.Error(), http.StatusInternalServerError)
+		}
 
 		return
 	}
 
-	resp := Response{Signature: signature}
 	w.Header().Set("Content-Type", "application/json")
 	err = json.NewEncoder(w).Encode(resp)
 	if err != nil {
@@ -120,29 +341,223 @@ func (s *Signer) handler(w http.ResponseWriter, r *http.Request) {
 	s.logger.Debugw("answered http request", "response", resp)
 }
 
-// Given a transaction hash returns the ECDSA `r` and `s` signature values
-func (s *Signer) hashAndSign(
+// Signs a decoded request and records its outcome. The signature is only returned once
+// it is recorded.
+func (s *Signer) answer(source requestSource, req *Request, start time.Time) (Response, error) {
+	txnHash, signature, release, err := s.sign(req)
+	auditErr := s.record(source, req, txnHash, err, time.Since(start))
+	if auditErr != nil && err == nil {
+		// A signature is never released without being recorded, nor counted by the policy
+		release()
+
+		return Response{}, fmt.Errorf("%w: %w", errNotRecorded, auditErr)
+	}
+	if err != nil {
+		if policyViolation, ok := AsPolicyViolation(err); ok {
+			s.logger.Warnw(
+				"refused to sign transaction",
+				"client", source.client,
+				"code", policyViolation.Code,
+				"reason", policyViolation.Reason,
+			)
+		}
+
+		return Response{}, err
+	}
+
+	//nolint:exhaustruct // The protocol version is only answered from v2
+	resp := Response{Signature: signature, TxHash: txnHash}
+	if req.protocolVersion() >= ProtocolV2 {
+		resp.Protocol = req.Protocol
+	}
+
+	return resp, nil
+}
+
+// Answers a request refused by the policy with the reason code of the refusal
+func (s *Signer) refuse(w http.ResponseWriter, policyViolation *PolicyViolation) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	if err := json.NewEncoder(w).Encode(policyViolation); err != nil {
+		s.logger.Errorf("encoding policy violation %s: %s", policyViolation, err)
+	}
+}
+
+// Who sent a signing request
+type requestSource struct {
+	// Empty if authentication is disabled
+	client        string
+	remoteAddress string
+}
+
+func sourceOf(r *http.Request) requestSource {
+	return requestSource{client: ClientIdentity(r.Context()), remoteAddress: r.RemoteAddr}
+}
+
+// Records the outcome of a signing request in the audit log and the metrics, if set.
+// Returns the error writing the audit log.
+func (s *Signer) record(
+	source requestSource,
+	req *Request,
+	txnHash *felt.Felt,
+	signErr error,
+	duration time.Duration,
+) error {
+	var auditErr error
+	if s.auditLog != nil {
+		entry := s.auditLog.newEntry(source, req, txnHash, signErr)
+		if auditErr = s.auditLog.Append(&entry); auditErr != nil {
+			s.logger.Errorw("cannot write audit log", "error", auditErr)
+		}
+	}
+
+	if s.metrics != nil {
+		// A signature that couldn't be audited is not released
+		outcomeErr := signErr
+		if outcomeErr == nil {
+			outcomeErr = auditErr
+		}
+		s.metrics.recordRequest(req, outcomeErr, duration)
+	}
+
+	return auditErr
+}
+
+// Checks the transaction against the policy and, if satisfied, signs it. The transaction
+// hash is returned whenever it could be computed, even if the transaction is refused.
+// The returned function gives back the slot the signature took in the rate limits of the
+// policy, in case it isn't released.
+func (s *Signer) sign(req *Request) (*felt.Felt, []*felt.Felt, func(), error) {
+	if req.Mode == SignModeHash {
+		signature, err := s.signHashOnly(req.Hash, req.Account)
+
+		return req.Hash, signature, func() {}, err
+	}
+	if req.DeployAccountTxnV3 != nil {
+		return s.signDeployAccount(req.DeployAccountTxnV3, req.ChainID)
+	}
+
+	// Transactions the hash can't be computed of might still be refused by the policy
+	txnHash, hashErr := hash.TransactionHashInvokeV3(req.InvokeTxnV3, req.ChainID)
+
+	release := func() {}
+	if s.policy != nil {
+		if err := s.policy.Check(req.InvokeTxnV3, req.ChainID); err != nil {
+			return txnHash, nil, release, err
+		}
+	}
+	if hashErr != nil {
+		return nil, nil, release, hashErr
+	}
+	if s.policy != nil {
+		var err error
+		if release, err = s.policy.Reserve(req.InvokeTxnV3, req.EpochID); err != nil {
+			return txnHash, nil, func() {}, err
+		}
+	}
+
+	signature, err := s.signHash(req.InvokeTxnV3, req.ChainID, txnHash, req.EpochID)
+	if err != nil {
+		// Refused requests don't use up the rate limits
+		release()
+
+		return txnHash, nil, func() {}, err
+	}
+
+	return txnHash, signature, release, nil
+}
+
+// Given a transaction hash returns the signature of the keys of the sender, usually
+// the ECDSA `r` and `s` values
+func (s *Signer) signHash(
 	invokeTxnV3 *rpc.InvokeTxnV3,
 	chainID *felt.Felt,
-) ([2]*felt.Felt, error) {
+	txnHash *felt.Felt,
+	epochID *uint64,
+) ([]*felt.Felt, error) {
 	s.logger.Infow("Signing transaction", "transaction", invokeTxnV3, "chainId", chainID)
 
-	txnHash, err := hash.TransactionHashInvokeV3(invokeTxnV3, chainID)
+	keys, err := s.keys.keyFor(invokeTxnV3.SenderAddress)
 	if err != nil {
-		return [2]*felt.Felt{}, err
+		return nil, err
+	}
+
+	if s.history != nil {
+		if err := s.history.Register(invokeTxnV3, chainID, txnHash, epochID); err != nil {
+			return nil, err
+		}
 	}
 
 	hashBig := txnHash.BigInt(new(big.Int))
 
-	s1, s2, err := s.keyStore.Sign(context.Background(), s.publicKey, hashBig)
+	signature, err := keys.sign(context.Background(), hashBig)
 	if err != nil {
-		return [2]*felt.Felt{}, err
+		return nil, err
 	}
 
-	s.logger.Debugw("Signature", "r", s1, "s", s2)
+	s.logger.Debugw("Signature", "signature", signature)
+
+	return signature, nil
+}
+
+// Checks the deploy account transaction against the policy and, if satisfied, signs it
+// with the keys of the account being deployed. Deployments don't attest, so they aren't
+// registered in the history.
+func (s *Signer) signDeployAccount(
+	txn *rpc.DeployAccountTxnV3, chainID *felt.Felt,
+) (*felt.Felt, []*felt.Felt, func(), error) {
+	address := deployedAddress(txn)
+	txnHash, hashErr := hash.TransactionHashDeployAccountV3(txn, address, chainID)
+
+	release := func() {}
+	if s.policy != nil {
+		if err := s.policy.CheckDeployAccount(txn, chainID); err != nil {
+			return txnHash, nil, release, err
+		}
+	}
+	if hashErr != nil {
+		return nil, nil, release, hashErr
+	}
+	if s.policy != nil {
+		var err error
+		if release, err = s.policy.ReserveDeployAccount(txn); err != nil {
+			return txnHash, nil, func() {}, err
+		}
+	}
+
+	s.logger.Infow(
+		"Signing deploy account transaction",
+		"transaction", txn,
+		"address", address,
+		"chainId", chainID,
+	)
+	keys, err := s.keys.keyFor(address)
+	if err != nil {
+		release()
+
+		return txnHash, nil, func() {}, err
+	}
+	signature, err := keys.sign(context.Background(), txnHash.BigInt(new(big.Int)))
+	if err != nil {
+		release()
+
+		return txnHash, nil, func() {}, err
+	}
+
+	return txnHash, signature, release, nil
+}
+
+// Signs a hash for the account without seeing the transaction it is for
+func (s *Signer) signHashOnly(txnHash, account *felt.Felt) ([]*felt.Felt, error) {
+	if !s.hashOnlyAllowed() {
+		return nil, violation(ReasonHashOnlyNotAllowed, "signing a hash alone is not allowed")
+	}
+	s.logger.Infow("Signing hash", "hash", txnHash, "account", account)
+
+	keys, err := s.keys.keyFor(account)
+	if err != nil {
+		return nil, err
+	}
 
-	return [2]*felt.Felt{
-		new(felt.Felt).SetBigInt(s1),
-		new(felt.Felt).SetBigInt(s2),
-	}, nil
+	return keys.sign(context.Background(), txnHash.BigInt(new(big.Int)))
 }
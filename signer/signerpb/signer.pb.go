@@ -0,0 +1,930 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: signer/signerpb/signer.proto
+
+// gRPC interface of the remote signer. It has the same semantics as the HTTP endpoints:
+// `Sign` is `POST /sign`, `GetPublicKeys` is `GET /public-key` and `GetCapabilities` is
+// `GET /capabilities`. Health is served by the standard `grpc.health.v1.Health` service.
+//
+// Felts and other Starknet numbers are hex encoded strings, as in the Starknet JSON-RPC.
+//
+// Regenerate the Go code with `make signer-proto`.
+
+package signerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DataAvailabilityMode int32
+
+const (
+	DataAvailabilityMode_DATA_AVAILABILITY_MODE_UNSPECIFIED DataAvailabilityMode = 0
+	DataAvailabilityMode_DATA_AVAILABILITY_MODE_L1          DataAvailabilityMode = 1
+	DataAvailabilityMode_DATA_AVAILABILITY_MODE_L2          DataAvailabilityMode = 2
+)
+
+// Enum value maps for DataAvailabilityMode.
+var (
+	DataAvailabilityMode_name = map[int32]string{
+		0: "DATA_AVAILABILITY_MODE_UNSPECIFIED",
+		1: "DATA_AVAILABILITY_MODE_L1",
+		2: "DATA_AVAILABILITY_MODE_L2",
+	}
+	DataAvailabilityMode_value = map[string]int32{
+		"DATA_AVAILABILITY_MODE_UNSPECIFIED": 0,
+		"DATA_AVAILABILITY_MODE_L1":          1,
+		"DATA_AVAILABILITY_MODE_L2":          2,
+	}
+)
+
+func (x DataAvailabilityMode) Enum() *DataAvailabilityMode {
+	p := new(DataAvailabilityMode)
+	*p = x
+	return p
+}
+
+func (x DataAvailabilityMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DataAvailabilityMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_signer_signerpb_signer_proto_enumTypes[0].Descriptor()
+}
+
+func (DataAvailabilityMode) Type() protoreflect.EnumType {
+	return &file_signer_signerpb_signer_proto_enumTypes[0]
+}
+
+func (x DataAvailabilityMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DataAvailabilityMode.Descriptor instead.
+func (DataAvailabilityMode) EnumDescriptor() ([]byte, []int) {
+	return file_signer_signerpb_signer_proto_rawDescGZIP(), []int{0}
+}
+
+type SignMode int32
+
+const (
+	// Same as `SIGN_MODE_TRANSACTION`
+	SignMode_SIGN_MODE_UNSPECIFIED SignMode = 0
+	SignMode_SIGN_MODE_TRANSACTION SignMode = 1
+	SignMode_SIGN_MODE_HASH        SignMode = 2
+)
+
+// Enum value maps for SignMode.
+var (
+	SignMode_name = map[int32]string{
+		0: "SIGN_MODE_UNSPECIFIED",
+		1: "SIGN_MODE_TRANSACTION",
+		2: "SIGN_MODE_HASH",
+	}
+	SignMode_value = map[string]int32{
+		"SIGN_MODE_UNSPECIFIED": 0,
+		"SIGN_MODE_TRANSACTION": 1,
+		"SIGN_MODE_HASH":        2,
+	}
+)
+
+func (x SignMode) Enum() *SignMode {
+	p := new(SignMode)
+	*p = x
+	return p
+}
+
+func (x SignMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SignMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_signer_signerpb_signer_proto_enumTypes[1].Descriptor()
+}
+
+func (SignMode) Type() protoreflect.EnumType {
+	return &file_signer_signerpb_signer_proto_enumTypes[1]
+}
+
+func (x SignMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SignMode.Descriptor instead.
+func (SignMode) EnumDescriptor() ([]byte, []int) {
+	return file_signer_signerpb_signer_proto_rawDescGZIP(), []int{1}
+}
+
+type ResourceBounds struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MaxAmount       string                 `protobuf:"bytes,1,opt,name=max_amount,json=maxAmount,proto3" json:"max_amount,omitempty"`
+	MaxPricePerUnit string                 `protobuf:"bytes,2,opt,name=max_price_per_unit,json=maxPricePerUnit,proto3" json:"max_price_per_unit,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ResourceBounds) Reset() {
+	*x = ResourceBounds{}
+	mi := &file_signer_signerpb_signer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResourceBounds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceBounds) ProtoMessage() {}
+
+func (x *ResourceBounds) ProtoReflect() protoreflect.Message {
+	mi := &file_signer_signerpb_signer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceBounds.ProtoReflect.Descriptor instead.
+func (*ResourceBounds) Descriptor() ([]byte, []int) {
+	return file_signer_signerpb_signer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ResourceBounds) GetMaxAmount() string {
+	if x != nil {
+		return x.MaxAmount
+	}
+	return ""
+}
+
+func (x *ResourceBounds) GetMaxPricePerUnit() string {
+	if x != nil {
+		return x.MaxPricePerUnit
+	}
+	return ""
+}
+
+type ResourceBoundsMapping struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	L1Gas         *ResourceBounds        `protobuf:"bytes,1,opt,name=l1_gas,json=l1Gas,proto3" json:"l1_gas,omitempty"`
+	L1DataGas     *ResourceBounds        `protobuf:"bytes,2,opt,name=l1_data_gas,json=l1DataGas,proto3" json:"l1_data_gas,omitempty"`
+	L2Gas         *ResourceBounds        `protobuf:"bytes,3,opt,name=l2_gas,json=l2Gas,proto3" json:"l2_gas,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResourceBoundsMapping) Reset() {
+	*x = ResourceBoundsMapping{}
+	mi := &file_signer_signerpb_signer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResourceBoundsMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceBoundsMapping) ProtoMessage() {}
+
+func (x *ResourceBoundsMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_signer_signerpb_signer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceBoundsMapping.ProtoReflect.Descriptor instead.
+func (*ResourceBoundsMapping) Descriptor() ([]byte, []int) {
+	return file_signer_signerpb_signer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ResourceBoundsMapping) GetL1Gas() *ResourceBounds {
+	if x != nil {
+		return x.L1Gas
+	}
+	return nil
+}
+
+func (x *ResourceBoundsMapping) GetL1DataGas() *ResourceBounds {
+	if x != nil {
+		return x.L1DataGas
+	}
+	return nil
+}
+
+func (x *ResourceBoundsMapping) GetL2Gas() *ResourceBounds {
+	if x != nil {
+		return x.L2Gas
+	}
+	return nil
+}
+
+type InvokeTransactionV3 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SenderAddress string                 `protobuf:"bytes,1,opt,name=sender_address,json=senderAddress,proto3" json:"sender_address,omitempty"`
+	Calldata      []string               `protobuf:"bytes,2,rep,name=calldata,proto3" json:"calldata,omitempty"`
+	// Either `0x3` or its query bit version
+	Version                   string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Signature                 []string               `protobuf:"bytes,4,rep,name=signature,proto3" json:"signature,omitempty"`
+	Nonce                     string                 `protobuf:"bytes,5,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	ResourceBounds            *ResourceBoundsMapping `protobuf:"bytes,6,opt,name=resource_bounds,json=resourceBounds,proto3" json:"resource_bounds,omitempty"`
+	Tip                       string                 `protobuf:"bytes,7,opt,name=tip,proto3" json:"tip,omitempty"`
+	PaymasterData             []string               `protobuf:"bytes,8,rep,name=paymaster_data,json=paymasterData,proto3" json:"paymaster_data,omitempty"`
+	AccountDeploymentData     []string               `protobuf:"bytes,9,rep,name=account_deployment_data,json=accountDeploymentData,proto3" json:"account_deployment_data,omitempty"`
+	NonceDataAvailabilityMode DataAvailabilityMode   `protobuf:"varint,10,opt,name=nonce_data_availability_mode,json=nonceDataAvailabilityMode,proto3,enum=starknet.staking.signer.v1.DataAvailabilityMode" json:"nonce_data_availability_mode,omitempty"`
+	FeeDataAvailabilityMode   DataAvailabilityMode   `protobuf:"varint,11,opt,name=fee_data_availability_mode,json=feeDataAvailabilityMode,proto3,enum=starknet.staking.signer.v1.DataAvailabilityMode" json:"fee_data_availability_mode,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *InvokeTransactionV3) Reset() {
+	*x = InvokeTransactionV3{}
+	mi := &file_signer_signerpb_signer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvokeTransactionV3) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvokeTransactionV3) ProtoMessage() {}
+
+func (x *InvokeTransactionV3) ProtoReflect() protoreflect.Message {
+	mi := &file_signer_signerpb_signer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvokeTransactionV3.ProtoReflect.Descriptor instead.
+func (*InvokeTransactionV3) Descriptor() ([]byte, []int) {
+	return file_signer_signerpb_signer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *InvokeTransactionV3) GetSenderAddress() string {
+	if x != nil {
+		return x.SenderAddress
+	}
+	return ""
+}
+
+func (x *InvokeTransactionV3) GetCalldata() []string {
+	if x != nil {
+		return x.Calldata
+	}
+	return nil
+}
+
+func (x *InvokeTransactionV3) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *InvokeTransactionV3) GetSignature() []string {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *InvokeTransactionV3) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *InvokeTransactionV3) GetResourceBounds() *ResourceBoundsMapping {
+	if x != nil {
+		return x.ResourceBounds
+	}
+	return nil
+}
+
+func (x *InvokeTransactionV3) GetTip() string {
+	if x != nil {
+		return x.Tip
+	}
+	return ""
+}
+
+func (x *InvokeTransactionV3) GetPaymasterData() []string {
+	if x != nil {
+		return x.PaymasterData
+	}
+	return nil
+}
+
+func (x *InvokeTransactionV3) GetAccountDeploymentData() []string {
+	if x != nil {
+		return x.AccountDeploymentData
+	}
+	return nil
+}
+
+func (x *InvokeTransactionV3) GetNonceDataAvailabilityMode() DataAvailabilityMode {
+	if x != nil {
+		return x.NonceDataAvailabilityMode
+	}
+	return DataAvailabilityMode_DATA_AVAILABILITY_MODE_UNSPECIFIED
+}
+
+func (x *InvokeTransactionV3) GetFeeDataAvailabilityMode() DataAvailabilityMode {
+	if x != nil {
+		return x.FeeDataAvailabilityMode
+	}
+	return DataAvailabilityMode_DATA_AVAILABILITY_MODE_UNSPECIFIED
+}
+
+type SignRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Version of the signing protocol. Not set by v1 clients
+	ProtocolVersion uint32               `protobuf:"varint,1,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	Transaction     *InvokeTransactionV3 `protobuf:"bytes,2,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	ChainId         string               `protobuf:"bytes,3,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	// Epoch the transaction attests for. Used to refuse conflicting attestations
+	EpochId *uint64 `protobuf:"varint,4,opt,name=epoch_id,json=epochId,proto3,oneof" json:"epoch_id,omitempty"`
+	// From v2, requests in hash mode give the hash to sign and the account signing it
+	// instead of the transaction
+	Mode          SignMode `protobuf:"varint,5,opt,name=mode,proto3,enum=starknet.staking.signer.v1.SignMode" json:"mode,omitempty"`
+	Hash          string   `protobuf:"bytes,6,opt,name=hash,proto3" json:"hash,omitempty"`
+	Account       string   `protobuf:"bytes,7,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignRequest) Reset() {
+	*x = SignRequest{}
+	mi := &file_signer_signerpb_signer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignRequest) ProtoMessage() {}
+
+func (x *SignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_signer_signerpb_signer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignRequest.ProtoReflect.Descriptor instead.
+func (*SignRequest) Descriptor() ([]byte, []int) {
+	return file_signer_signerpb_signer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SignRequest) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *SignRequest) GetTransaction() *InvokeTransactionV3 {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+func (x *SignRequest) GetChainId() string {
+	if x != nil {
+		return x.ChainId
+	}
+	return ""
+}
+
+func (x *SignRequest) GetEpochId() uint64 {
+	if x != nil && x.EpochId != nil {
+		return *x.EpochId
+	}
+	return 0
+}
+
+func (x *SignRequest) GetMode() SignMode {
+	if x != nil {
+		return x.Mode
+	}
+	return SignMode_SIGN_MODE_UNSPECIFIED
+}
+
+func (x *SignRequest) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *SignRequest) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+type SignResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Same as the request's. Not set in v1 responses
+	ProtocolVersion uint32 `protobuf:"varint,1,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	// Usually the ECDSA `r` and `s` values, longer for accounts combining several keys
+	Signature       []string `protobuf:"bytes,2,rep,name=signature,proto3" json:"signature,omitempty"`
+	TransactionHash string   `protobuf:"bytes,3,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SignResponse) Reset() {
+	*x = SignResponse{}
+	mi := &file_signer_signerpb_signer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignResponse) ProtoMessage() {}
+
+func (x *SignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_signer_signerpb_signer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignResponse.ProtoReflect.Descriptor instead.
+func (*SignResponse) Descriptor() ([]byte, []int) {
+	return file_signer_signerpb_signer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SignResponse) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *SignResponse) GetSignature() []string {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *SignResponse) GetTransactionHash() string {
+	if x != nil {
+		return x.TransactionHash
+	}
+	return ""
+}
+
+type GetPublicKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPublicKeysRequest) Reset() {
+	*x = GetPublicKeysRequest{}
+	mi := &file_signer_signerpb_signer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPublicKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPublicKeysRequest) ProtoMessage() {}
+
+func (x *GetPublicKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_signer_signerpb_signer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPublicKeysRequest.ProtoReflect.Descriptor instead.
+func (*GetPublicKeysRequest) Descriptor() ([]byte, []int) {
+	return file_signer_signerpb_signer_proto_rawDescGZIP(), []int{5}
+}
+
+type AccountPublicKeys struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Not set for a key signing for any account
+	Account         string   `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	PublicKeys      []string `protobuf:"bytes,2,rep,name=public_keys,json=publicKeys,proto3" json:"public_keys,omitempty"`
+	SignatureFormat string   `protobuf:"bytes,3,opt,name=signature_format,json=signatureFormat,proto3" json:"signature_format,omitempty"`
+	Enabled         bool     `protobuf:"varint,4,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AccountPublicKeys) Reset() {
+	*x = AccountPublicKeys{}
+	mi := &file_signer_signerpb_signer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountPublicKeys) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountPublicKeys) ProtoMessage() {}
+
+func (x *AccountPublicKeys) ProtoReflect() protoreflect.Message {
+	mi := &file_signer_signerpb_signer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountPublicKeys.ProtoReflect.Descriptor instead.
+func (*AccountPublicKeys) Descriptor() ([]byte, []int) {
+	return file_signer_signerpb_signer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AccountPublicKeys) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *AccountPublicKeys) GetPublicKeys() []string {
+	if x != nil {
+		return x.PublicKeys
+	}
+	return nil
+}
+
+func (x *AccountPublicKeys) GetSignatureFormat() string {
+	if x != nil {
+		return x.SignatureFormat
+	}
+	return ""
+}
+
+func (x *AccountPublicKeys) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+type GetPublicKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []*AccountPublicKeys   `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPublicKeysResponse) Reset() {
+	*x = GetPublicKeysResponse{}
+	mi := &file_signer_signerpb_signer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPublicKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPublicKeysResponse) ProtoMessage() {}
+
+func (x *GetPublicKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_signer_signerpb_signer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPublicKeysResponse.ProtoReflect.Descriptor instead.
+func (*GetPublicKeysResponse) Descriptor() ([]byte, []int) {
+	return file_signer_signerpb_signer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetPublicKeysResponse) GetKeys() []*AccountPublicKeys {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type GetCapabilitiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCapabilitiesRequest) Reset() {
+	*x = GetCapabilitiesRequest{}
+	mi := &file_signer_signerpb_signer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesRequest) ProtoMessage() {}
+
+func (x *GetCapabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_signer_signerpb_signer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_signer_signerpb_signer_proto_rawDescGZIP(), []int{8}
+}
+
+type GetCapabilitiesResponse struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	ProtocolVersions         []uint32               `protobuf:"varint,1,rep,packed,name=protocol_versions,json=protocolVersions,proto3" json:"protocol_versions,omitempty"`
+	VariableLengthSignatures bool                   `protobuf:"varint,2,opt,name=variable_length_signatures,json=variableLengthSignatures,proto3" json:"variable_length_signatures,omitempty"`
+	HashOnly                 bool                   `protobuf:"varint,3,opt,name=hash_only,json=hashOnly,proto3" json:"hash_only,omitempty"`
+	TransactionTypes         []string               `protobuf:"bytes,4,rep,name=transaction_types,json=transactionTypes,proto3" json:"transaction_types,omitempty"`
+	TransactionVersions      []string               `protobuf:"bytes,5,rep,name=transaction_versions,json=transactionVersions,proto3" json:"transaction_versions,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *GetCapabilitiesResponse) Reset() {
+	*x = GetCapabilitiesResponse{}
+	mi := &file_signer_signerpb_signer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesResponse) ProtoMessage() {}
+
+func (x *GetCapabilitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_signer_signerpb_signer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilitiesResponse.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesResponse) Descriptor() ([]byte, []int) {
+	return file_signer_signerpb_signer_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetCapabilitiesResponse) GetProtocolVersions() []uint32 {
+	if x != nil {
+		return x.ProtocolVersions
+	}
+	return nil
+}
+
+func (x *GetCapabilitiesResponse) GetVariableLengthSignatures() bool {
+	if x != nil {
+		return x.VariableLengthSignatures
+	}
+	return false
+}
+
+func (x *GetCapabilitiesResponse) GetHashOnly() bool {
+	if x != nil {
+		return x.HashOnly
+	}
+	return false
+}
+
+func (x *GetCapabilitiesResponse) GetTransactionTypes() []string {
+	if x != nil {
+		return x.TransactionTypes
+	}
+	return nil
+}
+
+func (x *GetCapabilitiesResponse) GetTransactionVersions() []string {
+	if x != nil {
+		return x.TransactionVersions
+	}
+	return nil
+}
+
+var File_signer_signerpb_signer_proto protoreflect.FileDescriptor
+
+const file_signer_signerpb_signer_proto_rawDesc = "" +
+	"\n" +
+	"\x1csigner/signerpb/signer.proto\x12\x1astarknet.staking.signer.v1\"\\\n" +
+	"\x0eResourceBounds\x12\x1d\n" +
+	"\n" +
+	"max_amount\x18\x01 \x01(\tR\tmaxAmount\x12+\n" +
+	"\x12max_price_per_unit\x18\x02 \x01(\tR\x0fmaxPricePerUnit\"\xe9\x01\n" +
+	"\x15ResourceBoundsMapping\x12A\n" +
+	"\x06l1_gas\x18\x01 \x01(\v2*.starknet.staking.signer.v1.ResourceBoundsR\x05l1Gas\x12J\n" +
+	"\vl1_data_gas\x18\x02 \x01(\v2*.starknet.staking.signer.v1.ResourceBoundsR\tl1DataGas\x12A\n" +
+	"\x06l2_gas\x18\x03 \x01(\v2*.starknet.staking.signer.v1.ResourceBoundsR\x05l2Gas\"\xd5\x04\n" +
+	"\x13InvokeTransactionV3\x12%\n" +
+	"\x0esender_address\x18\x01 \x01(\tR\rsenderAddress\x12\x1a\n" +
+	"\bcalldata\x18\x02 \x03(\tR\bcalldata\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12\x1c\n" +
+	"\tsignature\x18\x04 \x03(\tR\tsignature\x12\x14\n" +
+	"\x05nonce\x18\x05 \x01(\tR\x05nonce\x12Z\n" +
+	"\x0fresource_bounds\x18\x06 \x01(\v21.starknet.staking.signer.v1.ResourceBoundsMappingR\x0eresourceBounds\x12\x10\n" +
+	"\x03tip\x18\a \x01(\tR\x03tip\x12%\n" +
+	"\x0epaymaster_data\x18\b \x03(\tR\rpaymasterData\x126\n" +
+	"\x17account_deployment_data\x18\t \x03(\tR\x15accountDeploymentData\x12q\n" +
+	"\x1cnonce_data_availability_mode\x18\n" +
+	" \x01(\x0e20.starknet.staking.signer.v1.DataAvailabilityModeR\x19nonceDataAvailabilityMode\x12m\n" +
+	"\x1afee_data_availability_mode\x18\v \x01(\x0e20.starknet.staking.signer.v1.DataAvailabilityModeR\x17feeDataAvailabilityMode\"\xbb\x02\n" +
+	"\vSignRequest\x12)\n" +
+	"\x10protocol_version\x18\x01 \x01(\rR\x0fprotocolVersion\x12Q\n" +
+	"\vtransaction\x18\x02 \x01(\v2/.starknet.staking.signer.v1.InvokeTransactionV3R\vtransaction\x12\x19\n" +
+	"\bchain_id\x18\x03 \x01(\tR\achainId\x12\x1e\n" +
+	"\bepoch_id\x18\x04 \x01(\x04H\x00R\aepochId\x88\x01\x01\x128\n" +
+	"\x04mode\x18\x05 \x01(\x0e2$.starknet.staking.signer.v1.SignModeR\x04mode\x12\x12\n" +
+	"\x04hash\x18\x06 \x01(\tR\x04hash\x12\x18\n" +
+	"\aaccount\x18\a \x01(\tR\aaccountB\v\n" +
+	"\t_epoch_id\"\x82\x01\n" +
+	"\fSignResponse\x12)\n" +
+	"\x10protocol_version\x18\x01 \x01(\rR\x0fprotocolVersion\x12\x1c\n" +
+	"\tsignature\x18\x02 \x03(\tR\tsignature\x12)\n" +
+	"\x10transaction_hash\x18\x03 \x01(\tR\x0ftransactionHash\"\x16\n" +
+	"\x14GetPublicKeysRequest\"\x93\x01\n" +
+	"\x11AccountPublicKeys\x12\x18\n" +
+	"\aaccount\x18\x01 \x01(\tR\aaccount\x12\x1f\n" +
+	"\vpublic_keys\x18\x02 \x03(\tR\n" +
+	"publicKeys\x12)\n" +
+	"\x10signature_format\x18\x03 \x01(\tR\x0fsignatureFormat\x12\x18\n" +
+	"\aenabled\x18\x04 \x01(\bR\aenabled\"Z\n" +
+	"\x15GetPublicKeysResponse\x12A\n" +
+	"\x04keys\x18\x01 \x03(\v2-.starknet.staking.signer.v1.AccountPublicKeysR\x04keys\"\x18\n" +
+	"\x16GetCapabilitiesRequest\"\x81\x02\n" +
+	"\x17GetCapabilitiesResponse\x12+\n" +
+	"\x11protocol_versions\x18\x01 \x03(\rR\x10protocolVersions\x12<\n" +
+	"\x1avariable_length_signatures\x18\x02 \x01(\bR\x18variableLengthSignatures\x12\x1b\n" +
+	"\thash_only\x18\x03 \x01(\bR\bhashOnly\x12+\n" +
+	"\x11transaction_types\x18\x04 \x03(\tR\x10transactionTypes\x121\n" +
+	"\x14transaction_versions\x18\x05 \x03(\tR\x13transactionVersions*|\n" +
+	"\x14DataAvailabilityMode\x12&\n" +
+	"\"DATA_AVAILABILITY_MODE_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19DATA_AVAILABILITY_MODE_L1\x10\x01\x12\x1d\n" +
+	"\x19DATA_AVAILABILITY_MODE_L2\x10\x02*T\n" +
+	"\bSignMode\x12\x19\n" +
+	"\x15SIGN_MODE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15SIGN_MODE_TRANSACTION\x10\x01\x12\x12\n" +
+	"\x0eSIGN_MODE_HASH\x10\x022\xd5\x02\n" +
+	"\x06Signer\x12Y\n" +
+	"\x04Sign\x12'.starknet.staking.signer.v1.SignRequest\x1a(.starknet.staking.signer.v1.SignResponse\x12t\n" +
+	"\rGetPublicKeys\x120.starknet.staking.signer.v1.GetPublicKeysRequest\x1a1.starknet.staking.signer.v1.GetPublicKeysResponse\x12z\n" +
+	"\x0fGetCapabilities\x122.starknet.staking.signer.v1.GetCapabilitiesRequest\x1a3.starknet.staking.signer.v1.GetCapabilitiesResponseB>Z<github.com/NethermindEth/starknet-staking-v2/signer/signerpbb\x06proto3"
+
+var (
+	file_signer_signerpb_signer_proto_rawDescOnce sync.Once
+	file_signer_signerpb_signer_proto_rawDescData []byte
+)
+
+func file_signer_signerpb_signer_proto_rawDescGZIP() []byte {
+	file_signer_signerpb_signer_proto_rawDescOnce.Do(func() {
+		file_signer_signerpb_signer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_signer_signerpb_signer_proto_rawDesc), len(file_signer_signerpb_signer_proto_rawDesc)))
+	})
+	return file_signer_signerpb_signer_proto_rawDescData
+}
+
+var file_signer_signerpb_signer_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_signer_signerpb_signer_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_signer_signerpb_signer_proto_goTypes = []any{
+	(DataAvailabilityMode)(0),       // 0: starknet.staking.signer.v1.DataAvailabilityMode
+	(SignMode)(0),                   // 1: starknet.staking.signer.v1.SignMode
+	(*ResourceBounds)(nil),          // 2: starknet.staking.signer.v1.ResourceBounds
+	(*ResourceBoundsMapping)(nil),   // 3: starknet.staking.signer.v1.ResourceBoundsMapping
+	(*InvokeTransactionV3)(nil),     // 4: starknet.staking.signer.v1.InvokeTransactionV3
+	(*SignRequest)(nil),             // 5: starknet.staking.signer.v1.SignRequest
+	(*SignResponse)(nil),            // 6: starknet.staking.signer.v1.SignResponse
+	(*GetPublicKeysRequest)(nil),    // 7: starknet.staking.signer.v1.GetPublicKeysRequest
+	(*AccountPublicKeys)(nil),       // 8: starknet.staking.signer.v1.AccountPublicKeys
+	(*GetPublicKeysResponse)(nil),   // 9: starknet.staking.signer.v1.GetPublicKeysResponse
+	(*GetCapabilitiesRequest)(nil),  // 10: starknet.staking.signer.v1.GetCapabilitiesRequest
+	(*GetCapabilitiesResponse)(nil), // 11: starknet.staking.signer.v1.GetCapabilitiesResponse
+}
+var file_signer_signerpb_signer_proto_depIdxs = []int32{
+	2,  // 0: starknet.staking.signer.v1.ResourceBoundsMapping.l1_gas:type_name -> starknet.staking.signer.v1.ResourceBounds
+	2,  // 1: starknet.staking.signer.v1.ResourceBoundsMapping.l1_data_gas:type_name -> starknet.staking.signer.v1.ResourceBounds
+	2,  // 2: starknet.staking.signer.v1.ResourceBoundsMapping.l2_gas:type_name -> starknet.staking.signer.v1.ResourceBounds
+	3,  // 3: starknet.staking.signer.v1.InvokeTransactionV3.resource_bounds:type_name -> starknet.staking.signer.v1.ResourceBoundsMapping
+	0,  // 4: starknet.staking.signer.v1.InvokeTransactionV3.nonce_data_availability_mode:type_name -> starknet.staking.signer.v1.DataAvailabilityMode
+	0,  // 5: starknet.staking.signer.v1.InvokeTransactionV3.fee_data_availability_mode:type_name -> starknet.staking.signer.v1.DataAvailabilityMode
+	4,  // 6: starknet.staking.signer.v1.SignRequest.transaction:type_name -> starknet.staking.signer.v1.InvokeTransactionV3
+	1,  // 7: starknet.staking.signer.v1.SignRequest.mode:type_name -> starknet.staking.signer.v1.SignMode
+	8,  // 8: starknet.staking.signer.v1.GetPublicKeysResponse.keys:type_name -> starknet.staking.signer.v1.AccountPublicKeys
+	5,  // 9: starknet.staking.signer.v1.Signer.Sign:input_type -> starknet.staking.signer.v1.SignRequest
+	7,  // 10: starknet.staking.signer.v1.Signer.GetPublicKeys:input_type -> starknet.staking.signer.v1.GetPublicKeysRequest
+	10, // 11: starknet.staking.signer.v1.Signer.GetCapabilities:input_type -> starknet.staking.signer.v1.GetCapabilitiesRequest
+	6,  // 12: starknet.staking.signer.v1.Signer.Sign:output_type -> starknet.staking.signer.v1.SignResponse
+	9,  // 13: starknet.staking.signer.v1.Signer.GetPublicKeys:output_type -> starknet.staking.signer.v1.GetPublicKeysResponse
+	11, // 14: starknet.staking.signer.v1.Signer.GetCapabilities:output_type -> starknet.staking.signer.v1.GetCapabilitiesResponse
+	12, // [12:15] is the sub-list for method output_type
+	9,  // [9:12] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_signer_signerpb_signer_proto_init() }
+func file_signer_signerpb_signer_proto_init() {
+	if File_signer_signerpb_signer_proto != nil {
+		return
+	}
+	file_signer_signerpb_signer_proto_msgTypes[3].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_signer_signerpb_signer_proto_rawDesc), len(file_signer_signerpb_signer_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_signer_signerpb_signer_proto_goTypes,
+		DependencyIndexes: file_signer_signerpb_signer_proto_depIdxs,
+		EnumInfos:         file_signer_signerpb_signer_proto_enumTypes,
+		MessageInfos:      file_signer_signerpb_signer_proto_msgTypes,
+	}.Build()
+	File_signer_signerpb_signer_proto = out.File
+	file_signer_signerpb_signer_proto_goTypes = nil
+	file_signer_signerpb_signer_proto_depIdxs = nil
+}
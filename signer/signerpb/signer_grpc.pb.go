@@ -0,0 +1,215 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: signer/signerpb/signer.proto
+
+// gRPC interface of the remote signer. It has the same semantics as the HTTP endpoints:
+// `Sign` is `POST /sign`, `GetPublicKeys` is `GET /public-key` and `GetCapabilities` is
+// `GET /capabilities`. Health is served by the standard `grpc.health.v1.Health` service.
+//
+// Felts and other Starknet numbers are hex encoded strings, as in the Starknet JSON-RPC.
+//
+// Regenerate the Go code with `make signer-proto`.
+
+package signerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Signer_Sign_FullMethodName            = "/starknet.staking.signer.v1.Signer/Sign"
+	Signer_GetPublicKeys_FullMethodName   = "/starknet.staking.signer.v1.Signer/GetPublicKeys"
+	Signer_GetCapabilities_FullMethodName = "/starknet.staking.signer.v1.Signer/GetCapabilities"
+)
+
+// SignerClient is the client API for Signer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SignerClient interface {
+	// Hashes and signs a transaction, or only signs a hash in hash mode. Requests refused by
+	// the signing policy fail with `PERMISSION_DENIED` and a `google.rpc.ErrorInfo` detail
+	// whose reason is the refusal code.
+	Sign(ctx context.Context, in *SignRequest, opts ...grpc.CallOption) (*SignResponse, error)
+	// Lists the public keys of the signer and the accounts they sign for
+	GetPublicKeys(ctx context.Context, in *GetPublicKeysRequest, opts ...grpc.CallOption) (*GetPublicKeysResponse, error)
+	// Lists the protocol versions and features supported by the signer
+	GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error)
+}
+
+type signerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSignerClient(cc grpc.ClientConnInterface) SignerClient {
+	return &signerClient{cc}
+}
+
+func (c *signerClient) Sign(ctx context.Context, in *SignRequest, opts ...grpc.CallOption) (*SignResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignResponse)
+	err := c.cc.Invoke(ctx, Signer_Sign_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signerClient) GetPublicKeys(ctx context.Context, in *GetPublicKeysRequest, opts ...grpc.CallOption) (*GetPublicKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPublicKeysResponse)
+	err := c.cc.Invoke(ctx, Signer_GetPublicKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signerClient) GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCapabilitiesResponse)
+	err := c.cc.Invoke(ctx, Signer_GetCapabilities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SignerServer is the server API for Signer service.
+// All implementations must embed UnimplementedSignerServer
+// for forward compatibility.
+type SignerServer interface {
+	// Hashes and signs a transaction, or only signs a hash in hash mode. Requests refused by
+	// the signing policy fail with `PERMISSION_DENIED` and a `google.rpc.ErrorInfo` detail
+	// whose reason is the refusal code.
+	Sign(context.Context, *SignRequest) (*SignResponse, error)
+	// Lists the public keys of the signer and the accounts they sign for
+	GetPublicKeys(context.Context, *GetPublicKeysRequest) (*GetPublicKeysResponse, error)
+	// Lists the protocol versions and features supported by the signer
+	GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error)
+	mustEmbedUnimplementedSignerServer()
+}
+
+// UnimplementedSignerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSignerServer struct{}
+
+func (UnimplementedSignerServer) Sign(context.Context, *SignRequest) (*SignResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sign not implemented")
+}
+func (UnimplementedSignerServer) GetPublicKeys(context.Context, *GetPublicKeysRequest) (*GetPublicKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublicKeys not implemented")
+}
+func (UnimplementedSignerServer) GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapabilities not implemented")
+}
+func (UnimplementedSignerServer) mustEmbedUnimplementedSignerServer() {}
+func (UnimplementedSignerServer) testEmbeddedByValue()                {}
+
+// UnsafeSignerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SignerServer will
+// result in compilation errors.
+type UnsafeSignerServer interface {
+	mustEmbedUnimplementedSignerServer()
+}
+
+func RegisterSignerServer(s grpc.ServiceRegistrar, srv SignerServer) {
+	// If the following call pancis, it indicates UnimplementedSignerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Signer_ServiceDesc, srv)
+}
+
+func _Signer_Sign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).Sign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signer_Sign_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).Sign(ctx, req.(*SignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signer_GetPublicKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPublicKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).GetPublicKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signer_GetPublicKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).GetPublicKeys(ctx, req.(*GetPublicKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signer_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signer_GetCapabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).GetCapabilities(ctx, req.(*GetCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Signer_ServiceDesc is the grpc.ServiceDesc for Signer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Signer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "starknet.staking.signer.v1.Signer",
+	HandlerType: (*SignerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Sign",
+			Handler:    _Signer_Sign_Handler,
+		},
+		{
+			MethodName: "GetPublicKeys",
+			Handler:    _Signer_GetPublicKeys_Handler,
+		},
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _Signer_GetCapabilities_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "signer/signerpb/signer.proto",
+}
@@ -0,0 +1,105 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Prefix of the addresses of signers served over a Unix domain socket
+const UnixSocketScheme = "unix://"
+
+// Only the owner and group of the socket can connect to the signer
+const SocketFileMode os.FileMode = 0o660
+
+// Returns the path of the socket if the address is a `unix://` one
+func SocketPath(address string) (string, bool) {
+	path, ok := strings.CutPrefix(address, UnixSocketScheme)
+
+	return path, ok
+}
+
+// Checks no other user can replace the socket or connect to it: neither the socket nor
+// its directory are writable by any user. A world writable directory is accepted when
+// its sticky bit is set, since only the owner of the socket can then remove it.
+func CheckSocketPermissions(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Type() != os.ModeSocket {
+		return fmt.Errorf("%s is not a socket", path)
+	}
+	if info.Mode().Perm()&0o002 != 0 {
+		return fmt.Errorf("socket %s is writable by any user", path)
+	}
+
+	return checkSocketDir(filepath.Dir(path))
+}
+
+func checkSocketDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	if info.Mode().Perm()&0o002 != 0 && info.Mode()&os.ModeSticky == 0 {
+		return fmt.Errorf("socket directory %s is writable by any user", dir)
+	}
+
+	return nil
+}
+
+// Listens on a Unix domain socket at the path, only accessible to its owner and group.
+// A socket left behind by a signer that is no longer running is replaced.
+func listenUnix(path string) (net.Listener, error) {
+	if err := checkSocketDir(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Lstat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	case info.Mode().Type() != os.ModeSocket:
+		return nil, fmt.Errorf("%s already exists and is not a socket", path)
+	default:
+		conn, err := net.DialTimeout("unix", path, time.Second)
+		if err == nil {
+			_ = conn.Close()
+
+			return nil, fmt.Errorf("socket %s is already in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("cannot remove stale socket: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, SocketFileMode); err != nil {
+		_ = listener.Close()
+
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+// Listens on the address, either a `unix://` socket path or a TCP address
+func listen(address string) (net.Listener, error) {
+	if path, ok := SocketPath(address); ok {
+		return listenUnix(path)
+	}
+
+	return net.Listen("tcp", address)
+}
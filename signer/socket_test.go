@@ -0,0 +1,104 @@
+package signer_test
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnixSocket(t *testing.T) {
+	remoteSigner := signer.NewWithKey(big.NewInt(0x123), utils.NewNopZapLogger())
+
+	serve := func(t *testing.T, socketPath string) <-chan error {
+		t.Helper()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		t.Cleanup(cancel)
+		served := make(chan error, 1)
+		go func() { served <- remoteSigner.Serve(ctx, signer.UnixSocketScheme+socketPath) }()
+
+		return served
+	}
+
+	healthy := func(socketPath string) bool {
+		//nolint:exhaustruct // Only specifying used fields
+		client := &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return new(net.Dialer).DialContext(ctx, "unix", socketPath)
+			},
+		}}
+		resp, err := client.Get("http://signer" + signer.HealthEndpoint) //nolint:noctx // Test
+		if err != nil {
+			return false
+		}
+		_ = resp.Body.Close()
+
+		return resp.StatusCode == http.StatusOK
+	}
+
+	t.Run("Serve over a socket", func(t *testing.T) {
+		socketPath := filepath.Join(t.TempDir(), "signer.sock")
+		serve(t, socketPath)
+
+		require.Eventually(t, func() bool { return healthy(socketPath) }, 5*time.Second, 10*time.Millisecond)
+
+		info, err := os.Stat(socketPath)
+		require.NoError(t, err)
+		require.Equal(t, signer.SocketFileMode, info.Mode().Perm())
+		require.NoError(t, signer.CheckSocketPermissions(socketPath))
+	})
+
+	t.Run("Stale socket is replaced", func(t *testing.T) {
+		socketPath := filepath.Join(t.TempDir(), "signer.sock")
+		listener, err := net.Listen("unix", socketPath)
+		require.NoError(t, err)
+		listener.(*net.UnixListener).SetUnlinkOnClose(false)
+		require.NoError(t, listener.Close())
+
+		serve(t, socketPath)
+		require.Eventually(t, func() bool { return healthy(socketPath) }, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Socket in use", func(t *testing.T) {
+		socketPath := filepath.Join(t.TempDir(), "signer.sock")
+		listener, err := net.Listen("unix", socketPath)
+		require.NoError(t, err)
+		defer listener.Close()
+
+		require.ErrorContains(t, <-serve(t, socketPath), "is already in use")
+	})
+
+	t.Run("Path is not a socket", func(t *testing.T) {
+		socketPath := filepath.Join(t.TempDir(), "signer.sock")
+		require.NoError(t, os.WriteFile(socketPath, []byte{}, 0o600))
+
+		require.ErrorContains(t, <-serve(t, socketPath), "is not a socket")
+	})
+
+	t.Run("Directory writable by any user", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.Chmod(dir, 0o777))
+
+		err := <-serve(t, filepath.Join(dir, "signer.sock"))
+		require.ErrorContains(t, err, "is writable by any user")
+	})
+
+	t.Run("Socket writable by any user", func(t *testing.T) {
+		socketPath := filepath.Join(t.TempDir(), "signer.sock")
+		serve(t, socketPath)
+		require.Eventually(t, func() bool { return healthy(socketPath) }, 5*time.Second, 10*time.Millisecond)
+
+		require.NoError(t, os.Chmod(socketPath, 0o666))
+		err := signer.CheckSocketPermissions(socketPath)
+		require.ErrorContains(t, err, "is writable by any user")
+	})
+}
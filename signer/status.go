@@ -0,0 +1,156 @@
+package signer
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+const (
+	PublicKeyEndpoint = "/public-key"
+	HealthEndpoint    = "/health"
+	ReadyEndpoint     = "/ready"
+	VersionEndpoint   = "/version"
+)
+
+// Set at build time
+var Version = "dev"
+
+// Public keys signing for an account
+type AccountPublicKeys struct {
+	// Not set for a key signing for any account
+	Account         *felt.Felt      `json:"account,omitempty"`
+	PublicKeys      []*felt.Felt    `json:"public_keys"`
+	SignatureFormat SignatureFormat `json:"signature_format,omitempty"`
+	Enabled         bool            `json:"enabled"`
+}
+
+type PublicKeysResponse struct {
+	Keys []AccountPublicKeys `json:"keys"`
+}
+
+// Returns the keys signing for the account, nil if the signer has none
+func (r *PublicKeysResponse) For(account *felt.Felt) *AccountPublicKeys {
+	var fallback *AccountPublicKeys
+	for i := range r.Keys {
+		switch {
+		case r.Keys[i].Account == nil:
+			fallback = &r.Keys[i]
+		case r.Keys[i].Account.Equal(account):
+			return &r.Keys[i]
+		}
+	}
+
+	return fallback
+}
+
+type StatusResponse struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type VersionResponse struct {
+	Version string `json:"version"`
+}
+
+func (r *keyRing) publicKeys() []AccountPublicKeys {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	describe := func(account *felt.Felt, keys *accountKeys) AccountPublicKeys {
+		publicKeys := make([]*felt.Felt, len(keys.keys))
+		for i, key := range keys.keys {
+			publicKeys[i] = new(felt.Felt).SetBigInt(key.PublicKey())
+		}
+
+		return AccountPublicKeys{
+			Account:         account,
+			PublicKeys:      publicKeys,
+			SignatureFormat: keys.format,
+			Enabled:         keys.enabled,
+		}
+	}
+
+	described := make([]AccountPublicKeys, 0, len(r.byAccount)+1)
+	for account, keys := range r.byAccount {
+		described = append(described, describe(&account, keys))
+	}
+	slices.SortFunc(described, func(a, b AccountPublicKeys) int {
+		return a.Account.Cmp(b.Account)
+	})
+	if r.fallback != nil {
+		described = append(described, describe(nil, r.fallback))
+	}
+
+	return described
+}
+
+func (r *keyRing) anyEnabled() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.fallback != nil && r.fallback.enabled {
+		return true
+	}
+	for _, keys := range r.byAccount {
+		if keys.enabled {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Answers with the public keys of the signer and the accounts they sign for
+func (s *Signer) publicKeysHandler(w http.ResponseWriter, _ *http.Request) {
+	s.writeJSON(w, http.StatusOK, PublicKeysResponse{Keys: s.keys.publicKeys()})
+}
+
+// Answers as long as the signer is running
+func (s *Signer) healthHandler(w http.ResponseWriter, _ *http.Request) {
+	//nolint:exhaustruct // No reason when healthy
+	s.writeJSON(w, http.StatusOK, StatusResponse{Status: "ok"})
+}
+
+// Returns why the signer can't sign requests, empty if it can: it is not shutting down
+// and has at least one enabled key
+func (s *Signer) notReadyReason() string {
+	switch {
+	case s.shuttingDown.Load():
+		return "shutting down"
+	case !s.keys.anyEnabled():
+		return "all keys are disabled"
+	default:
+		return ""
+	}
+}
+
+// Answers whether the signer can sign requests
+func (s *Signer) readyHandler(w http.ResponseWriter, _ *http.Request) {
+	if reason := s.notReadyReason(); reason != "" {
+		s.writeJSON(
+			w,
+			http.StatusServiceUnavailable,
+			StatusResponse{Status: "not ready", Reason: reason},
+		)
+
+		return
+	}
+
+	//nolint:exhaustruct // No reason when ready
+	s.writeJSON(w, http.StatusOK, StatusResponse{Status: "ready"})
+}
+
+func (s *Signer) versionHandler(w http.ResponseWriter, _ *http.Request) {
+	s.writeJSON(w, http.StatusOK, VersionResponse{Version: Version})
+}
+
+func (s *Signer) writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		s.logger.Errorf("encoding response %v: %s", value, err)
+	}
+}
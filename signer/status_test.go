@@ -0,0 +1,138 @@
+package signer_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/secret"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusEndpoints(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+	accountA := utils.HexToFelt(t, "0xa")
+	accountB := utils.HexToFelt(t, "0xb")
+
+	remoteSigner, err := signer.NewWithAccountKeys([]signer.AccountKey{
+		{Account: *accountB, PrivateKey: big.NewInt(0x456), Enabled: false},
+		{Account: *accountA, PrivateKey: big.NewInt(0x123), Enabled: true},
+	}, logger)
+	require.NoError(t, err)
+	authConfig := signer.AuthConfig{Clients: []signer.ClientCredential{
+		{ID: "validator", Token: secret.New("some token")},
+	}}
+	remoteSigner.SetAuthenticator(signer.NewAuthenticator(&authConfig, time.Minute, logger))
+
+	get := func(t *testing.T, endpoint string, token string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, endpoint, nil)
+		if token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+		remoteSigner.Handler().ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	publicKeyOf := func(privateKey int64) *felt.Felt {
+		publicKey, _ := curve.PrivateKeyToPoint(big.NewInt(privateKey))
+
+		return new(felt.Felt).SetBigInt(publicKey)
+	}
+
+	t.Run("Public keys of every account", func(t *testing.T) {
+		recorder := get(t, signer.PublicKeyEndpoint, "some token")
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var resp signer.PublicKeysResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		require.Equal(t, []signer.AccountPublicKeys{
+			{Account: accountA, PublicKeys: []*felt.Felt{publicKeyOf(0x123)}, Enabled: true},
+			{Account: accountB, PublicKeys: []*felt.Felt{publicKeyOf(0x456)}, Enabled: false},
+		}, resp.Keys)
+		require.Equal(t, accountB, resp.For(accountB).Account)
+		require.Nil(t, resp.For(utils.HexToFelt(t, "0xc")))
+	})
+
+	t.Run("Public keys require authentication", func(t *testing.T) {
+		recorder := get(t, signer.PublicKeyEndpoint, "")
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+
+	t.Run("Health, readiness and version don't require authentication", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, get(t, signer.HealthEndpoint, "").Code)
+		require.Equal(t, http.StatusOK, get(t, signer.ReadyEndpoint, "").Code)
+
+		recorder := get(t, signer.VersionEndpoint, "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var resp signer.VersionResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		require.Equal(t, signer.Version, resp.Version)
+	})
+
+	t.Run("Not ready when all keys are disabled", func(t *testing.T) {
+		require.NoError(t, remoteSigner.SetKeyEnabled(accountA, false))
+		defer func() { require.NoError(t, remoteSigner.SetKeyEnabled(accountA, true)) }()
+
+		recorder := get(t, signer.ReadyEndpoint, "")
+		require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		require.Contains(t, recorder.Body.String(), "all keys are disabled")
+	})
+
+	t.Run("Key signing for any account", func(t *testing.T) {
+		fallbackSigner := signer.NewWithKey(big.NewInt(0x123), logger)
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, signer.PublicKeyEndpoint, nil)
+		fallbackSigner.Handler().ServeHTTP(recorder, request)
+
+		var resp signer.PublicKeysResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+		require.Len(t, resp.Keys, 1)
+		require.Nil(t, resp.Keys[0].Account)
+		require.Equal(t, publicKeyOf(0x123), resp.For(accountA).PublicKeys[0])
+	})
+}
+
+func TestGracefulShutdown(t *testing.T) {
+	remoteSigner := signer.NewWithKey(big.NewInt(0x123), utils.NewNopZapLogger())
+
+	// Reserve a free port for the signer
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	served := make(chan error, 1)
+	go func() { served <- remoteSigner.Serve(ctx, address) }()
+
+	require.Eventually(t, func() bool {
+		resp, err := http.Get("http://" + address + signer.HealthEndpoint) //nolint:noctx // Test
+		if err != nil {
+			return false
+		}
+		_ = resp.Body.Close()
+
+		return resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-served:
+		require.NoError(t, err)
+	case <-time.After(signer.ShutdownTimeout):
+		t.Fatal("signer didn't shut down")
+	}
+}
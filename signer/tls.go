@@ -0,0 +1,107 @@
+package signer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLS settings of the signer server. Setting a client CA enables mutual TLS, where only
+// clients presenting a certificate issued by that CA are accepted
+type TLSConfig struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+}
+
+func (c *TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+func (c *TLSConfig) ServerConfig() (*tls.Config, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, errors.New("both tls certificate and key are required")
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load tls certificate: %w", err)
+	}
+
+	//nolint:exhaustruct // Only specifying used fields
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if c.ClientCAFile != "" {
+		clientCAs, err := loadCertPool(c.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load tls client CA: %w", err)
+		}
+		config.ClientCAs = clientCAs
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return config, nil
+}
+
+// TLS settings of a client connecting to the signer
+type ClientTLSConfig struct {
+	// CA bundle used to verify the signer certificate instead of the system roots
+	CAFile string
+	// Client certificate and key presented to the signer for mutual TLS
+	CertFile string
+	KeyFile  string
+	// Name the signer certificate has to be valid for, instead of the URL host
+	ServerName string
+}
+
+func (c *ClientTLSConfig) Enabled() bool {
+	return c.CAFile != "" || c.CertFile != "" || c.KeyFile != "" || c.ServerName != ""
+}
+
+func (c *ClientTLSConfig) Config() (*tls.Config, error) {
+	//nolint:exhaustruct // Only specifying used fields
+	config := &tls.Config{
+		ServerName: c.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if c.CAFile != "" {
+		rootCAs, err := loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load tls CA: %w", err)
+		}
+		config.RootCAs = rootCAs
+	}
+
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, errors.New("both tls client certificate and key are required")
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load tls client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,215 @@
+package signer_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/stretchr/testify/require"
+)
+
+type tlsFiles struct {
+	caCert     string
+	serverCert string
+	serverKey  string
+	clientCert string
+	clientKey  string
+}
+
+// Generates a CA together with a server and a client certificate issued by it
+func generateTLSFiles(t *testing.T) tlsFiles {
+	t.Helper()
+
+	dir := t.TempDir()
+	writePEM := func(name, blockType string, data []byte) string {
+		path := filepath.Join(dir, name)
+		//nolint:exhaustruct // Only specifying used fields
+		encoded := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data})
+		require.NoError(t, os.WriteFile(path, encoded, 0o600))
+
+		return path
+	}
+
+	newKey := func() *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		return key
+	}
+
+	caKey := newKey()
+	//nolint:exhaustruct // Only specifying used fields
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	issue := func(serial int64, name string, usage x509.ExtKeyUsage) (string, string) {
+		key := newKey()
+		//nolint:exhaustruct // Only specifying used fields
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     []string{"localhost"},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+
+		return writePEM(name+".crt", "CERTIFICATE", der),
+			writePEM(name+".key", "EC PRIVATE KEY", keyDER)
+	}
+
+	serverCert, serverKey := issue(2, "server", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := issue(3, "client", x509.ExtKeyUsageClientAuth)
+
+	return tlsFiles{
+		caCert:     writePEM("ca.crt", "CERTIFICATE", caDER),
+		serverCert: serverCert,
+		serverKey:  serverKey,
+		clientCert: clientCert,
+		clientKey:  clientKey,
+	}
+}
+
+func TestTLS(t *testing.T) {
+	files := generateTLSFiles(t)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	startServer := func(t *testing.T, serverTLS *signer.TLSConfig) *httptest.Server {
+		t.Helper()
+
+		require.True(t, serverTLS.Enabled())
+		tlsConfig, err := serverTLS.ServerConfig()
+		require.NoError(t, err)
+
+		server := httptest.NewUnstartedServer(handler)
+		server.TLS = tlsConfig
+		server.StartTLS()
+		t.Cleanup(server.Close)
+
+		return server
+	}
+
+	get := func(t *testing.T, url string, clientTLS *signer.ClientTLSConfig) error {
+		t.Helper()
+
+		tlsConfig, err := clientTLS.Config()
+		require.NoError(t, err)
+		//nolint:exhaustruct // Only specifying used fields
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+		resp, err := client.Get(url)
+		if err != nil {
+			return err
+		}
+
+		return resp.Body.Close()
+	}
+
+	t.Run("Server without client certificates", func(t *testing.T) {
+		//nolint:exhaustruct // No client certificate verification
+		server := startServer(t, &signer.TLSConfig{
+			CertFile: files.serverCert, KeyFile: files.serverKey,
+		})
+
+		//nolint:exhaustruct // Only specifying used fields
+		require.NoError(t, get(t, server.URL, &signer.ClientTLSConfig{CAFile: files.caCert}))
+
+		//nolint:exhaustruct // The server certificate is verified with the system roots
+		err := get(t, server.URL, &signer.ClientTLSConfig{})
+		require.ErrorContains(t, err, "certificate signed by unknown authority")
+	})
+
+	t.Run("Server requiring client certificates", func(t *testing.T) {
+		server := startServer(t, &signer.TLSConfig{
+			CertFile:     files.serverCert,
+			KeyFile:      files.serverKey,
+			ClientCAFile: files.caCert,
+		})
+
+		//nolint:exhaustruct // Only specifying used fields
+		require.Error(t, get(t, server.URL, &signer.ClientTLSConfig{CAFile: files.caCert}))
+
+		//nolint:exhaustruct // Only specifying used fields
+		require.NoError(t, get(t, server.URL, &signer.ClientTLSConfig{
+			CAFile:   files.caCert,
+			CertFile: files.clientCert,
+			KeyFile:  files.clientKey,
+		}))
+	})
+
+	t.Run("Missing CA file", func(t *testing.T) {
+		missing := filepath.Join(t.TempDir(), "missing.crt")
+
+		_, err := (&signer.TLSConfig{
+			CertFile:     files.serverCert,
+			KeyFile:      files.serverKey,
+			ClientCAFile: missing,
+		}).ServerConfig()
+		require.ErrorContains(t, err, "cannot load tls client CA")
+
+		//nolint:exhaustruct // Only specifying used fields
+		_, err = (&signer.ClientTLSConfig{CAFile: missing}).Config()
+		require.ErrorContains(t, err, "cannot load tls CA")
+	})
+
+	t.Run("CA file without certificates", func(t *testing.T) {
+		_, err := (&signer.TLSConfig{
+			CertFile:     files.serverCert,
+			KeyFile:      files.serverKey,
+			ClientCAFile: files.serverKey,
+		}).ServerConfig()
+		require.ErrorContains(t, err, "no PEM certificates found")
+	})
+
+	t.Run("Wrong key pair", func(t *testing.T) {
+		//nolint:exhaustruct // No client certificate verification
+		_, err := (&signer.TLSConfig{
+			CertFile: files.serverCert, KeyFile: files.clientKey,
+		}).ServerConfig()
+		require.ErrorContains(t, err, "cannot load tls certificate")
+
+		//nolint:exhaustruct // Only specifying used fields
+		_, err = (&signer.ClientTLSConfig{
+			CertFile: files.clientCert, KeyFile: files.serverKey,
+		}).Config()
+		require.ErrorContains(t, err, "cannot load tls client certificate")
+	})
+
+	t.Run("Certificate without its key", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		_, err := (&signer.TLSConfig{CertFile: files.serverCert}).ServerConfig()
+		require.ErrorContains(t, err, "both tls certificate and key are required")
+
+		//nolint:exhaustruct // Only specifying used fields
+		_, err = (&signer.ClientTLSConfig{CertFile: files.clientCert}).Config()
+		require.ErrorContains(t, err, "both tls client certificate and key are required")
+	})
+}
@@ -0,0 +1,221 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	signerP "github.com/NethermindEth/starknet-staking-v2/validator/signer"
+	"github.com/NethermindEth/starknet.go/rpc"
+	snUtils "github.com/NethermindEth/starknet.go/utils"
+)
+
+var ErrAccountDeployed = errors.New("operational account is already deployed")
+
+const (
+	// Time waited between checks of the status of a transaction sent by a command
+	transactionStatusInterval = 5 * time.Second
+	// Time a transaction sent by a command has to be accepted, unless set otherwise
+	DefaultAcceptanceTimeout = 10 * time.Minute
+)
+
+// Deploys the operational account, see `DeployAccount`
+func (v *Validator) DeployAccount(
+	ctx context.Context,
+	accountClass *signer.AccountClass,
+	salt *felt.Felt,
+	acceptanceTimeout time.Duration,
+) (*felt.Felt, error) {
+	deployer, ok := v.signer.(signerP.AccountDeployer)
+	if !ok {
+		return nil, errors.New("the signer cannot deploy the operational account")
+	}
+
+	return DeployAccount(ctx, deployer, accountClass, salt, acceptanceTimeout, &v.logger)
+}
+
+// Deploys the operational account from the account class, owned by the signer public key
+// and salted with the salt, or the public key if nil. The address they give has to be the
+// operational address, which must hold enough STRK to pay for the deployment. Returns the
+// hash of the deploy account transaction once it is accepted, or `ErrAccountDeployed` if
+// the account is already deployed. Fails if it isn't accepted within the acceptance
+// timeout.
+func DeployAccount[D signerP.AccountDeployer](
+	ctx context.Context,
+	deployer D,
+	accountClass *signer.AccountClass,
+	salt *felt.Felt,
+	acceptanceTimeout time.Duration,
+	logger *utils.ZapLogger,
+) (*felt.Felt, error) {
+	deployed, err := deployer.AccountDeployed()
+	if err != nil {
+		return nil, fmt.Errorf("cannot check if the operational account is deployed: %w", err)
+	}
+	if deployed {
+		return nil, ErrAccountDeployed
+	}
+
+	if accountClass.Type == signer.AccountTypeBraavos {
+		return nil, errors.New(
+			"deploying braavos accounts is not supported, they require an additional" +
+				" signature of the deployment",
+		)
+	}
+
+	publicKey, err := deployer.AccountPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	if salt == nil {
+		salt = publicKey
+	}
+	address := accountClass.Address(publicKey, salt)
+	if !address.Equal(deployer.Address().Felt()) {
+		return nil, fmt.Errorf(
+			"%s account of public key %s and salt %s is deployed at %s, not at the operational"+
+				" address %s",
+			accountClass.Type,
+			publicKey,
+			salt,
+			address,
+			deployer.Address(),
+		)
+	}
+
+	txn, err := buildDeployAccount(deployer, accountClass, salt, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDeploymentFunds(deployer, &txn); err != nil {
+		return nil, err
+	}
+
+	resp, err := deployer.DeployAccountTransaction(&txn)
+	if err != nil {
+		return nil, fmt.Errorf("cannot send the deploy account transaction: %w", err)
+	}
+	logger.Infow(
+		"deploy account transaction sent",
+		"transaction hash", resp.Hash,
+		"address", address,
+		"account type", accountClass.Type,
+		"class hash", accountClass.ClassHash,
+	)
+
+	err = waitForAcceptance(
+		ctx, deployer.TransactionStatus, resp.Hash, acceptanceTimeout, logger,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Hash, nil
+}
+
+// Builds and signs the deploy account transaction, with resource bounds set from the
+// fee estimation
+func buildDeployAccount[D signerP.AccountDeployer](
+	deployer D, accountClass *signer.AccountClass, salt, publicKey *felt.Felt,
+) (rpc.BroadcastDeployAccountTxnV3, error) {
+	txn, err := deployer.BuildDeployAccountTransaction(
+		accountClass.ClassHash, salt, accountClass.ConstructorCalldata(publicKey),
+	)
+	if err != nil {
+		return rpc.BroadcastDeployAccountTxnV3{}, err
+	}
+
+	// Signed with the query bit version, so the signature only serves for the estimation
+	txn.Version = rpc.TransactionV3WithQueryBit
+	if err := deployer.SignDeployAccountTransaction(&txn); err != nil {
+		return rpc.BroadcastDeployAccountTxnV3{}, err
+	}
+	estimate, err := deployer.EstimateDeployAccountFee(&txn)
+	if err != nil {
+		return rpc.BroadcastDeployAccountTxnV3{}, fmt.Errorf("cannot estimate fee: %w", err)
+	}
+	txn.ResourceBounds = snUtils.FeeEstToResBoundsMap(estimate, constants.FeeEstimationMultiplier)
+
+	txn.Version = rpc.TransactionV3
+	if err := deployer.SignDeployAccountTransaction(&txn); err != nil {
+		return rpc.BroadcastDeployAccountTxnV3{}, err
+	}
+
+	return txn, nil
+}
+
+// Checks the operational address holds enough STRK to pay the highest fee the
+// transaction allows
+func checkDeploymentFunds[D signerP.AccountDeployer](
+	deployer D, txn *rpc.BroadcastDeployAccountTxnV3,
+) error {
+	maxFee, err := snUtils.ResBoundsMapToOverallFee(txn.ResourceBounds, 1, txn.Tip)
+	if err != nil {
+		return err
+	}
+	balance, err := signerP.FetchValidatorBalance(deployer)
+	if err != nil {
+		return err
+	}
+
+	if (*big.Int)(&balance).Cmp(maxFee.BigInt(new(big.Int))) < 0 {
+		return fmt.Errorf(
+			"operational address %s holds %s FRI, the deployment can cost up to %s FRI",
+			deployer.Address(),
+			balance.Text(10),                     //nolint:mnd // Decimal base
+			maxFee.BigInt(new(big.Int)).Text(10), //nolint:mnd // Decimal base
+		)
+	}
+
+	return nil
+}
+
+// Waits until the transaction is accepted, failing if it is reverted, not accepted within
+// the timeout or the context is done first
+func waitForAcceptance(
+	ctx context.Context,
+	transactionStatus func(*felt.Felt) (*rpc.TxnStatusResult, error),
+	txHash *felt.Felt,
+	timeout time.Duration,
+	logger *utils.ZapLogger,
+) error {
+	ctx, cancel := context.WithTimeoutCause(
+		ctx, timeout, fmt.Errorf("transaction %s not accepted after %s", txHash, timeout),
+	)
+	defer cancel()
+
+	for {
+		txStatus, err := transactionStatus(txHash)
+		switch {
+		case err != nil && err.Error() == ErrTxnHashNotFound.Error():
+			logger.Infow("transaction not found yet", "transaction hash", txHash)
+		case err != nil:
+			return fmt.Errorf("cannot get the transaction status: %w", err)
+		case txStatus.ExecutionStatus == rpc.TxnExecutionStatusREVERTED:
+			return fmt.Errorf("transaction %s reverted: %s", txHash, txStatus.FailureReason)
+		case txStatus.FinalityStatus == rpc.TxnStatusAcceptedOnL2 ||
+			txStatus.FinalityStatus == rpc.TxnStatusAcceptedOnL1:
+			logger.Infow(
+				"transaction accepted",
+				"transaction hash", txHash,
+				"finality status", txStatus.FinalityStatus,
+			)
+
+			return nil
+		default:
+			logger.Infow(
+				fmt.Sprintf("transaction %s. Will wait.", txStatus.FinalityStatus),
+				"transaction hash", txHash,
+			)
+		}
+		if err := sleep(ctx, transactionStatusInterval); err != nil {
+			return context.Cause(ctx)
+		}
+	}
+}
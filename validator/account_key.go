@@ -0,0 +1,151 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	signerP "github.com/NethermindEth/starknet-staking-v2/validator/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/types"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	snUtils "github.com/NethermindEth/starknet.go/utils"
+)
+
+// Value returned by `is_valid_signature` for valid signatures, 'VALID' as a short string
+var validSignature = new(felt.Felt).SetBytes([]byte("VALID"))
+
+// Changes the public key of the signer account to the one of the new key, see
+// `ChangeAccountKey`
+func (v *Validator) ChangeAccountKey(
+	ctx context.Context,
+	accountType signer.AccountType,
+	newKey signer.KeyBackend,
+	acceptanceTimeout time.Duration,
+) (*felt.Felt, error) {
+	invoker, err := v.invoker()
+	if err != nil {
+		return nil, err
+	}
+	deployer, ok := v.signer.(signerP.AccountDeployer)
+	if !ok {
+		return nil, errors.New("the public key of the operational account is unknown")
+	}
+	oldPublicKey, err := deployer.AccountPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := providerChainID(ctx, v.provider)
+	if err != nil {
+		return nil, err
+	}
+
+	return ChangeAccountKey(
+		ctx, invoker, accountType, chainID, oldPublicKey, newKey, acceptanceTimeout, &v.logger,
+	)
+}
+
+// Changes the public key of the signer account, of the given type, from the old key to
+// the new one. The change is signed with the old key, by the signer. Returns the hash of
+// the transaction once the account accepts signatures of the new key, failing if it isn't
+// accepted within the acceptance timeout.
+func ChangeAccountKey[I signerP.Invoker](
+	ctx context.Context,
+	invoker I,
+	accountType signer.AccountType,
+	chainID, oldPublicKey *felt.Felt,
+	newKey signer.KeyBackend,
+	acceptanceTimeout time.Duration,
+	logger *utils.ZapLogger,
+) (*felt.Felt, error) {
+	newPublicKey := new(felt.Felt).SetBigInt(newKey.PublicKey())
+	if newPublicKey.Equal(oldPublicKey) {
+		return nil, fmt.Errorf("the account key is already %s", newPublicKey)
+	}
+
+	calls, err := signer.KeyChangeCalls(
+		ctx, accountType, invoker.Address().Felt(), chainID, oldPublicKey, newKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	txHash, err := invoke(ctx, invoker, calls, acceptanceTimeout, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := VerifyAccountKey(ctx, invoker, chainID, newKey); err != nil {
+		return nil, err
+	}
+	logger.Infow(
+		"account key changed",
+		"address", invoker.Address(),
+		"old public key", oldPublicKey,
+		"new public key", newPublicKey,
+	)
+
+	return txHash, nil
+}
+
+// Checks the signer account accepts signatures of the key, see `VerifyAccountKey`
+func (v *Validator) VerifyAccountKey(ctx context.Context, key signer.KeyBackend) error {
+	chainID, err := providerChainID(ctx, v.provider)
+	if err != nil {
+		return err
+	}
+
+	return VerifyAccountKey(ctx, v.signer, chainID, key)
+}
+
+// Checks the signer account accepts signatures of the key, with a dry run: an attest
+// transaction is built and signed with the key, and the account asked whether the
+// signature is valid. Nothing is sent.
+func VerifyAccountKey[S signerP.Signer](
+	ctx context.Context, accountSigner S, chainID *felt.Felt, key signer.KeyBackend,
+) error {
+	txn, err := accountSigner.BuildAttestTransaction(new(types.BlockHash))
+	if err != nil {
+		return err
+	}
+	txHash, err := hash.TransactionHashInvokeV3(&txn, chainID)
+	if err != nil {
+		return fmt.Errorf("cannot hash the attest transaction: %w", err)
+	}
+	r, s, err := key.Sign(ctx, txHash.BigInt(new(big.Int)))
+	if err != nil {
+		return fmt.Errorf("cannot sign the attest transaction: %w", err)
+	}
+
+	result, err := accountSigner.Call(
+		rpc.FunctionCall{
+			ContractAddress:    accountSigner.Address().Felt(),
+			EntryPointSelector: snUtils.GetSelectorFromNameFelt("is_valid_signature"),
+			Calldata: []*felt.Felt{
+				txHash,
+				new(felt.Felt).SetUint64(2), //nolint:mnd // Length of the signature
+				new(felt.Felt).SetBigInt(r),
+				new(felt.Felt).SetBigInt(s),
+			},
+		},
+		rpc.WithBlockTag(rpc.BlockTagLatest),
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot check the signature of account %s: %w", accountSigner.Address(), err,
+		)
+	}
+	if len(result) != 1 || !result[0].Equal(validSignature) {
+		return fmt.Errorf(
+			"account %s doesn't accept signatures of public key %s",
+			accountSigner.Address(),
+			new(felt.Felt).SetBigInt(key.PublicKey()),
+		)
+	}
+
+	return nil
+}
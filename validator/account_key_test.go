@@ -0,0 +1,147 @@
+package validator_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/mocks"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator"
+	signerP "github.com/NethermindEth/starknet-staking-v2/validator/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/types"
+	"github.com/NethermindEth/starknet.go/hash"
+	"github.com/NethermindEth/starknet.go/rpc"
+	snUtils "github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestChangeAccountKey(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+	validator.After = func(time.Duration) <-chan time.Time { return time.After(0) }
+	t.Cleanup(func() { validator.After = time.After })
+
+	address := types.AddressFromString("0xabc")
+	chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+	oldPublicKey := utils.HexToFelt(t, "0x111")
+	newKey := signer.NewMemoryKey(big.NewInt(0x123))
+	newPublicKey := utils.HexToFelt(
+		t, "0x566d69d8c99f62bc71118399bab25c1f03719463eab8d6a444cd11ece131616",
+	)
+	txHash := utils.HexToFelt(t, "0xdef")
+	resources := signerP.MakeDefaultResources()
+	attestTxn := rpc.BroadcastInvokeTxnV3{
+		Type:                  rpc.TransactionTypeInvoke,
+		Version:               rpc.TransactionV3,
+		SenderAddress:         address.Felt(),
+		Nonce:                 new(felt.Felt).SetUint64(1),
+		Calldata:              []*felt.Felt{new(felt.Felt).SetUint64(1)},
+		Signature:             []*felt.Felt{},
+		Tip:                   "0x0",
+		PayMasterData:         []*felt.Felt{},
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         rpc.DAModeL1,
+		FeeMode:               rpc.DAModeL1,
+		ResourceBounds:        &resources,
+	}
+	attestHash, err := hash.TransactionHashInvokeV3(&attestTxn, chainID)
+	require.NoError(t, err)
+
+	newInvoker := func(t *testing.T) *mocks.MockInvoker {
+		t.Helper()
+
+		mockCtrl := gomock.NewController(t)
+		invoker := mocks.NewMockInvoker(mockCtrl)
+		invoker.EXPECT().Address().Return(&address).AnyTimes()
+
+		return invoker
+	}
+
+	// Expects the dry-run attest to be built and its signature checked by the account,
+	// which answers with the result
+	expectDryRun := func(invoker *mocks.MockInvoker, result *felt.Felt) {
+		invoker.EXPECT().BuildAttestTransaction(new(types.BlockHash)).Return(attestTxn, nil)
+		invoker.EXPECT().
+			Call(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+				require.Equal(t, address.Felt(), call.ContractAddress)
+				require.Equal(
+					t, snUtils.GetSelectorFromNameFelt("is_valid_signature"), call.EntryPointSelector,
+				)
+				require.Equal(t, attestHash, call.Calldata[0])
+				require.Equal(t, new(felt.Felt).SetUint64(2), call.Calldata[1])
+
+				return []*felt.Felt{result}, nil
+			})
+	}
+
+	t.Run("Change the key of an OpenZeppelin account", func(t *testing.T) {
+		invoker := newInvoker(t)
+		invoker.EXPECT().
+			BuildInvokeTransaction([]rpc.InvokeFunctionCall{{
+				ContractAddress: address.Felt(),
+				FunctionName:    "set_public_key",
+				CallData:        []*felt.Felt{newPublicKey},
+			}}).
+			Return(rpc.BroadcastInvokeTxnV3{Version: rpc.TransactionV3}, nil)
+		invoker.EXPECT().SignInvokeTransaction(gomock.Any()).Times(2)
+		invoker.EXPECT().EstimateFee(gomock.Any()).Return(rpc.FeeEstimation{
+			FeeEstimationCommon: rpc.FeeEstimationCommon{
+				L1GasConsumed:     new(felt.Felt),
+				L1GasPrice:        new(felt.Felt),
+				L2GasConsumed:     new(felt.Felt).SetUint64(0x1000),
+				L2GasPrice:        new(felt.Felt).SetUint64(0x10),
+				L1DataGasConsumed: new(felt.Felt),
+				L1DataGasPrice:    new(felt.Felt),
+			},
+		}, nil)
+		invoker.EXPECT().
+			InvokeTransaction(gomock.Any()).
+			Return(rpc.AddInvokeTransactionResponse{Hash: txHash}, nil)
+		invoker.EXPECT().TransactionStatus(txHash).Return(&rpc.TxnStatusResult{
+			FinalityStatus:  rpc.TxnStatusAcceptedOnL2,
+			ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+		}, nil)
+		expectDryRun(invoker, new(felt.Felt).SetBytes([]byte("VALID")))
+
+		changedBy, err := validator.ChangeAccountKey(
+			t.Context(),
+			invoker,
+			signer.AccountTypeOpenZeppelin,
+			chainID,
+			oldPublicKey,
+			newKey,
+			time.Minute,
+			logger,
+		)
+		require.NoError(t, err)
+		require.Equal(t, txHash, changedBy)
+	})
+
+	t.Run("Key already in use", func(t *testing.T) {
+		_, err := validator.ChangeAccountKey(
+			t.Context(),
+			newInvoker(t),
+			signer.AccountTypeOpenZeppelin,
+			chainID,
+			newPublicKey,
+			newKey,
+			time.Minute,
+			logger,
+		)
+		require.ErrorContains(t, err, "the account key is already")
+	})
+
+	t.Run("Account refusing signatures of the key", func(t *testing.T) {
+		invoker := newInvoker(t)
+		expectDryRun(invoker, new(felt.Felt))
+
+		err := validator.VerifyAccountKey(t.Context(), invoker, chainID, newKey)
+		require.ErrorContains(
+			t, err, "doesn't accept signatures of public key "+newPublicKey.String(),
+		)
+	})
+}
@@ -0,0 +1,185 @@
+package validator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/mocks"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator"
+	"github.com/NethermindEth/starknet-staking-v2/validator/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeployAccount(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+	validator.After = func(time.Duration) <-chan time.Time { return time.After(0) }
+	t.Cleanup(func() { validator.After = time.After })
+
+	// Public key for private key "0x123"
+	publicKey := utils.HexToFelt(
+		t, "0x566d69d8c99f62bc71118399bab25c1f03719463eab8d6a444cd11ece131616",
+	)
+	accountClass, err := signer.NewAccountClass(signer.AccountTypeOpenZeppelin, nil)
+	require.NoError(t, err)
+	address := types.Address(*accountClass.Address(publicKey, publicKey))
+	txHash := utils.HexToFelt(t, "0xabc")
+
+	feeEstimate := rpc.FeeEstimation{
+		FeeEstimationCommon: rpc.FeeEstimationCommon{
+			L1GasConsumed:     new(felt.Felt),
+			L1GasPrice:        new(felt.Felt),
+			L2GasConsumed:     new(felt.Felt).SetUint64(0x1000),
+			L2GasPrice:        new(felt.Felt).SetUint64(0x10),
+			L1DataGasConsumed: new(felt.Felt),
+			L1DataGasPrice:    new(felt.Felt),
+		},
+	}
+
+	newDeployer := func(t *testing.T) *mocks.MockAccountDeployer {
+		t.Helper()
+
+		mockCtrl := gomock.NewController(t)
+		deployer := mocks.NewMockAccountDeployer(mockCtrl)
+		deployer.EXPECT().AccountPublicKey().Return(publicKey, nil).AnyTimes()
+		deployer.EXPECT().Address().Return(&address).AnyTimes()
+
+		return deployer
+	}
+
+	// Expects the transaction to be built, signed and estimated, with the STRK balance of
+	// the operational address
+	expectBuild := func(deployer *mocks.MockAccountDeployer, balance uint64) {
+		deployer.EXPECT().AccountDeployed().Return(false, nil)
+		deployer.EXPECT().
+			BuildDeployAccountTransaction(
+				accountClass.ClassHash, publicKey, accountClass.ConstructorCalldata(publicKey),
+			).
+			Return(rpc.BroadcastDeployAccountTxnV3{Tip: "0x0"}, nil)
+		gomock.InOrder(
+			deployer.EXPECT().
+				SignDeployAccountTransaction(gomock.Any()).
+				Do(func(txn *rpc.BroadcastDeployAccountTxnV3) {
+					require.Equal(t, rpc.TransactionV3WithQueryBit, txn.Version)
+				}),
+			deployer.EXPECT().EstimateDeployAccountFee(gomock.Any()).Return(feeEstimate, nil),
+		)
+		deployer.EXPECT().
+			Call(gomock.Any(), gomock.Any()).
+			Return([]*felt.Felt{new(felt.Felt).SetUint64(balance), new(felt.Felt)}, nil)
+	}
+
+	t.Run("Deploy the operational account", func(t *testing.T) {
+		deployer := newDeployer(t)
+		expectBuild(deployer, 1e18)
+		deployer.EXPECT().
+			SignDeployAccountTransaction(gomock.Any()).
+			Do(func(txn *rpc.BroadcastDeployAccountTxnV3) {
+				require.Equal(t, rpc.TransactionV3, txn.Version)
+				require.Equal(t, rpc.U64("0x1800"), txn.ResourceBounds.L2Gas.MaxAmount)
+			})
+		deployer.EXPECT().
+			DeployAccountTransaction(gomock.Any()).
+			Return(rpc.AddDeployAccountTransactionResponse{
+				Hash: txHash, ContractAddress: address.Felt(),
+			}, nil)
+		gomock.InOrder(
+			deployer.EXPECT().TransactionStatus(txHash).Return(nil, validator.ErrTxnHashNotFound),
+			deployer.EXPECT().TransactionStatus(txHash).Return(&rpc.TxnStatusResult{
+				FinalityStatus:  rpc.TxnStatusAcceptedOnL2,
+				ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+			}, nil),
+		)
+
+		deployedBy, err := validator.DeployAccount(
+			t.Context(), deployer, &accountClass, nil, time.Minute, logger,
+		)
+		require.NoError(t, err)
+		require.Equal(t, txHash, deployedBy)
+	})
+
+	t.Run("Account already deployed", func(t *testing.T) {
+		deployer := newDeployer(t)
+		deployer.EXPECT().AccountDeployed().Return(true, nil)
+
+		_, err := validator.DeployAccount(
+			t.Context(), deployer, &accountClass, nil, time.Minute, logger,
+		)
+		require.ErrorIs(t, err, validator.ErrAccountDeployed)
+	})
+
+	t.Run("Account deployed at another address", func(t *testing.T) {
+		deployer := newDeployer(t)
+		deployer.EXPECT().AccountDeployed().Return(false, nil)
+
+		_, err := validator.DeployAccount(
+			t.Context(), deployer, &accountClass, new(felt.Felt).SetUint64(1), time.Minute, logger,
+		)
+		require.ErrorContains(t, err, "not at the operational address")
+	})
+
+	t.Run("Not enough STRK to pay for the deployment", func(t *testing.T) {
+		deployer := newDeployer(t)
+		expectBuild(deployer, 1)
+		deployer.EXPECT().SignDeployAccountTransaction(gomock.Any())
+
+		_, err := validator.DeployAccount(
+			t.Context(), deployer, &accountClass, nil, time.Minute, logger,
+		)
+		require.ErrorContains(t, err, "holds 1 FRI")
+	})
+
+	t.Run("Deployment reverted", func(t *testing.T) {
+		deployer := newDeployer(t)
+		expectBuild(deployer, 1e18)
+		deployer.EXPECT().SignDeployAccountTransaction(gomock.Any())
+		deployer.EXPECT().
+			DeployAccountTransaction(gomock.Any()).
+			Return(rpc.AddDeployAccountTransactionResponse{Hash: txHash}, nil)
+		deployer.EXPECT().TransactionStatus(txHash).Return(&rpc.TxnStatusResult{
+			FinalityStatus:  rpc.TxnStatusAcceptedOnL2,
+			ExecutionStatus: rpc.TxnExecutionStatusREVERTED,
+			FailureReason:   "some reason",
+		}, nil)
+
+		_, err := validator.DeployAccount(
+			t.Context(), deployer, &accountClass, nil, time.Minute, logger,
+		)
+		require.ErrorContains(t, err, "reverted: some reason")
+	})
+
+	t.Run("Deployment not accepted within the timeout", func(t *testing.T) {
+		deployer := newDeployer(t)
+		expectBuild(deployer, 1e18)
+		deployer.EXPECT().SignDeployAccountTransaction(gomock.Any())
+		deployer.EXPECT().
+			DeployAccountTransaction(gomock.Any()).
+			Return(rpc.AddDeployAccountTransactionResponse{Hash: txHash}, nil)
+		deployer.EXPECT().
+			TransactionStatus(txHash).
+			Return(nil, validator.ErrTxnHashNotFound).
+			AnyTimes()
+
+		_, err := validator.DeployAccount(
+			t.Context(), deployer, &accountClass, nil, 50*time.Millisecond, logger,
+		)
+		require.ErrorContains(t, err, "transaction 0xabc not accepted after 50ms")
+	})
+
+	t.Run("Braavos accounts are not deployed", func(t *testing.T) {
+		braavosClass, err := signer.NewAccountClass(signer.AccountTypeBraavos, nil)
+		require.NoError(t, err)
+
+		deployer := newDeployer(t)
+		deployer.EXPECT().AccountDeployed().Return(false, nil)
+
+		_, err = validator.DeployAccount(
+			t.Context(), deployer, &braavosClass, nil, time.Minute, logger,
+		)
+		require.ErrorContains(t, err, "deploying braavos accounts is not supported")
+	})
+}
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"context"
 	"math"
 
 	junoUtils "github.com/NethermindEth/juno/utils"
@@ -9,7 +10,12 @@ import (
 )
 
 func CheckBalance[S signerP.Signer](
-	signer S, threshold float64, logger *junoUtils.ZapLogger, tracer metrics.Tracer,
+	ctx context.Context,
+	signer S,
+	threshold float64,
+	funder *Funder,
+	logger *junoUtils.ZapLogger,
+	tracer metrics.Tracer,
 ) {
 	// call the stark token balance based on the signer address
 	// record the balance
@@ -48,6 +54,9 @@ func CheckBalance[S signerP.Signer](
 	if balance <= threshold {
 		logger.Warnf("Balance below threshold: %f <= %f", balance, threshold)
 		tracer.RecordSignerBalanceBelowThreshold()
+		if funder != nil {
+			TopUp(ctx, signer, &balanceWei, funder, logger, tracer)
+		}
 	} else {
 		tracer.RecordSignerBalanceAboveThreshold()
 	}
@@ -1,28 +1,37 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet-staking-v2/secret"
 )
 
+// Provider urls are secrets since they usually carry the provider API key
 type Provider struct {
-	HTTP string `json:"http"`
-	WS   string `json:"ws"`
+	HTTP secret.Secret `json:"http"`
+	WS   secret.Secret `json:"ws"`
 }
 
 func ProviderFromEnv() Provider {
 	return Provider{
-		HTTP: os.Getenv("PROVIDER_HTTP_URL"),
-		WS:   os.Getenv("PROVIDER_WS_URL"),
+		HTTP: secret.New(os.Getenv("PROVIDER_HTTP_URL")),
+		WS:   secret.New(os.Getenv("PROVIDER_WS_URL")),
 	}
 }
 
 func (p *Provider) Check() error {
-	if p.HTTP == "" {
+	if p.HTTP.IsZero() {
 		return errors.New("http provider url not set in provider configuration")
 	}
-	if p.WS == "" {
+	if p.WS.IsZero() {
 		return errors.New("ws provider url not set in provider configuration")
 	}
 
@@ -39,57 +48,475 @@ func (p *Provider) Fill(other *Provider) {
 	}
 }
 
+// Identifies the backend used to sign the validator transactions
+type SignerType string
+
+const (
+	InternalSigner SignerType = "internal"
+	ExternalSigner SignerType = "external"
+	// Internal signing with the private key loaded from an encrypted keystore
+	KeystoreSigner SignerType = "keystore"
+)
+
+// Returns the signer types a backend is registered for. Set by the signer registry, which
+// the configuration can't depend on. Only the built-in types are valid while it is unset
+var RegisteredSignerTypes func() []SignerType
+
+// Returns an error naming the valid signer types if no backend is registered for the type
+func checkSignerType(signerType SignerType) error {
+	if RegisteredSignerTypes == nil {
+		return fmt.Errorf("unknown signer type %q", signerType)
+	}
+	signerTypes := RegisteredSignerTypes()
+	if slices.Contains(signerTypes, signerType) {
+		return nil
+	}
+	names := make([]string, len(signerTypes))
+	for i, validType := range signerTypes {
+		names[i] = string(validType)
+	}
+
+	return fmt.Errorf(
+		"unknown signer type %q, expected one of: %s", signerType, strings.Join(names, ", "),
+	)
+}
+
+const (
+	// Time an external signer has to answer a request before the next one is tried
+	DefaultSignerRequestTimeout = 10 * time.Second
+	// Time between health checks of redundant external signers
+	DefaultSignerHealthCheckInterval = 30 * time.Second
+)
+
 type Signer struct {
-	ExternalURL        string `json:"url"`
-	PrivKey            string `json:"privateKey"`
-	OperationalAddress string `json:"operationalAddress"`
+	Type                 SignerType    `json:"type,omitempty"`
+	ExternalURL          string        `json:"url"`
+	PrivKey              secret.Secret `json:"privateKey"`
+	Keystore             string        `json:"keystore,omitempty"`
+	KeystorePasswordFile string        `json:"keystorePasswordFile,omitempty"`
+	// Password of the keystore, usually given as a `file:`, `env:` or `exec:` reference.
+	// Read before the password file
+	KeystorePassword   secret.Secret `json:"keystorePassword"`
+	OperationalAddress string        `json:"operationalAddress"`
+	// Redundant external signers holding the same key. They are tried in order, after
+	// the external url if it is set, when the previous ones fail
+	ExternalURLs []string `json:"urls,omitempty"`
+	// Durations such as `5s`, defaulting to `DefaultSignerRequestTimeout` and
+	// `DefaultSignerHealthCheckInterval`
+	RequestTimeout      string `json:"requestTimeout,omitempty"`
+	HealthCheckInterval string `json:"healthCheckInterval,omitempty"`
+	// Credentials to authenticate against the external signer. Either a bearer token or
+	// a HMAC key together with the client ID
+	AuthClientID string        `json:"authClientId,omitempty"`
+	AuthToken    secret.Secret `json:"authToken"`
+	AuthHMACKey  secret.Secret `json:"authHmacKey"`
+	// TLS settings to reach the external signer: a CA bundle to verify its certificate,
+	// a client certificate and key for mutual TLS and the expected server name
+	TLSCACert     string `json:"tlsCaCert,omitempty"`
+	TLSClientCert string `json:"tlsClientCert,omitempty"`
+	TLSClientKey  string `json:"tlsClientKey,omitempty"`
+	TLSServerName string `json:"tlsServerName,omitempty"`
+	// Public key the external signer signatures are verified against. Read from the
+	// operational account if not set
+	PublicKey string `json:"publicKey,omitempty"`
 }
 
 func (s *Signer) Check() error {
 	if s.OperationalAddress == "" {
 		return errors.New("operational address is not set in signer configuration")
 	}
-	if s.External() {
-		return nil
+
+	signerType, err := s.ResolveType()
+	if err != nil {
+		return err
+	}
+
+	hasAuth := s.AuthClientID != "" || !s.AuthToken.IsZero() || !s.AuthHMACKey.IsZero()
+	if hasAuth && signerType != ExternalSigner {
+		return errors.New(
+			"conflicting signer configuration: authentication is only used by the external" +
+				" signer",
+		)
+	}
+	hasTLS := s.TLSCACert != "" || s.TLSClientCert != "" || s.TLSClientKey != "" ||
+		s.TLSServerName != ""
+	if hasTLS && signerType != ExternalSigner {
+		return errors.New(
+			"conflicting signer configuration: tls settings are only used by the external signer",
+		)
+	}
+	if s.PublicKey != "" && signerType != ExternalSigner {
+		return errors.New(
+			"conflicting signer configuration: a public key is only used by the external signer",
+		)
+	}
+
+	switch signerType {
+	case InternalSigner:
+		if s.PrivKey.IsZero() {
+			return errors.New("private key is not set for the internal signer")
+		}
+		if len(s.SignerURLs()) > 0 || s.Keystore != "" {
+			return errors.New(
+				"conflicting signer configuration: only a private key is expected for the" +
+					" internal signer",
+			)
+		}
+	case ExternalSigner:
+		if len(s.SignerURLs()) == 0 {
+			return errors.New("external url is not set for the external signer")
+		}
+		if err := s.checkSignerURLs(hasTLS); err != nil {
+			return err
+		}
+		if !s.PrivKey.IsZero() || s.Keystore != "" {
+			return errors.New(
+				"conflicting signer configuration: no private key or keystore is expected for" +
+					" the external signer",
+			)
+		}
+		if !s.AuthToken.IsZero() && !s.AuthHMACKey.IsZero() {
+			return errors.New(
+				"conflicting signer configuration: set either an auth token or an auth hmac key",
+			)
+		}
+		if !s.AuthHMACKey.IsZero() && s.AuthClientID == "" {
+			return errors.New("auth client id is required when authenticating with a hmac key")
+		}
+		if (s.TLSClientCert == "") != (s.TLSClientKey == "") {
+			return errors.New("both tls client certificate and key are required for mutual tls")
+		}
+		if s.PublicKey != "" {
+			if _, err := new(felt.Felt).SetString(s.PublicKey); err != nil {
+				return fmt.Errorf("invalid signer public key: %w", err)
+			}
+		}
+	case KeystoreSigner:
+		if s.Keystore == "" {
+			return errors.New("keystore path is not set for the keystore signer")
+		}
+		if !s.KeystorePassword.IsZero() && s.KeystorePasswordFile != "" {
+			return errors.New(
+				"conflicting signer configuration: set either a keystore password or a keystore" +
+					" password file",
+			)
+		}
+		if !s.PrivKey.IsZero() || len(s.SignerURLs()) > 0 {
+			return errors.New(
+				"conflicting signer configuration: only a keystore is expected for the" +
+					" keystore signer",
+			)
+		}
+	default:
+		// Registered signer types are validated by their own backend
+		if err := checkSignerType(signerType); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Signer) checkSignerURLs(hasTLS bool) error {
+	urls := s.SignerURLs()
+	for i, url := range urls {
+		if url == "" {
+			return errors.New("empty external signer url")
+		}
+		if slices.Contains(urls[:i], url) {
+			return fmt.Errorf("external signer url %s is set more than once", url)
+		}
+		if !s.AuthHMACKey.IsZero() && strings.HasPrefix(url, "grpc://") {
+			return errors.New(
+				"hmac authentication is not supported by grpc signers, use an auth token instead",
+			)
+		}
+		if hasTLS && strings.HasPrefix(url, "unix://") {
+			return errors.New("tls is not supported when the signer url is a unix socket")
+		}
 	}
-	if s.PrivKey == "" {
-		return errors.New("neither private key nor external url set in signer configuration")
+
+	if _, err := s.SignerRequestTimeout(); err != nil {
+		return err
+	}
+	if _, err := s.SignerHealthCheckInterval(); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// Returns the urls of the external signers in priority order
+func (s *Signer) SignerURLs() []string {
+	if s.ExternalURL == "" {
+		return s.ExternalURLs
+	}
+
+	return append([]string{s.ExternalURL}, s.ExternalURLs...)
+}
+
+func (s *Signer) SignerRequestTimeout() (time.Duration, error) {
+	return parseSignerDuration(s.RequestTimeout, "request timeout", DefaultSignerRequestTimeout)
+}
+
+func (s *Signer) SignerHealthCheckInterval() (time.Duration, error) {
+	return parseSignerDuration(
+		s.HealthCheckInterval, "health check interval", DefaultSignerHealthCheckInterval,
+	)
+}
+
+func parseSignerDuration(value, name string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid signer %s: %w", name, err)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("signer %s must be positive", name)
+	}
+
+	return duration, nil
+}
+
+// Returns the configured signer type. If it is not explicitly set, it is inferred from
+// the signer fields, failing when the configuration is ambiguous.
+func (s *Signer) ResolveType() (SignerType, error) {
+	if s.Type != "" {
+		return s.Type, nil
+	}
+
+	var candidates []SignerType
+	if len(s.SignerURLs()) > 0 {
+		candidates = append(candidates, ExternalSigner)
+	}
+	if !s.PrivKey.IsZero() {
+		candidates = append(candidates, InternalSigner)
+	}
+	if s.Keystore != "" {
+		candidates = append(candidates, KeystoreSigner)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", errors.New(
+			"neither private key, keystore nor external url set in signer configuration",
+		)
+	case 1:
+		return candidates[0], nil
+	default:
+		return "", errors.New(
+			"ambiguous signer configuration: more than one of private key, keystore and" +
+				" external url are set. Keep only one of them or set the signer type explicitly",
+		)
+	}
+}
+
 func SignerFromEnv() Signer {
 	return Signer{
-		ExternalURL:        os.Getenv("SIGNER_EXTERNAL_URL"),
-		PrivKey:            os.Getenv("SIGNER_PRIVATE_KEY"),
-		OperationalAddress: os.Getenv("SIGNER_OPERATIONAL_ADDRESS"),
+		Type:                 SignerType(os.Getenv("SIGNER_TYPE")),
+		ExternalURL:          os.Getenv("SIGNER_EXTERNAL_URL"),
+		PrivKey:              secret.New(os.Getenv("SIGNER_PRIVATE_KEY")),
+		Keystore:             os.Getenv("SIGNER_KEYSTORE"),
+		KeystorePasswordFile: os.Getenv("SIGNER_KEYSTORE_PASSWORD_FILE"),
+		OperationalAddress:   os.Getenv("SIGNER_OPERATIONAL_ADDRESS"),
+		ExternalURLs:         listFromEnv("SIGNER_EXTERNAL_URLS"),
+		RequestTimeout:       os.Getenv("SIGNER_REQUEST_TIMEOUT"),
+		HealthCheckInterval:  os.Getenv("SIGNER_HEALTH_CHECK_INTERVAL"),
+		AuthClientID:         os.Getenv("SIGNER_AUTH_CLIENT_ID"),
+		AuthToken:            secret.New(os.Getenv("SIGNER_AUTH_TOKEN")),
+		AuthHMACKey:          secret.New(os.Getenv("SIGNER_AUTH_HMAC_KEY")),
+		TLSCACert:            os.Getenv("SIGNER_TLS_CA_CERT"),
+		TLSClientCert:        os.Getenv("SIGNER_TLS_CLIENT_CERT"),
+		TLSClientKey:         os.Getenv("SIGNER_TLS_CLIENT_KEY"),
+		TLSServerName:        os.Getenv("SIGNER_TLS_SERVER_NAME"),
+		PublicKey:            os.Getenv("SIGNER_PUBLIC_KEY"),
 	}
 }
 
 // Merge its missing fields with data from other signer
 func (s *Signer) Fill(other *Signer) {
+	if isZero(s.Type) {
+		s.Type = other.Type
+	}
 	if isZero(s.ExternalURL) {
 		s.ExternalURL = other.ExternalURL
 	}
 	if isZero(s.PrivKey) {
 		s.PrivKey = other.PrivKey
 	}
+	if isZero(s.Keystore) {
+		s.Keystore = other.Keystore
+	}
+	if isZero(s.KeystorePasswordFile) {
+		s.KeystorePasswordFile = other.KeystorePasswordFile
+	}
+	if isZero(s.KeystorePassword) {
+		s.KeystorePassword = other.KeystorePassword
+	}
 	if isZero(s.OperationalAddress) {
 		s.OperationalAddress = other.OperationalAddress
 	}
+	if len(s.ExternalURLs) == 0 {
+		s.ExternalURLs = other.ExternalURLs
+	}
+	if isZero(s.RequestTimeout) {
+		s.RequestTimeout = other.RequestTimeout
+	}
+	if isZero(s.HealthCheckInterval) {
+		s.HealthCheckInterval = other.HealthCheckInterval
+	}
+	if isZero(s.AuthClientID) {
+		s.AuthClientID = other.AuthClientID
+	}
+	if isZero(s.AuthToken) {
+		s.AuthToken = other.AuthToken
+	}
+	if isZero(s.AuthHMACKey) {
+		s.AuthHMACKey = other.AuthHMACKey
+	}
+	if isZero(s.TLSCACert) {
+		s.TLSCACert = other.TLSCACert
+	}
+	if isZero(s.TLSClientCert) {
+		s.TLSClientCert = other.TLSClientCert
+	}
+	if isZero(s.TLSClientKey) {
+		s.TLSClientKey = other.TLSClientKey
+	}
+	if isZero(s.TLSServerName) {
+		s.TLSServerName = other.TLSServerName
+	}
+	if isZero(s.PublicKey) {
+		s.PublicKey = other.PublicKey
+	}
+}
+
+// Verifies the signer can be set up without typing its keystore password in, as needed by
+// the signers of the validator other than the operational one. They are set up once the
+// validator runs, where the password can't be prompted for, and don't share the
+// `SIGNER_KEYSTORE_PASSWORD` env var of the operational signer
+func (s *Signer) checkUnattended() error {
+	signerType, err := s.ResolveType()
+	if err != nil {
+		return err
+	}
+	if signerType == KeystoreSigner && s.KeystorePassword.IsZero() &&
+		s.KeystorePasswordFile == "" {
+		return errors.New(
+			"keystore password is not set: set a keystore password or a keystore password file",
+		)
+	}
+
+	return nil
 }
 
 func (s *Signer) External() bool {
-	return s.ExternalURL != ""
+	signerType, err := s.ResolveType()
+
+	return err == nil && signerType == ExternalSigner
+}
+
+// Automatic claiming of the staker rewards
+type Rewards struct {
+	// Unclaimed rewards, in STRK, from which they are claimed. Rewards are only monitored
+	// when it is not set
+	ClaimThreshold float64 `json:"claimThreshold,omitempty"`
+	// Signer of the account claiming the rewards, either the staker or its reward address.
+	// Its operational address is the address of that account
+	Claimer Signer `json:"claimer"`
+}
+
+func (r *Rewards) Check() error {
+	if r.ClaimThreshold < 0 {
+		return errors.New("rewards claim threshold cannot be negative")
+	}
+	if r.ClaimThreshold == 0 {
+		return nil
+	}
+	if err := r.Claimer.Check(); err != nil {
+		return fmt.Errorf("rewards claimer: %w", err)
+	}
+	if err := r.Claimer.checkUnattended(); err != nil {
+		return fmt.Errorf("rewards claimer: %w", err)
+	}
+
+	return nil
+}
+
+// Merge its missing fields with data from other rewards
+func (r *Rewards) Fill(other *Rewards) {
+	if isZero(r.ClaimThreshold) {
+		r.ClaimThreshold = other.ClaimThreshold
+	}
+	r.Claimer.Fill(&other.Claimer)
+}
+
+// Automatic top-up of the operational account from a funding account
+type TopUp struct {
+	// Balance, in STRK, the operational account is topped up to once it falls below the
+	// balance threshold. Top-ups are disabled when it is not set
+	Target float64 `json:"target,omitempty"`
+	// Most STRK sent to the operational account in 24 hours
+	DailyCap float64 `json:"dailyCap,omitempty"`
+	// Most top-ups sent in 24 hours. Not limited when it is not set
+	DailyTransfers uint64 `json:"dailyTransfers,omitempty"`
+	// File the sent top-ups are kept in, so the daily caps hold across restarts
+	StateFile string `json:"stateFile,omitempty"`
+	// Signer of the funding account. Its operational address is the address of that account
+	Funder Signer `json:"funder"`
+}
+
+func (t *TopUp) Check() error {
+	if t.Target < 0 {
+		return errors.New("top-up target cannot be negative")
+	}
+	if t.Target == 0 {
+		return nil
+	}
+	if t.DailyCap <= 0 {
+		return errors.New("top-up daily cap is not set")
+	}
+	if t.StateFile == "" {
+		return errors.New("top-up state file is not set")
+	}
+	if err := t.Funder.Check(); err != nil {
+		return fmt.Errorf("top-up funder: %w", err)
+	}
+	if err := t.Funder.checkUnattended(); err != nil {
+		return fmt.Errorf("top-up funder: %w", err)
+	}
+
+	return nil
+}
+
+// Merge its missing fields with data from other top-up
+func (t *TopUp) Fill(other *TopUp) {
+	if isZero(t.Target) {
+		t.Target = other.Target
+	}
+	if isZero(t.DailyCap) {
+		t.DailyCap = other.DailyCap
+	}
+	if isZero(t.DailyTransfers) {
+		t.DailyTransfers = other.DailyTransfers
+	}
+	if isZero(t.StateFile) {
+		t.StateFile = other.StateFile
+	}
+	t.Funder.Fill(&other.Funder)
 }
 
 type Config struct {
 	Provider Provider `json:"provider"`
 	Signer   Signer   `json:"signer"`
+	Rewards  Rewards  `json:"rewards"`
+	TopUp    TopUp    `json:"topUp"`
 }
 
 func FromEnv() Config {
+	//nolint:exhaustruct // Rewards and top-ups are only set by flags and the config file
 	return Config{
 		Provider: ProviderFromEnv(),
 		Signer:   SignerFromEnv(),
@@ -119,6 +546,38 @@ func FromData(data []byte) (Config, error) {
 func (c *Config) Fill(other *Config) {
 	c.Provider.Fill(&other.Provider)
 	c.Signer.Fill(&other.Signer)
+	c.Rewards.Fill(&other.Rewards)
+	c.TopUp.Fill(&other.TopUp)
+}
+
+// Replaces every secret given as a `file:`, `env:` or `exec:` reference by its value
+func (c *Config) ResolveSecrets(ctx context.Context) error {
+	secrets := []struct {
+		name  string
+		value *secret.Secret
+	}{
+		{"provider http url", &c.Provider.HTTP},
+		{"provider ws url", &c.Provider.WS},
+		{"signer private key", &c.Signer.PrivKey},
+		{"signer auth token", &c.Signer.AuthToken},
+		{"signer auth hmac key", &c.Signer.AuthHMACKey},
+		{"signer keystore password", &c.Signer.KeystorePassword},
+		{"rewards claimer private key", &c.Rewards.Claimer.PrivKey},
+		{"rewards claimer auth token", &c.Rewards.Claimer.AuthToken},
+		{"rewards claimer auth hmac key", &c.Rewards.Claimer.AuthHMACKey},
+		{"rewards claimer keystore password", &c.Rewards.Claimer.KeystorePassword},
+		{"top-up funder private key", &c.TopUp.Funder.PrivKey},
+		{"top-up funder auth token", &c.TopUp.Funder.AuthToken},
+		{"top-up funder auth hmac key", &c.TopUp.Funder.AuthHMACKey},
+		{"top-up funder keystore password", &c.TopUp.Funder.KeystorePassword},
+	}
+	for _, s := range secrets {
+		if err := s.value.Resolve(ctx); err != nil {
+			return fmt.Errorf("resolving %s: %w", s.name, err)
+		}
+	}
+
+	return nil
 }
 
 // Verifies its data is appropiatly set
@@ -129,10 +588,26 @@ func (c *Config) Check() error {
 	if err := c.Signer.Check(); err != nil {
 		return err
 	}
+	if err := c.Rewards.Check(); err != nil {
+		return err
+	}
+	if err := c.TopUp.Check(); err != nil {
+		return err
+	}
 
 	return nil
 }
 
+// Returns the comma separated values of the environment variable, nil if it is not set
+func listFromEnv(name string) []string {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+
+	return strings.Split(value, ",")
+}
+
 func isZero[T comparable](v T) bool {
 	var x T
 
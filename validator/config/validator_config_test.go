@@ -1,9 +1,14 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/NethermindEth/starknet-staking-v2/secret"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -45,8 +50,8 @@ func TestConfigFromFile(t *testing.T) {
                 "ws": "ws://localhost:1235"
             },
             "signer": {
+                "type": "external",
                 "url": "http://localhost:5678",
-                "privateKey": "0x123", 
                 "operationalAddress": "0x456"
             }
         }`)
@@ -56,12 +61,12 @@ func TestConfigFromFile(t *testing.T) {
 
 		expectedConfig := Config{
 			Provider: Provider{
-				HTTP: "http://localhost:1234",
-				WS:   "ws://localhost:1235",
+				HTTP: secret.New("http://localhost:1234"),
+				WS:   secret.New("ws://localhost:1235"),
 			},
 			Signer: Signer{
+				Type:               ExternalSigner,
 				ExternalURL:        "http://localhost:5678",
-				PrivKey:            "0x123",
 				OperationalAddress: "0x456",
 			},
 		}
@@ -78,8 +83,8 @@ func TestConfigFromEnv(t *testing.T) {
 
 	provider := ProviderFromEnv()
 	expectedProvider := Provider{
-		HTTP: http,
-		WS:   ws,
+		HTTP: secret.New(http),
+		WS:   secret.New(ws),
 	}
 	require.Equal(
 		t,
@@ -88,6 +93,8 @@ func TestConfigFromEnv(t *testing.T) {
 	)
 
 	// Test Signer
+	signerType := "external"
+	t.Setenv("SIGNER_TYPE", signerType)
 	url := "ciao"
 	t.Setenv("SIGNER_EXTERNAL_URL", url)
 	privateKey := "bonjour"
@@ -97,8 +104,9 @@ func TestConfigFromEnv(t *testing.T) {
 
 	signer := SignerFromEnv()
 	expectedSigner := Signer{
+		Type:               SignerType(signerType),
 		ExternalURL:        url,
-		PrivKey:            privateKey,
+		PrivKey:            secret.New(privateKey),
 		OperationalAddress: operationalAddress,
 	}
 	require.Equal(
@@ -213,6 +221,574 @@ func TestCorrectConfig(t *testing.T) {
 		require.NoError(t, err)
 		require.ErrorContains(t, config.Check(), "private key")
 	})
+
+	t.Run("Keystore signer inferred from the keystore path", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "keystore": "/path/to/keystore.json",
+                "keystorePasswordFile": "/path/to/password",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.NoError(t, config.Check())
+
+		signerType, err := config.Signer.ResolveType()
+		require.NoError(t, err)
+		require.Equal(t, KeystoreSigner, signerType)
+	})
+
+	t.Run("Keystore signer type with a private key", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "type": "keystore",
+                "keystore": "/path/to/keystore.json",
+                "privateKey": "0x123",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "conflicting signer configuration")
+	})
+
+	t.Run("Unknown signer type", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "type": "extrenal",
+                "url": "http://localhost:5678",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), `unknown signer type "extrenal"`)
+
+		// The valid types are named once the signer registry is known
+		registeredSignerTypes := RegisteredSignerTypes
+		RegisteredSignerTypes = func() []SignerType {
+			return []SignerType{ExternalSigner, InternalSigner, KeystoreSigner}
+		}
+		t.Cleanup(func() { RegisteredSignerTypes = registeredSignerTypes })
+		require.ErrorContains(
+			t,
+			config.Check(),
+			`unknown signer type "extrenal", expected one of: external, internal, keystore`,
+		)
+	})
+
+	t.Run("Signer type of a registered backend", func(t *testing.T) {
+		registeredSignerTypes := RegisteredSignerTypes
+		RegisteredSignerTypes = func() []SignerType {
+			return []SignerType{"registered-backend", ExternalSigner, InternalSigner, KeystoreSigner}
+		}
+		t.Cleanup(func() { RegisteredSignerTypes = registeredSignerTypes })
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "type": "registered-backend",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.NoError(t, config.Check())
+	})
+
+	t.Run("Private key and external signer without signer type", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "url": "http://localhost:5678",
+                "privateKey": "0x123",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "ambiguous signer configuration")
+		require.False(t, config.Signer.External())
+	})
+
+	t.Run("Internal signer type with an external url", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "type": "internal",
+                "url": "http://localhost:5678",
+                "privateKey": "0x123",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "conflicting signer configuration")
+	})
+
+	t.Run("External signer type with a private key", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "type": "external",
+                "url": "http://localhost:5678",
+                "privateKey": "0x123",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "conflicting signer configuration")
+	})
+
+	t.Run("Signer auth with an internal signer", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "privateKey": "0x123",
+                "authToken": "some token",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "conflicting signer configuration")
+	})
+
+	t.Run("Signer auth with both token and hmac key", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "url": "http://localhost:5678",
+                "authClientId": "validator",
+                "authToken": "some token",
+                "authHmacKey": "some key",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "either an auth token or an auth hmac key")
+	})
+
+	t.Run("Signer auth hmac key without client id", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "url": "http://localhost:5678",
+                "authHmacKey": "some key",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "auth client id is required")
+	})
+
+	t.Run("Signer auth hmac key with a grpc signer", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "url": "grpc://localhost:5678",
+                "authClientId": "validator",
+                "authHmacKey": "some key",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "not supported by grpc signers")
+	})
+
+	t.Run("Signer tls client certificate without key", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "url": "https://localhost:5678",
+                "tlsClientCert": "client.crt",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "both tls client certificate and key")
+	})
+
+	t.Run("Signer tls over a unix socket", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "url": "unix:///run/signer/signer.sock",
+                "tlsCaCert": "ca.crt",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "tls is not supported")
+	})
+
+	t.Run("Redundant external signers", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "url": "http://localhost:5678",
+                "urls": ["http://localhost:5679", "grpc://localhost:5680"],
+                "requestTimeout": "5s",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.NoError(t, config.Check())
+		require.Equal(
+			t,
+			[]string{"http://localhost:5678", "http://localhost:5679", "grpc://localhost:5680"},
+			config.Signer.SignerURLs(),
+		)
+		timeout, err := config.Signer.SignerRequestTimeout()
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Second, timeout)
+		interval, err := config.Signer.SignerHealthCheckInterval()
+		require.NoError(t, err)
+		require.Equal(t, DefaultSignerHealthCheckInterval, interval)
+	})
+
+	t.Run("Redundant external signers without the external url", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "urls": ["http://localhost:5678", "http://localhost:5679"],
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.NoError(t, config.Check())
+		require.True(t, config.Signer.External())
+	})
+
+	t.Run("Same external signer url twice", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "url": "http://localhost:5678",
+                "urls": ["http://localhost:5678"],
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "is set more than once")
+	})
+
+	t.Run("Invalid signer request timeout", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "url": "http://localhost:5678",
+                "requestTimeout": "soon",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "invalid signer request timeout")
+	})
+
+	t.Run("Signer public key for the internal signer", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "privateKey": "0x123",
+                "publicKey": "0x789",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "public key is only used by the external signer")
+	})
+
+	t.Run("Explicit signer type missing its fields", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "type": "internal",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "private key is not set")
+	})
+
+	t.Run("Rewards claimer missing its signer", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "privateKey": "0x123",
+                "operationalAddress": "0x456"
+            },
+            "rewards": {
+                "claimThreshold": 50,
+                "claimer": {
+                    "operationalAddress": "0x789"
+                }
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "rewards claimer")
+	})
+
+	t.Run("Rewards claimer only checked with a threshold", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "privateKey": "0x123",
+                "operationalAddress": "0x456"
+            },
+            "rewards": {
+                "claimer": {
+                    "operationalAddress": "0x789"
+                }
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.NoError(t, config.Check())
+	})
+
+	t.Run("Rewards claimer keystore without a password", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "privateKey": "0x123",
+                "operationalAddress": "0x456"
+            },
+            "rewards": {
+                "claimThreshold": 50,
+                "claimer": {
+                    "keystore": "/path/to/reward.json",
+                    "operationalAddress": "0x789"
+                }
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(
+			t, config.Check(), "rewards claimer: keystore password is not set",
+		)
+
+		config.Rewards.Claimer.KeystorePasswordFile = "/path/to/password"
+		require.NoError(t, config.Check())
+	})
+
+	t.Run("Negative rewards claim threshold", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "privateKey": "0x123",
+                "operationalAddress": "0x456"
+            },
+            "rewards": {
+                "claimThreshold": -1
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "rewards claim threshold cannot be negative")
+	})
+
+	t.Run("Top-up without a daily cap", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "privateKey": "0x123",
+                "operationalAddress": "0x456"
+            },
+            "topUp": {
+                "target": 200,
+                "funder": {
+                    "privateKey": "0x789",
+                    "operationalAddress": "0xabc"
+                }
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "top-up daily cap is not set")
+	})
+
+	t.Run("Top-up without a state file", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "privateKey": "0x123",
+                "operationalAddress": "0x456"
+            },
+            "topUp": {
+                "target": 200,
+                "dailyCap": 500,
+                "funder": {
+                    "privateKey": "0x789",
+                    "operationalAddress": "0xabc"
+                }
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "top-up state file is not set")
+	})
+
+	t.Run("Top-up funder missing its signer", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "privateKey": "0x123",
+                "operationalAddress": "0x456"
+            },
+            "topUp": {
+                "target": 200,
+                "dailyCap": 500,
+                "stateFile": "/path/to/top-ups.json",
+                "funder": {
+                    "operationalAddress": "0xabc"
+                }
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "top-up funder")
+	})
+
+	t.Run("Top-up funder keystore without a password", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "privateKey": "0x123",
+                "operationalAddress": "0x456"
+            },
+            "topUp": {
+                "target": 200,
+                "dailyCap": 500,
+                "stateFile": "/path/to/top-ups.json",
+                "funder": {
+                    "keystore": "/path/to/treasury.json",
+                    "operationalAddress": "0xabc"
+                }
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "top-up funder: keystore password is not set")
+
+		config.TopUp.Funder.KeystorePassword = secret.New("password")
+		require.NoError(t, config.Check())
+	})
+
+	t.Run("Keystore password and password file", func(t *testing.T) {
+		data := []byte(`{
+            "provider": {
+                "http": "http://localhost:1234",
+                "ws": "ws://localhost:1235"
+            },
+            "signer": {
+                "keystore": "/path/to/keystore.json",
+                "keystorePassword": "env:KEYSTORE_PASSWORD",
+                "keystorePasswordFile": "/path/to/password",
+                "operationalAddress": "0x456"
+            }
+        }`)
+		config, err := FromData(data)
+		require.NoError(t, err)
+		require.ErrorContains(t, config.Check(), "conflicting signer configuration")
+	})
 }
 
 func TestConfigFill(t *testing.T) {
@@ -264,3 +840,34 @@ func TestConfigFill(t *testing.T) {
 	assert.Equal(t, expectedConfig1, config1)
 	assert.Equal(t, expectedConfig2, config2)
 }
+
+func TestConfigSecrets(t *testing.T) {
+	privateKeyPath := filepath.Join(t.TempDir(), "private_key")
+	require.NoError(t, os.WriteFile(privateKeyPath, []byte("0x123\n"), 0o600))
+	t.Setenv("TEST_PROVIDER_WS_URL", "ws://localhost:1235/some-api-key")
+
+	config, err := FromData([]byte(`{
+            "provider": {
+                "http": "exec:echo http://localhost:1234/some-api-key",
+                "ws": "env:TEST_PROVIDER_WS_URL"
+            },
+            "signer": {
+                "privateKey": "file:` + privateKeyPath + `",
+                "operationalAddress": "0x456"
+            }
+        }`))
+	require.NoError(t, err)
+	require.NoError(t, config.ResolveSecrets(t.Context()))
+	require.NoError(t, config.Check())
+
+	require.Equal(t, "http://localhost:1234/some-api-key", config.Provider.HTTP.Reveal())
+	require.Equal(t, "ws://localhost:1235/some-api-key", config.Provider.WS.Reveal())
+	require.Equal(t, "0x123", config.Signer.PrivKey.Reveal())
+
+	// Secrets never show up when printing or dumping the config
+	dump, err := json.Marshal(config)
+	require.NoError(t, err)
+	require.NotContains(t, string(dump), "some-api-key")
+	require.NotContains(t, string(dump), "0x123")
+	require.NotContains(t, fmt.Sprintf("%v %+v %#v", config, config, config), "0x123")
+}
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -19,6 +20,10 @@ import (
 // Created a function variable for mocking purposes in tests
 var Sleep = time.Sleep
 
+// Created a function variable for mocking purposes in tests, as `Sleep` for the waits
+// which can be interrupted
+var After = time.After
+
 var ErrTxnHashNotFound = rpc.ErrHashNotFound
 
 type AttestStatus uint8
@@ -31,11 +36,15 @@ const (
 )
 
 type AttestTransaction struct {
-	txn   rpc.BroadcastInvokeTxnV3
-	valid bool
+	txn rpc.BroadcastInvokeTxnV3
+	// Epoch the attestation is for, sent to the signer with the transaction
+	epochID uint64
+	valid   bool
 }
 
-func (t *AttestTransaction) Build(signer signerP.Signer, blockHash *types.BlockHash) error {
+func (t *AttestTransaction) Build(
+	signer signerP.Signer, blockHash *types.BlockHash, epochID uint64,
+) error {
 	t.valid = false
 
 	var err error
@@ -43,8 +52,9 @@ func (t *AttestTransaction) Build(signer signerP.Signer, blockHash *types.BlockH
 	if err != nil {
 		return fmt.Errorf("signer failed building the transaction: %w", err)
 	}
+	t.epochID = epochID
 
-	_, err = signer.SignTransaction(&t.txn)
+	_, err = signer.SignTransaction(&t.txn, t.epochID)
 	if err != nil {
 		return fmt.Errorf("signer failed to sign the transaction: %w", err)
 	}
@@ -73,7 +83,7 @@ func (t *AttestTransaction) Invoke(signer signerP.Signer) (
 	// patch for making sure txn.Version is correct
 	t.txn.Version = rpc.TransactionV3
 
-	_, err = signer.SignTransaction(&t.txn)
+	_, err = signer.SignTransaction(&t.txn, t.epochID)
 	if err != nil {
 		return resp, fmt.Errorf("signer failed to sign the transaction: %w", err)
 	}
@@ -96,7 +106,7 @@ func (t *AttestTransaction) UpdateNonce(signer signerP.Signer) error {
 	}
 	if !t.txn.Nonce.Equal(newNonce) {
 		t.txn.Nonce = newNonce
-		_, err := signer.SignTransaction(&t.txn)
+		_, err := signer.SignTransaction(&t.txn, t.epochID)
 		if err != nil {
 			return fmt.Errorf("signer failed to sign the transaction: %w", err)
 		}
@@ -165,7 +175,12 @@ func NewEventDispatcher[S signerP.Signer]() EventDispatcher[S] {
 
 //nolint:gocyclo // Refactor in another time
 func (d *EventDispatcher[S]) Dispatch(
-	signer S, balanceThreshold float64, logger *junoUtils.ZapLogger, tracer metrics.Tracer,
+	ctx context.Context,
+	signer S,
+	balanceThreshold float64,
+	funder *Funder,
+	logger *junoUtils.ZapLogger,
+	tracer metrics.Tracer,
 ) {
 	var targetBlockHash types.BlockHash
 
@@ -184,7 +199,7 @@ func (d *EventDispatcher[S]) Dispatch(
 
 			targetBlockHash = attest.BlockHash
 			logger.Debugf("building attest transaction for blockhash: %s", targetBlockHash.String())
-			err := d.CurrentAttest.Transaction.Build(signer, &targetBlockHash)
+			err := d.CurrentAttest.Transaction.Build(signer, &targetBlockHash, attest.EpochID)
 			if err != nil {
 				logger.Errorf("failed to build attest transaction: %s", err.Error())
 
@@ -218,7 +233,7 @@ func (d *EventDispatcher[S]) Dispatch(
 					"building attest transaction (in `do` stage) for blockhash: %s",
 					&targetBlockHash,
 				)
-				err := d.CurrentAttest.Transaction.Build(signer, &targetBlockHash)
+				err := d.CurrentAttest.Transaction.Build(signer, &targetBlockHash, attest.EpochID)
 				if err != nil {
 					logger.Errorf("failed to build attest transaction: %s", err.Error())
 
@@ -285,7 +300,7 @@ func (d *EventDispatcher[S]) Dispatch(
 			// clean slate for the next window
 			d.CurrentAttest = NewAttestTracker()
 			// check the account balance
-			go CheckBalance(signer, balanceThreshold, logger, tracer)
+			go CheckBalance(ctx, signer, balanceThreshold, funder, logger, tracer)
 		}
 	}
 }
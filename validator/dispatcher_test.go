@@ -102,7 +102,7 @@ func TestDispatch(t *testing.T) {
 
 			dispatcher := validator.NewEventDispatcher[*mocks.MockSigner]()
 			wg := &conc.WaitGroup{}
-			wg.Go(func() { dispatcher.Dispatch(mockSigner, math.Inf(1), logger, tracer) })
+			wg.Go(func() { dispatcher.Dispatch(t.Context(), mockSigner, math.Inf(1), nil, logger, tracer) })
 
 			// Send event
 			dispatcher.DoAttest <- types.DoAttest{BlockHash: *blockhash}
@@ -30,6 +30,19 @@ type Metrics struct {
 	attestationConfirmedCount       *prometheus.CounterVec
 	signerBalance                   *prometheus.GaugeVec
 	signerBalanceBelowThreshold     *prometheus.GaugeVec
+	stakerUnclaimedRewards          *prometheus.GaugeVec
+	stakerOwnStake                  *prometheus.GaugeVec
+	stakerDelegatedStake            *prometheus.GaugeVec
+	stakerCommission                *prometheus.GaugeVec
+	rewardsClaimedCount             *prometheus.CounterVec
+	topUpSubmittedCount             *prometheus.CounterVec
+	topUpConfirmedCount             *prometheus.CounterVec
+	topUpFailureCount               *prometheus.CounterVec
+	topUpCappedCount                *prometheus.CounterVec
+	topUpAmount                     *prometheus.CounterVec
+	externalSignatureCount          *prometheus.CounterVec
+	externalSignerFailureCount      *prometheus.CounterVec
+	externalSignerHealthy           *prometheus.GaugeVec
 }
 
 // NewMetrics creates a new metrics server
@@ -118,6 +131,97 @@ func NewMetrics(serverAddress, chainID string, logger *utils.ZapLogger) *Metrics
 			},
 			[]string{"network"},
 		),
+		stakerUnclaimedRewards: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "validator_attestation_staker_unclaimed_rewards",
+				Help: "The rewards of the staker not claimed yet, in STRK, read at each epoch",
+			},
+			[]string{"network"},
+		),
+		stakerOwnStake: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "validator_attestation_staker_own_stake",
+				Help: "The stake of the staker, in STRK, without the delegated stake",
+			},
+			[]string{"network"},
+		),
+		stakerDelegatedStake: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "validator_attestation_staker_delegated_stake",
+				Help: "The stake delegated to the staker through its pool, in STRK",
+			},
+			[]string{"network"},
+		),
+		stakerCommission: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "validator_attestation_staker_commission",
+				Help: "The commission of the staker on its pool rewards, in percent",
+			},
+			[]string{"network"},
+		),
+		rewardsClaimedCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "validator_attestation_rewards_claimed_count",
+				Help: "The total number of times the staker rewards were claimed automatically since startup",
+			},
+			[]string{"network"},
+		),
+		topUpSubmittedCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "validator_attestation_top_up_submitted_count",
+				Help: "The total number of top-ups of the signer account sent by the funding account since startup",
+			},
+			[]string{"network"},
+		),
+		topUpConfirmedCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "validator_attestation_top_up_confirmed_count",
+				Help: "The total number of top-ups of the signer account accepted on the network since startup",
+			},
+			[]string{"network"},
+		),
+		topUpFailureCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "validator_attestation_top_up_failure_count",
+				Help: "The total number of top-ups of the signer account that could not be sent or were not accepted since startup",
+			},
+			[]string{"network"},
+		),
+		topUpCappedCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "validator_attestation_top_up_capped_count",
+				Help: "The total number of top-ups of the signer account not sent because the daily caps were reached",
+			},
+			[]string{"network"},
+		),
+		topUpAmount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "validator_attestation_top_up_amount",
+				Help: "The total amount of STRK sent to the signer account by accepted top-ups since startup",
+			},
+			[]string{"network"},
+		),
+		externalSignatureCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "validator_attestation_external_signer_signature_count",
+				Help: "The total number of transactions signed by each external signer since startup",
+			},
+			[]string{"network", "signer"},
+		),
+		externalSignerFailureCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "validator_attestation_external_signer_failure_count",
+				Help: "The total number of signing requests each external signer failed to answer since startup, the next signer being tried instead",
+			},
+			[]string{"network", "signer"},
+		),
+		externalSignerHealthy: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "validator_attestation_external_signer_healthy",
+				Help: "Set to one if the external signer answered its last request or health check",
+			},
+			[]string{"network", "signer"},
+		),
 	}
 
 	// Register metrics with Prometheus registry
@@ -133,6 +237,19 @@ func NewMetrics(serverAddress, chainID string, logger *utils.ZapLogger) *Metrics
 		m.attestationConfirmedCount,
 		m.signerBalance,
 		m.signerBalanceBelowThreshold,
+		m.stakerUnclaimedRewards,
+		m.stakerOwnStake,
+		m.stakerDelegatedStake,
+		m.stakerCommission,
+		m.rewardsClaimedCount,
+		m.topUpSubmittedCount,
+		m.topUpConfirmedCount,
+		m.topUpFailureCount,
+		m.topUpCappedCount,
+		m.topUpAmount,
+		m.externalSignatureCount,
+		m.externalSignerFailureCount,
+		m.externalSignerHealthy,
 	)
 
 	// Create HTTP server
@@ -227,3 +344,71 @@ func (m *Metrics) RecordSignerBalanceBelowThreshold() {
 	m.logger.Debug("RecordSignerBalanceBelowThreshold")
 	m.signerBalanceBelowThreshold.WithLabelValues(m.network).Set(1)
 }
+
+// UpdateStakerInfo sets the staker rewards, stake and commission metrics
+func (m *Metrics) UpdateStakerInfo(stakerInfo *types.StakerInfo) {
+	m.logger.Debugw("UpdateStakerInfo", "stakerInfo", stakerInfo)
+	m.stakerUnclaimedRewards.WithLabelValues(m.network).Set(stakerInfo.UnclaimedRewards.Strk())
+	m.stakerOwnStake.WithLabelValues(m.network).Set(stakerInfo.Stake.Strk())
+	delegatedStake, commission := 0.0, 0.0
+	if stakerInfo.Pool != nil {
+		delegatedStake = stakerInfo.Pool.Amount.Strk()
+		//nolint:mnd // The commission is in hundredths of a percent
+		commission = float64(stakerInfo.Pool.Commission) / 100
+	}
+	m.stakerDelegatedStake.WithLabelValues(m.network).Set(delegatedStake)
+	m.stakerCommission.WithLabelValues(m.network).Set(commission)
+}
+
+// RecordRewardsClaimed increments the rewards claimed counter
+func (m *Metrics) RecordRewardsClaimed() {
+	m.logger.Debugw("RecordRewardsClaimed")
+	m.rewardsClaimedCount.WithLabelValues(m.network).Inc()
+}
+
+// RecordTopUpSubmitted increments the top-up submitted counter
+func (m *Metrics) RecordTopUpSubmitted() {
+	m.logger.Debugw("RecordTopUpSubmitted")
+	m.topUpSubmittedCount.WithLabelValues(m.network).Inc()
+}
+
+// RecordTopUpConfirmed increments the top-up confirmed counter and the amount sent
+func (m *Metrics) RecordTopUpConfirmed(amount float64) {
+	m.logger.Debugw("RecordTopUpConfirmed", "amount", amount)
+	m.topUpConfirmedCount.WithLabelValues(m.network).Inc()
+	m.topUpAmount.WithLabelValues(m.network).Add(amount)
+}
+
+// RecordTopUpFailure increments the top-up failure counter
+func (m *Metrics) RecordTopUpFailure() {
+	m.logger.Debugw("RecordTopUpFailure")
+	m.topUpFailureCount.WithLabelValues(m.network).Inc()
+}
+
+// RecordTopUpCapped increments the top-up capped counter
+func (m *Metrics) RecordTopUpCapped() {
+	m.logger.Debugw("RecordTopUpCapped")
+	m.topUpCappedCount.WithLabelValues(m.network).Inc()
+}
+
+// RecordExternalSignature increments the signature counter of the external signer
+func (m *Metrics) RecordExternalSignature(signerURL string) {
+	m.logger.Debugw("RecordExternalSignature", "signer", signerURL)
+	m.externalSignatureCount.WithLabelValues(m.network, signerURL).Inc()
+}
+
+// RecordExternalSignerFailure increments the failure counter of the external signer
+func (m *Metrics) RecordExternalSignerFailure(signerURL string) {
+	m.logger.Debugw("RecordExternalSignerFailure", "signer", signerURL)
+	m.externalSignerFailureCount.WithLabelValues(m.network, signerURL).Inc()
+}
+
+// UpdateExternalSignerHealth sets the value to 1 if the external signer is healthy, 0 otherwise
+func (m *Metrics) UpdateExternalSignerHealth(signerURL string, healthy bool) {
+	m.logger.Debugw("UpdateExternalSignerHealth", "signer", signerURL, "healthy", healthy)
+	value := 0.0
+	if healthy {
+		value = 1
+	}
+	m.externalSignerHealthy.WithLabelValues(m.network, signerURL).Set(value)
+}
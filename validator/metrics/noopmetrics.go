@@ -25,3 +25,21 @@ func (m *NoOpMetrics) RecordAttestationConfirmed() {}
 func (m *NoOpMetrics) RecordSignerBalanceAboveThreshold() {}
 
 func (m *NoOpMetrics) RecordSignerBalanceBelowThreshold() {}
+
+func (m *NoOpMetrics) UpdateStakerInfo(stakerInfo *types.StakerInfo) {}
+
+func (m *NoOpMetrics) RecordRewardsClaimed() {}
+
+func (m *NoOpMetrics) RecordTopUpSubmitted() {}
+
+func (m *NoOpMetrics) RecordTopUpConfirmed(amount float64) {}
+
+func (m *NoOpMetrics) RecordTopUpFailure() {}
+
+func (m *NoOpMetrics) RecordTopUpCapped() {}
+
+func (m *NoOpMetrics) RecordExternalSignature(signerURL string) {}
+
+func (m *NoOpMetrics) RecordExternalSignerFailure(signerURL string) {}
+
+func (m *NoOpMetrics) UpdateExternalSignerHealth(signerURL string, healthy bool) {}
@@ -13,4 +13,16 @@ type Tracer interface {
 	RecordAttestationConfirmed()
 	RecordSignerBalanceAboveThreshold()
 	RecordSignerBalanceBelowThreshold()
+	// Read from the staking contract once per epoch
+	UpdateStakerInfo(stakerInfo *types.StakerInfo)
+	RecordRewardsClaimed()
+	// Top-ups of the operational account from the funding account, the amount in STRK
+	RecordTopUpSubmitted()
+	RecordTopUpConfirmed(amount float64)
+	RecordTopUpFailure()
+	RecordTopUpCapped()
+	// Labelled with the url of the external signer, for validators with redundant ones
+	RecordExternalSignature(signerURL string)
+	RecordExternalSignerFailure(signerURL string)
+	UpdateExternalSignerHealth(signerURL string, healthy bool)
 }
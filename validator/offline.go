@@ -0,0 +1,171 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	signerP "github.com/NethermindEth/starknet-staking-v2/validator/signer"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	snUtils "github.com/NethermindEth/starknet.go/utils"
+)
+
+// L2 gas added to the fee estimation of transactions signed offline, which can only be
+// estimated without the account validation. Validating the signature of a standard
+// account takes well below it
+const offlineValidationL2Gas = 1_000_000
+
+// Builds the unsigned invoke transaction of the calls, sent by the account at the sender
+// address, to be signed offline. It carries the chain id, the next nonce of the account
+// and resource bounds from a fee estimation. The estimation skips the account validation,
+// which can't run without the signature, so the L2 gas of the validation is added to it.
+func BuildOfflineTransaction(
+	ctx context.Context,
+	provider rpc.RPCProvider,
+	sender *felt.Felt,
+	calls []rpc.InvokeFunctionCall,
+) (signer.OfflineTransaction, error) {
+	chainID, err := providerChainID(ctx, provider)
+	if err != nil {
+		return signer.OfflineTransaction{}, err
+	}
+	nonce, err := provider.Nonce(ctx, rpc.WithBlockTag(rpc.BlockTagPreConfirmed), sender)
+	if err != nil {
+		return signer.OfflineTransaction{}, fmt.Errorf(
+			"cannot get the nonce of account %s: %w", sender, err,
+		)
+	}
+	tip, err := rpc.EstimateTip(ctx, provider, constants.TipMultiplier)
+	if err != nil {
+		return signer.OfflineTransaction{}, fmt.Errorf("failed to estimate tip: %w", err)
+	}
+	calldata := account.FmtCallDataCairo2(snUtils.InvokeFuncCallsToFunctionCalls(calls))
+	defaultResources := signerP.MakeDefaultResources()
+
+	// Taken from starknet.go `utils.BuildInvokeTxn`
+	txn := rpc.BroadcastInvokeTxnV3{
+		Type:                  rpc.TransactionTypeInvoke,
+		SenderAddress:         sender,
+		Calldata:              calldata,
+		Version:               rpc.TransactionV3,
+		Signature:             []*felt.Felt{},
+		Nonce:                 nonce,
+		ResourceBounds:        &defaultResources,
+		Tip:                   tip,
+		PayMasterData:         []*felt.Felt{},
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         rpc.DAModeL1,
+		FeeMode:               rpc.DAModeL1,
+	}
+
+	estimate, err := provider.EstimateFee(
+		ctx,
+		[]rpc.BroadcastTxn{txn},
+		[]rpc.SimulationFlag{rpc.SkipValidate},
+		rpc.WithBlockTag(rpc.BlockTagPreConfirmed),
+	)
+	if err != nil {
+		return signer.OfflineTransaction{}, fmt.Errorf("cannot estimate fee: %w", err)
+	}
+	l2Gas := estimate[0].L2GasConsumed
+	estimate[0].L2GasConsumed = new(felt.Felt).Add(
+		l2Gas, new(felt.Felt).SetUint64(offlineValidationL2Gas),
+	)
+	txn.ResourceBounds = snUtils.FeeEstToResBoundsMap(
+		estimate[0], constants.FeeEstimationMultiplier,
+	)
+
+	return signer.OfflineTransaction{ChainID: chainID, Transaction: &txn}, nil
+}
+
+// Sends the transaction signed offline and waits until it is accepted, within the
+// acceptance timeout. Its nonce has to be the next one of the account. Returns its hash
+func SubmitOfflineTransaction(
+	ctx context.Context,
+	provider rpc.RPCProvider,
+	txn *signer.OfflineTransaction,
+	acceptanceTimeout time.Duration,
+	logger *utils.ZapLogger,
+) (*felt.Felt, error) {
+	if !txn.Signed() {
+		return nil, errors.New("the transaction is not signed")
+	}
+
+	chainID, err := providerChainID(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+	if !chainID.Equal(txn.ChainID) {
+		return nil, fmt.Errorf(
+			"transaction built for chain %s, the provider is on chain %s",
+			snUtils.HexToShortStr(txn.ChainID.String()),
+			snUtils.HexToShortStr(chainID.String()),
+		)
+	}
+
+	sender := txn.Transaction.SenderAddress
+	nonce, err := provider.Nonce(ctx, rpc.WithBlockTag(rpc.BlockTagPreConfirmed), sender)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the nonce of account %s: %w", sender, err)
+	}
+	switch nonce.Cmp(txn.Transaction.Nonce) {
+	case 1:
+		return nil, fmt.Errorf(
+			"nonce %s was already used by account %s, the transaction has to be built again",
+			txn.Transaction.Nonce,
+			sender,
+		)
+	case -1:
+		return nil, fmt.Errorf(
+			"nonce %s is ahead of the nonce %s of account %s, the transactions before it have"+
+				" to be sent first",
+			txn.Transaction.Nonce,
+			nonce,
+			sender,
+		)
+	}
+
+	txHash, err := txn.Hash()
+	if err != nil {
+		return nil, fmt.Errorf("cannot compute the transaction hash: %w", err)
+	}
+	resp, err := provider.AddInvokeTransaction(ctx, txn.Transaction)
+	if err != nil {
+		return nil, fmt.Errorf("cannot send the transaction: %w", err)
+	}
+	if !resp.Hash.Equal(txHash) {
+		// The chain or version of the file doesn't match the signed transaction, whose
+		// signature is then invalid
+		return nil, fmt.Errorf(
+			"the provider answered transaction hash %s, the signed transaction is %s",
+			resp.Hash,
+			txHash,
+		)
+	}
+	logger.Infow("transaction sent", "transaction hash", txHash, "sender", sender)
+
+	transactionStatus := func(txHash *felt.Felt) (*rpc.TxnStatusResult, error) {
+		return provider.TransactionStatus(ctx, txHash)
+	}
+	err = waitForAcceptance(ctx, transactionStatus, txHash, acceptanceTimeout, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return txHash, nil
+}
+
+func providerChainID(ctx context.Context, provider rpc.RPCProvider) (*felt.Felt, error) {
+	chainID, err := provider.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the chain id: %w", err)
+	}
+
+	return new(felt.Felt).SetBytes([]byte(chainID)), nil
+}
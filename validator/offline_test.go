@@ -0,0 +1,220 @@
+package validator_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// Provider answering another hash than the one of the transactions sent
+type otherHashProvider struct {
+	rpc.RPCProvider
+}
+
+func (p otherHashProvider) AddInvokeTransaction(
+	ctx context.Context, txn *rpc.BroadcastInvokeTxnV3,
+) (rpc.AddInvokeTransactionResponse, error) {
+	resp, err := p.RPCProvider.AddInvokeTransaction(ctx, txn)
+	resp.Hash = new(felt.Felt).SetUint64(0xabc)
+
+	return resp, err
+}
+
+func TestOfflineTransaction(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+	validator.After = func(time.Duration) <-chan time.Time { return time.After(0) }
+	t.Cleanup(func() { validator.After = time.After })
+
+	sender := utils.HexToFelt(t, "0x123")
+	calls := []rpc.InvokeFunctionCall{{
+		ContractAddress: utils.HexToFelt(t, constants.SepoliaStakingContractAddress),
+		FunctionName:    "claim_rewards",
+		CallData:        []*felt.Felt{sender},
+	}}
+
+	// Answers the provider methods used by the offline transactions, with the account
+	// nonce given. Transactions sent are kept in `sent`.
+	newProvider := func(
+		t *testing.T, nonce string, sent *[]rpc.BroadcastInvokeTxnV3,
+	) rpc.RPCProvider {
+		t.Helper()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req struct {
+				ID     json.RawMessage   `json:"id"`
+				Method string            `json:"method"`
+				Params []json.RawMessage `json:"params"`
+			}
+			require.NoError(t, json.Unmarshal(body, &req))
+
+			var result any
+			switch req.Method {
+			case "starknet_specVersion":
+				result = "0.9.0"
+			case "starknet_chainId":
+				result = "0x534e5f5345504f4c4941"
+			case "starknet_getNonce":
+				result = nonce
+			case "starknet_getBlockWithTxs":
+				result = map[string]any{"block_hash": "0x1", "transactions": []any{}}
+			case "starknet_estimateFee":
+				// Unsigned transactions can only be estimated without validation
+				require.JSONEq(t, `["SKIP_VALIDATE"]`, string(req.Params[1]))
+				result = []map[string]string{{
+					"l1_gas_consumed":      "0x0",
+					"l1_gas_price":         "0x0",
+					"l2_gas_consumed":      "0x1000",
+					"l2_gas_price":         "0x10",
+					"l1_data_gas_consumed": "0x0",
+					"l1_data_gas_price":    "0x0",
+					"overall_fee":          "0x10000",
+					"unit":                 "FRI",
+				}}
+			case "starknet_addInvokeTransaction":
+				var txn rpc.BroadcastInvokeTxnV3
+				require.NoError(t, json.Unmarshal(req.Params[0], &txn))
+				*sent = append(*sent, txn)
+				txHash, err := (&signer.OfflineTransaction{
+					ChainID:     new(felt.Felt).SetBytes([]byte("SN_SEPOLIA")),
+					Transaction: &txn,
+				}).Hash()
+				require.NoError(t, err)
+				result = map[string]any{"transaction_hash": txHash}
+			case "starknet_getTransactionStatus":
+				result = map[string]string{
+					"finality_status":  "ACCEPTED_ON_L2",
+					"execution_status": "SUCCEEDED",
+				}
+			default:
+				require.Fail(t, "unexpected method "+req.Method)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+				"jsonrpc": "2.0", "id": req.ID, "result": result,
+			}))
+		}))
+		t.Cleanup(server.Close)
+
+		provider, err := validator.NewProvider(t.Context(), server.URL, logger)
+		require.NoError(t, err)
+
+		return provider
+	}
+
+	t.Run("Build, sign and submit a transaction", func(t *testing.T) {
+		var sent []rpc.BroadcastInvokeTxnV3
+		provider := newProvider(t, "0x5", &sent)
+
+		txn, err := validator.BuildOfflineTransaction(t.Context(), provider, sender, calls)
+		require.NoError(t, err)
+		require.Equal(t, new(felt.Felt).SetBytes([]byte("SN_SEPOLIA")), txn.ChainID)
+		require.Equal(t, new(felt.Felt).SetUint64(5), txn.Transaction.Nonce)
+		// The estimation plus 1M L2 gas for the account validation, with the estimation
+		// margin on top
+		require.Equal(
+			t, rpc.U64("0x16fb60"), txn.Transaction.ResourceBounds.L2Gas.MaxAmount,
+		)
+		require.False(t, txn.Signed())
+
+		txHash, err := txn.Sign(t.Context(), signer.NewMemoryKey(big.NewInt(0x123)))
+		require.NoError(t, err)
+
+		submittedHash, err := validator.SubmitOfflineTransaction(
+			t.Context(), provider, &txn, time.Minute, logger,
+		)
+		require.NoError(t, err)
+		require.Equal(t, txHash, submittedHash)
+		require.Len(t, sent, 1)
+		require.Equal(t, txn.Transaction.Signature, sent[0].Signature)
+	})
+
+	t.Run("Unsigned transactions are not submitted", func(t *testing.T) {
+		var sent []rpc.BroadcastInvokeTxnV3
+		provider := newProvider(t, "0x5", &sent)
+		txn, err := validator.BuildOfflineTransaction(t.Context(), provider, sender, calls)
+		require.NoError(t, err)
+
+		_, err = validator.SubmitOfflineTransaction(
+			t.Context(), provider, &txn, time.Minute, logger,
+		)
+		require.ErrorContains(t, err, "not signed")
+		require.Empty(t, sent)
+	})
+
+	t.Run("Transactions built for another chain are not submitted", func(t *testing.T) {
+		var sent []rpc.BroadcastInvokeTxnV3
+		provider := newProvider(t, "0x5", &sent)
+		txn, err := validator.BuildOfflineTransaction(t.Context(), provider, sender, calls)
+		require.NoError(t, err)
+		txn.ChainID = new(felt.Felt).SetBytes([]byte("SN_MAIN"))
+		_, err = txn.Sign(t.Context(), signer.NewMemoryKey(big.NewInt(0x123)))
+		require.NoError(t, err)
+
+		_, err = validator.SubmitOfflineTransaction(
+			t.Context(), provider, &txn, time.Minute, logger,
+		)
+		require.ErrorContains(t, err, "built for chain SN_MAIN")
+		require.Empty(t, sent)
+	})
+
+	t.Run("Transactions with a used nonce are not submitted", func(t *testing.T) {
+		var sent []rpc.BroadcastInvokeTxnV3
+		txn, err := validator.BuildOfflineTransaction(
+			t.Context(), newProvider(t, "0x5", &sent), sender, calls,
+		)
+		require.NoError(t, err)
+		_, err = txn.Sign(t.Context(), signer.NewMemoryKey(big.NewInt(0x123)))
+		require.NoError(t, err)
+
+		_, err = validator.SubmitOfflineTransaction(
+			t.Context(), newProvider(t, "0x6", &sent), &txn, time.Minute, logger,
+		)
+		require.ErrorContains(t, err, "has to be built again")
+		require.Empty(t, sent)
+	})
+	t.Run("Transactions with a future nonce are not submitted", func(t *testing.T) {
+		var sent []rpc.BroadcastInvokeTxnV3
+		txn, err := validator.BuildOfflineTransaction(
+			t.Context(), newProvider(t, "0x5", &sent), sender, calls,
+		)
+		require.NoError(t, err)
+		_, err = txn.Sign(t.Context(), signer.NewMemoryKey(big.NewInt(0x123)))
+		require.NoError(t, err)
+
+		_, err = validator.SubmitOfflineTransaction(
+			t.Context(), newProvider(t, "0x4", &sent), &txn, time.Minute, logger,
+		)
+		require.ErrorContains(t, err, "transactions before it have to be sent first")
+		require.Empty(t, sent)
+	})
+
+	t.Run("Transactions answered with another hash fail", func(t *testing.T) {
+		var sent []rpc.BroadcastInvokeTxnV3
+		provider := newProvider(t, "0x5", &sent)
+		txn, err := validator.BuildOfflineTransaction(t.Context(), provider, sender, calls)
+		require.NoError(t, err)
+		_, err = txn.Sign(t.Context(), signer.NewMemoryKey(big.NewInt(0x123)))
+		require.NoError(t, err)
+
+		_, err = validator.SubmitOfflineTransaction(
+			t.Context(), otherHashProvider{provider}, &txn, time.Minute, logger,
+		)
+		require.ErrorContains(t, err, "the provider answered transaction hash 0xabc")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/secret"
 	"github.com/NethermindEth/starknet.go/client"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/cockroachdb/errors"
@@ -17,10 +18,12 @@ func NewProvider[Logger utils.Logger](
 ) (*rpc.Provider, error) {
 	provider, err := rpc.NewProvider(ctx, providerURL)
 	if err != nil {
-		return nil, errors.Errorf("cannot create RPC provider at %s: %w", providerURL, err)
+		return nil, errors.Errorf(
+			"cannot create RPC provider at %s: %w", secret.RedactURL(providerURL), err,
+		)
 	}
 
-	logger.Infof("connected to RPC at %s", providerURL)
+	logger.Infof("connected to RPC at %s", secret.RedactURL(providerURL))
 
 	return provider, nil
 }
@@ -36,11 +39,15 @@ func SubscribeToBlockHeaders[Logger utils.Logger](
 	*client.ClientSubscription,
 	error,
 ) {
-	logger.Debugw("initialising websocket connection", "wsProviderUrl", wsProviderURL)
+	logger.Debugw(
+		"initialising websocket connection", "wsProviderUrl", secret.RedactURL(wsProviderURL),
+	)
 	// This needs a timeout or something
 	wsProvider, err := rpc.NewWebsocketProvider(ctx, wsProviderURL)
 	if err != nil {
-		return nil, nil, nil, errors.Errorf("dialling WS provider at %s: %w", wsProviderURL, err)
+		return nil, nil, nil, errors.Errorf(
+			"dialling WS provider at %s: %w", secret.RedactURL(wsProviderURL), err,
+		)
 	}
 
 	logger.Debugw("Subscribing to new block headers...")
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/secret"
 	"github.com/NethermindEth/starknet-staking-v2/validator"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -21,8 +22,10 @@ func TestNewProvider(t *testing.T) {
 		provider, err := validator.NewProvider(t.Context(), providerURL, logger)
 
 		require.Nil(t, provider)
-		expectedErrorMsg := "cannot create RPC provider at " + providerURL
+		// The url is redacted since it might hold the provider API key
+		expectedErrorMsg := "cannot create RPC provider at " + secret.Redacted
 		require.ErrorContains(t, err, expectedErrorMsg)
+		require.NotContains(t, err.Error(), providerURL)
 	})
 
 	envVars, err := validator.LoadEnv(t)
@@ -58,8 +61,10 @@ func TestBlockHeaderSubscription(t *testing.T) {
 		require.Nil(t, wsProvider)
 		require.Nil(t, headerFeed)
 		require.Nil(t, clientSubscription)
-		expectedErrorMsg := "dialling WS provider at " + wsProviderURL
+		// The url is redacted since it might hold the provider API key
+		expectedErrorMsg := "dialling WS provider at " + secret.Redacted
 		require.ErrorContains(t, err, expectedErrorMsg)
+		require.NotContains(t, err.Error(), wsProviderURL)
 	})
 
 	// Cannot test error when subscribing to new block headers
@@ -0,0 +1,137 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	junoUtils "github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/validator/config"
+	"github.com/NethermindEth/starknet-staking-v2/validator/metrics"
+	signerP "github.com/NethermindEth/starknet-staking-v2/validator/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// Time a claim of the staker rewards has to be accepted before it is considered failed
+const rewardsClaimAcceptanceTimeout = 10 * time.Minute
+
+// Claims the staker rewards once they reach the threshold, from the account of its
+// signer: the staker or its reward address
+type RewardsClaimer struct {
+	Signer signerP.Invoker
+	// Unclaimed rewards, in STRK, from which they are claimed
+	Threshold float64
+	// Time a claim has to be accepted before it is considered failed, and can be sent again
+	AcceptanceTimeout time.Duration
+	// Set while a claim is ongoing, so it isn't sent twice
+	claiming atomic.Bool
+}
+
+// Creates the claimer of the rewards configuration, signing through the provider of the
+// validator. Claiming has to be enabled by a threshold
+func (v *Validator) NewRewardsClaimer(
+	ctx context.Context, rewards *config.Rewards, snConfig *config.StarknetConfig,
+) (*RewardsClaimer, error) {
+	signer, err := signerP.New(
+		ctx, v.provider, &v.logger, &rewards.Claimer, &snConfig.ContractAddresses, false,
+	)
+	if err != nil {
+		return nil, err
+	}
+	invoker, ok := signer.(signerP.Invoker)
+	if !ok {
+		return nil, errors.New("the rewards claimer can only sign attestations")
+	}
+
+	//nolint:exhaustruct // No claim is ongoing
+	return &RewardsClaimer{
+		Signer:            invoker,
+		Threshold:         rewards.ClaimThreshold,
+		AcceptanceTimeout: rewardsClaimAcceptanceTimeout,
+	}, nil
+}
+
+// Reads the staker rewards, stake and commission from the staking contract, recording
+// them in the logs and metrics. With a claimer, claims the rewards once they reach its
+// threshold. The claim is given up once the context is done
+func CheckRewards[S signerP.Signer](
+	ctx context.Context,
+	signer S,
+	staker *types.Address,
+	claimer *RewardsClaimer,
+	logger *junoUtils.ZapLogger,
+	tracer metrics.Tracer,
+) {
+	stakerInfo, err := signerP.FetchStakerInfo(signer, staker)
+	if err != nil {
+		logger.Warnf("Unable to get the staking info of staker %s: %s", staker, err.Error())
+
+		return
+	}
+	unclaimedRewards := stakerInfo.UnclaimedRewards.Strk()
+	fields := []any{
+		"staker", staker,
+		"unclaimed rewards STRK", unclaimedRewards,
+		"stake STRK", stakerInfo.Stake.Strk(),
+	}
+	if stakerInfo.Pool != nil {
+		fields = append(
+			fields,
+			"delegated stake STRK", stakerInfo.Pool.Amount.Strk(),
+			//nolint:mnd // The commission is in hundredths of a percent
+			"commission %", float64(stakerInfo.Pool.Commission)/100,
+		)
+	}
+	logger.Infow("Staker rewards", fields...)
+	tracer.UpdateStakerInfo(&stakerInfo)
+
+	if claimer == nil || unclaimedRewards < claimer.Threshold {
+		return
+	}
+	if !claimer.claiming.CompareAndSwap(false, true) {
+		logger.Debug("a claim of the staker rewards is already ongoing")
+
+		return
+	}
+	defer claimer.claiming.Store(false)
+
+	logger.Infow(
+		"Claiming staker rewards",
+		"staker", staker,
+		"unclaimed rewards STRK", unclaimedRewards,
+		"threshold STRK", claimer.Threshold,
+	)
+	_, err = ClaimRewards(ctx, claimer.Signer, staker, claimer.AcceptanceTimeout, logger)
+	if err != nil {
+		logger.Errorw("failed to claim the staker rewards", "error", err.Error())
+
+		return
+	}
+	tracer.RecordRewardsClaimed()
+}
+
+// Claims the rewards of the staker, sent to its reward address. The signer account has
+// to be the staker or its reward address. Returns the hash of the transaction once it is
+// accepted, failing if it isn't within the acceptance timeout.
+func ClaimRewards[I signerP.Invoker](
+	ctx context.Context,
+	invoker I,
+	staker *types.Address,
+	acceptanceTimeout time.Duration,
+	logger *junoUtils.ZapLogger,
+) (*felt.Felt, error) {
+	txHash, err := invoke(ctx, invoker, []rpc.InvokeFunctionCall{{
+		ContractAddress: invoker.ValidationContracts().Staking.Felt(),
+		FunctionName:    "claim_rewards",
+		CallData:        []*felt.Felt{staker.Felt()},
+	}}, acceptanceTimeout, logger)
+	if err != nil {
+		return nil, err
+	}
+	logger.Infow("staker rewards claimed", "staker", staker, "transaction hash", txHash)
+
+	return txHash, nil
+}
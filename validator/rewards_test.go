@@ -0,0 +1,231 @@
+package validator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/mocks"
+	"github.com/NethermindEth/starknet-staking-v2/validator"
+	"github.com/NethermindEth/starknet-staking-v2/validator/metrics"
+	"github.com/NethermindEth/starknet-staking-v2/validator/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	snUtils "github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// Records the staker info and claims reported to the tracer
+type rewardsTracer struct {
+	metrics.NoOpMetrics
+
+	stakerInfo *types.StakerInfo
+	claims     int
+}
+
+func (t *rewardsTracer) UpdateStakerInfo(stakerInfo *types.StakerInfo) {
+	t.stakerInfo = stakerInfo
+}
+
+func (t *rewardsTracer) RecordRewardsClaimed() {
+	t.claims++
+}
+
+func TestCheckRewards(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+	validator.After = func(time.Duration) <-chan time.Time { return time.After(0) }
+	t.Cleanup(func() { validator.After = time.After })
+
+	staker := types.AddressFromString("0x123")
+	rewardAddress := utils.HexToFelt(t, "0x456")
+	operationalAddress := utils.HexToFelt(t, "0x789")
+	poolContract := utils.HexToFelt(t, "0xabc")
+	txHash := utils.HexToFelt(t, "0xdef")
+	contracts := validator.SepoliaValidationContracts(t)
+
+	strk := func(amount uint64) *felt.Felt {
+		return new(felt.Felt).Mul(
+			new(felt.Felt).SetUint64(amount), new(felt.Felt).SetUint64(1_000_000_000_000_000_000),
+		)
+	}
+	none := new(felt.Felt).SetUint64(1)
+	some := new(felt.Felt)
+
+	newInvoker := func(t *testing.T) *mocks.MockInvoker {
+		t.Helper()
+
+		mockCtrl := gomock.NewController(t)
+		invoker := mocks.NewMockInvoker(mockCtrl)
+		invoker.EXPECT().ValidationContracts().Return(contracts).AnyTimes()
+
+		return invoker
+	}
+
+	// Expects the staker info to be read, answering with the result
+	expectStakerInfo := func(invoker *mocks.MockInvoker, result []*felt.Felt) {
+		invoker.EXPECT().
+			Call(rpc.FunctionCall{
+				ContractAddress:    contracts.Staking.Felt(),
+				EntryPointSelector: snUtils.GetSelectorFromNameFelt("staker_info_v1"),
+				Calldata:           []*felt.Felt{staker.Felt()},
+			}, rpc.WithBlockTag(rpc.BlockTagLatest)).
+			Return(result, nil)
+	}
+
+	// Expects the rewards of the staker to be claimed, without the transaction status
+	expectClaim := func(invoker *mocks.MockInvoker) {
+		invoker.EXPECT().
+			BuildInvokeTransaction([]rpc.InvokeFunctionCall{{
+				ContractAddress: contracts.Staking.Felt(),
+				FunctionName:    "claim_rewards",
+				CallData:        []*felt.Felt{staker.Felt()},
+			}}).
+			Return(rpc.BroadcastInvokeTxnV3{Version: rpc.TransactionV3}, nil)
+		invoker.EXPECT().SignInvokeTransaction(gomock.Any()).Times(2)
+		invoker.EXPECT().EstimateFee(gomock.Any()).Return(rpc.FeeEstimation{
+			FeeEstimationCommon: rpc.FeeEstimationCommon{
+				L1GasConsumed:     new(felt.Felt),
+				L1GasPrice:        new(felt.Felt),
+				L2GasConsumed:     new(felt.Felt).SetUint64(0x1000),
+				L2GasPrice:        new(felt.Felt).SetUint64(0x10),
+				L1DataGasConsumed: new(felt.Felt),
+				L1DataGasPrice:    new(felt.Felt),
+			},
+		}, nil)
+		invoker.EXPECT().
+			InvokeTransaction(gomock.Any()).
+			Return(rpc.AddInvokeTransactionResponse{Hash: txHash}, nil)
+	}
+	accepted := &rpc.TxnStatusResult{
+		FinalityStatus:  rpc.TxnStatusAcceptedOnL2,
+		ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+	}
+
+	t.Run("Staker info is recorded without a claimer", func(t *testing.T) {
+		invoker := newInvoker(t)
+		expectStakerInfo(invoker, []*felt.Felt{
+			rewardAddress, operationalAddress, none, strk(1000), strk(50), none,
+		})
+		tracer := &rewardsTracer{}
+
+		validator.CheckRewards(t.Context(), invoker, &staker, nil, logger, tracer)
+
+		require.NotNil(t, tracer.stakerInfo)
+		require.Equal(t, types.Address(*rewardAddress), tracer.stakerInfo.RewardAddress)
+		require.Equal(t, types.Address(*operationalAddress), tracer.stakerInfo.OperationalAddress)
+		require.Equal(t, 1000.0, tracer.stakerInfo.Stake.Strk())
+		require.Equal(t, 50.0, tracer.stakerInfo.UnclaimedRewards.Strk())
+		require.Nil(t, tracer.stakerInfo.Pool)
+		require.Zero(t, tracer.claims)
+	})
+
+	t.Run("Staker info with an unstake time and a pool", func(t *testing.T) {
+		invoker := newInvoker(t)
+		expectStakerInfo(invoker, []*felt.Felt{
+			rewardAddress,
+			operationalAddress,
+			some,
+			new(felt.Felt).SetUint64(1_700_000_000),
+			strk(1000),
+			strk(5),
+			some,
+			poolContract,
+			strk(200),
+			new(felt.Felt).SetUint64(1000),
+		})
+		tracer := &rewardsTracer{}
+
+		validator.CheckRewards(t.Context(), invoker, &staker, nil, logger, tracer)
+
+		require.NotNil(t, tracer.stakerInfo)
+		require.Equal(t, 5.0, tracer.stakerInfo.UnclaimedRewards.Strk())
+		require.Equal(t, &types.PoolInfo{
+			Contract:   types.Address(*poolContract),
+			Amount:     types.NewBalance(strk(200), new(felt.Felt)),
+			Commission: 1000,
+		}, tracer.stakerInfo.Pool)
+	})
+
+	t.Run("Invalid staker info is not recorded", func(t *testing.T) {
+		invoker := newInvoker(t)
+		expectStakerInfo(invoker, []*felt.Felt{rewardAddress, operationalAddress, none})
+		tracer := &rewardsTracer{}
+
+		validator.CheckRewards(t.Context(), invoker, &staker, nil, logger, tracer)
+
+		require.Nil(t, tracer.stakerInfo)
+	})
+
+	t.Run("Rewards below the threshold are not claimed", func(t *testing.T) {
+		invoker := newInvoker(t)
+		expectStakerInfo(invoker, []*felt.Felt{
+			rewardAddress, operationalAddress, none, strk(1000), strk(5), none,
+		})
+		tracer := &rewardsTracer{}
+		//nolint:exhaustruct // No claim is ongoing
+		claimer := &validator.RewardsClaimer{Signer: newInvoker(t), Threshold: 10}
+
+		validator.CheckRewards(t.Context(), invoker, &staker, claimer, logger, tracer)
+
+		require.NotNil(t, tracer.stakerInfo)
+		require.Zero(t, tracer.claims)
+	})
+
+	t.Run("Rewards reaching the threshold are claimed", func(t *testing.T) {
+		invoker := newInvoker(t)
+		expectStakerInfo(invoker, []*felt.Felt{
+			rewardAddress, operationalAddress, none, strk(1000), strk(10), none,
+		})
+		claimerInvoker := newInvoker(t)
+		claimerInvoker.EXPECT().Address().Return(&staker).AnyTimes()
+		expectClaim(claimerInvoker)
+		claimerInvoker.EXPECT().TransactionStatus(txHash).Return(accepted, nil)
+		tracer := &rewardsTracer{}
+		//nolint:exhaustruct // No claim is ongoing
+		claimer := &validator.RewardsClaimer{
+			Signer: claimerInvoker, Threshold: 10, AcceptanceTimeout: time.Minute,
+		}
+
+		validator.CheckRewards(t.Context(), invoker, &staker, claimer, logger, tracer)
+
+		require.Equal(t, 1, tracer.claims)
+	})
+
+	t.Run("Claim not accepted within the timeout is sent again", func(t *testing.T) {
+		invoker := newInvoker(t)
+		stakerInfo := []*felt.Felt{
+			rewardAddress, operationalAddress, none, strk(1000), strk(10), none,
+		}
+		expectStakerInfo(invoker, stakerInfo)
+		expectStakerInfo(invoker, stakerInfo)
+		claimerInvoker := newInvoker(t)
+		claimerInvoker.EXPECT().Address().Return(&staker).AnyTimes()
+		expectClaim(claimerInvoker)
+		expectClaim(claimerInvoker)
+		// The first claim is never found, the second one is accepted
+		claimed := false
+		claimerInvoker.EXPECT().
+			TransactionStatus(txHash).
+			DoAndReturn(func(*felt.Felt) (*rpc.TxnStatusResult, error) {
+				if !claimed {
+					return nil, validator.ErrTxnHashNotFound
+				}
+
+				return accepted, nil
+			}).
+			AnyTimes()
+		tracer := &rewardsTracer{}
+		//nolint:exhaustruct // No claim is ongoing
+		claimer := &validator.RewardsClaimer{
+			Signer: claimerInvoker, Threshold: 10, AcceptanceTimeout: 50 * time.Millisecond,
+		}
+
+		validator.CheckRewards(t.Context(), invoker, &staker, claimer, logger, tracer)
+		require.Zero(t, tracer.claims)
+
+		claimed = true
+		validator.CheckRewards(t.Context(), invoker, &staker, claimer, logger, tracer)
+		require.Equal(t, 1, tracer.claims)
+	})
+}
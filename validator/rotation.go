@@ -0,0 +1,292 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	signerP "github.com/NethermindEth/starknet-staking-v2/validator/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	snUtils "github.com/NethermindEth/starknet.go/utils"
+)
+
+// Time waited between checks of whether the staker attested in the current epoch
+const attestationDoneInterval = 30 * time.Second
+
+func (v *Validator) invoker() (signerP.Invoker, error) {
+	invoker, ok := v.signer.(signerP.Invoker)
+	if !ok {
+		return nil, errors.New("the signer can only sign attestations")
+	}
+
+	return invoker, nil
+}
+
+// Declares the signer account as operational account of the staker, see
+// `DeclareOperationalAddress`
+func (v *Validator) DeclareOperationalAddress(
+	ctx context.Context, staker *types.Address, acceptanceTimeout time.Duration,
+) (*felt.Felt, error) {
+	invoker, err := v.invoker()
+	if err != nil {
+		return nil, err
+	}
+
+	return DeclareOperationalAddress(ctx, invoker, staker, acceptanceTimeout, &v.logger)
+}
+
+// Declares the signer account as operational account of the staker, which lets the
+// staker change its operational address to it. Returns the hash of the transaction once
+// it is accepted, failing if it isn't within the acceptance timeout.
+func DeclareOperationalAddress[I signerP.Invoker](
+	ctx context.Context,
+	invoker I,
+	staker *types.Address,
+	acceptanceTimeout time.Duration,
+	logger *utils.ZapLogger,
+) (*felt.Felt, error) {
+	return invoke(ctx, invoker, []rpc.InvokeFunctionCall{{
+		ContractAddress: invoker.ValidationContracts().Staking.Felt(),
+		FunctionName:    "declare_operational_address",
+		CallData:        []*felt.Felt{staker.Felt()},
+	}}, acceptanceTimeout, logger)
+}
+
+// Changes the operational address of the staker, the signer account, see
+// `ChangeOperationalAddress`
+func (v *Validator) ChangeOperationalAddress(
+	ctx context.Context,
+	operationalAddress *types.Address,
+	waitForAttestation bool,
+	acceptanceTimeout time.Duration,
+) (*felt.Felt, error) {
+	invoker, err := v.invoker()
+	if err != nil {
+		return nil, err
+	}
+
+	return ChangeOperationalAddress(
+		ctx, invoker, operationalAddress, waitForAttestation, acceptanceTimeout, &v.logger,
+	)
+}
+
+// Changes the operational address of the staker, the signer account, to one that
+// declared it. If `waitForAttestation` is set, the change waits until the staker attested
+// in the current epoch, so the validator of the new address only attests from the next
+// one. The wait is given up once the next epoch is over, or the context is done. Returns
+// the hash of the transaction once the staking contract resolves the new address to the
+// staker, failing if it isn't accepted within the acceptance timeout.
+func ChangeOperationalAddress[I signerP.Invoker](
+	ctx context.Context,
+	invoker I,
+	operationalAddress *types.Address,
+	waitForAttestation bool,
+	acceptanceTimeout time.Duration,
+	logger *utils.ZapLogger,
+) (*felt.Felt, error) {
+	staker := invoker.Address()
+	if waitForAttestation {
+		if err := waitForEpochAttestation(ctx, invoker, staker, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	txHash, err := invoke(ctx, invoker, []rpc.InvokeFunctionCall{{
+		ContractAddress: invoker.ValidationContracts().Staking.Felt(),
+		FunctionName:    "change_operational_address",
+		CallData:        []*felt.Felt{operationalAddress.Felt()},
+	}}, acceptanceTimeout, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	operational := operationalView{Signer: invoker, address: operationalAddress}
+	epochInfo, err := VerifyOperationalAddress(operational, staker)
+	if err != nil {
+		return nil, err
+	}
+	logger.Infow(
+		"operational address changed",
+		"staker", staker,
+		"operational address", operationalAddress,
+		"epoch", epochInfo.EpochID,
+	)
+
+	return txHash, nil
+}
+
+// Checks the staking contract resolves the signer account, as operational address, to
+// the staker
+func (v *Validator) VerifyOperationalAddress(staker *types.Address) (types.EpochInfo, error) {
+	return VerifyOperationalAddress(v.signer, staker)
+}
+
+// Checks the staking contract resolves the signer address, as operational address, to
+// the staker. Returns the current epoch of the staker.
+func VerifyOperationalAddress[S signerP.Signer](
+	signer S, staker *types.Address,
+) (types.EpochInfo, error) {
+	epochInfo, err := signerP.FetchEpochInfo(signer)
+	if err != nil {
+		return types.EpochInfo{}, fmt.Errorf(
+			"operational address %s is not resolved by the staking contract: %w",
+			signer.Address(),
+			err,
+		)
+	}
+	if epochInfo.StakerAddress != *staker {
+		return types.EpochInfo{}, fmt.Errorf(
+			"operational address %s belongs to staker %s, not to %s",
+			signer.Address(),
+			&epochInfo.StakerAddress,
+			staker,
+		)
+	}
+
+	return epochInfo, nil
+}
+
+// Signer reading the staking contract state of another operational address
+type operationalView struct {
+	signerP.Signer
+	address *types.Address
+}
+
+func (o operationalView) Address() *types.Address {
+	return o.address
+}
+
+// Waits until the staker attested in the current epoch. If it hasn't by the end of the
+// next epoch, its validator isn't attesting and an error is returned
+func waitForEpochAttestation[S signerP.Signer](
+	ctx context.Context, signer S, staker *types.Address, logger *utils.ZapLogger,
+) error {
+	stakerInfo, err := signerP.FetchStakerInfo(signer, staker)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot read the current operational address of staker %s: %w", staker, err,
+		)
+	}
+	// The epoch is read through the operational address currently attesting for the staker
+	operational := operationalView{Signer: signer, address: &stakerInfo.OperationalAddress}
+	epochInfo, err := signerP.FetchEpochInfo(operational)
+	if err != nil {
+		return fmt.Errorf("cannot read the current epoch of staker %s: %w", staker, err)
+	}
+	lastEpoch := epochInfo.EpochID + 1
+
+	for {
+		result, err := signer.Call(
+			rpc.FunctionCall{
+				ContractAddress: signer.ValidationContracts().Attest.Felt(),
+				EntryPointSelector: snUtils.GetSelectorFromNameFelt(
+					"is_attestation_done_in_curr_epoch",
+				),
+				Calldata: []*felt.Felt{staker.Felt()},
+			},
+			rpc.WithBlockTag(rpc.BlockTagLatest),
+		)
+		if err != nil {
+			return fmt.Errorf(
+				"cannot check if staker %s attested in the current epoch: %w", staker, err,
+			)
+		}
+		if len(result) != 1 {
+			return fmt.Errorf(
+				"unexpected is_attestation_done_in_curr_epoch response: %s", result,
+			)
+		}
+		if !result[0].IsZero() {
+			logger.Infow("staker attested in the current epoch", "staker", staker)
+
+			return nil
+		}
+
+		epochInfo, err := signerP.FetchEpochInfo(operational)
+		if err != nil {
+			return fmt.Errorf("cannot read the current epoch of staker %s: %w", staker, err)
+		}
+		if epochInfo.EpochID > lastEpoch {
+			return fmt.Errorf(
+				"staker %s didn't attest by the end of epoch %d, check its validator is"+
+					" running or change the operational address without waiting",
+				staker,
+				lastEpoch,
+			)
+		}
+
+		logger.Infow(
+			"waiting for the staker to attest in the current epoch before changing its"+
+				" operational address",
+			"staker", staker,
+		)
+		if err := sleep(ctx, attestationDoneInterval); err != nil {
+			return fmt.Errorf("stopped waiting for staker %s to attest: %w", staker, err)
+		}
+	}
+}
+
+// Sends the calls from the signer account and waits until they are accepted, within the
+// acceptance timeout. Returns the transaction hash
+func invoke[I signerP.Invoker](
+	ctx context.Context,
+	invoker I,
+	calls []rpc.InvokeFunctionCall,
+	acceptanceTimeout time.Duration,
+	logger *utils.ZapLogger,
+) (*felt.Felt, error) {
+	txHash, err := sendInvoke(invoker, calls, logger)
+	if err != nil {
+		return nil, err
+	}
+	err = waitForAcceptance(ctx, invoker.TransactionStatus, txHash, acceptanceTimeout, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return txHash, nil
+}
+
+// Sends the calls from the signer account, without waiting for them to be accepted.
+// Returns the transaction hash
+func sendInvoke[I signerP.Invoker](
+	invoker I, calls []rpc.InvokeFunctionCall, logger *utils.ZapLogger,
+) (*felt.Felt, error) {
+	txn, err := invoker.BuildInvokeTransaction(calls)
+	if err != nil {
+		return nil, err
+	}
+	// Estimated with the query bit, so the signing history of an external signer only
+	// records the transaction sent
+	txn.Version = rpc.TransactionV3WithQueryBit
+	if err := invoker.SignInvokeTransaction(&txn); err != nil {
+		return nil, err
+	}
+	estimate, err := invoker.EstimateFee(&txn)
+	if err != nil {
+		return nil, fmt.Errorf("cannot estimate fee: %w", err)
+	}
+	txn.ResourceBounds = snUtils.FeeEstToResBoundsMap(estimate, constants.FeeEstimationMultiplier)
+	txn.Version = rpc.TransactionV3
+	if err := invoker.SignInvokeTransaction(&txn); err != nil {
+		return nil, err
+	}
+
+	resp, err := invoker.InvokeTransaction(&txn)
+	if err != nil {
+		return nil, fmt.Errorf("cannot send the transaction: %w", err)
+	}
+	logger.Infow(
+		"transaction sent",
+		"transaction hash", resp.Hash,
+		"sender", invoker.Address(),
+		"function", calls[0].FunctionName,
+	)
+
+	return resp.Hash, nil
+}
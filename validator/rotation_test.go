@@ -0,0 +1,211 @@
+package validator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/mocks"
+	"github.com/NethermindEth/starknet-staking-v2/validator"
+	"github.com/NethermindEth/starknet-staking-v2/validator/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	snUtils "github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOperationalAddressRotation(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+	validator.After = func(time.Duration) <-chan time.Time { return time.After(0) }
+	t.Cleanup(func() { validator.After = time.After })
+
+	staker := types.AddressFromString("0x123")
+	operationalAddress := types.AddressFromString("0x456")
+	txHash := utils.HexToFelt(t, "0xabc")
+	contracts := validator.SepoliaValidationContracts(t)
+
+	attestationDone := snUtils.GetSelectorFromNameFelt("is_attestation_done_in_curr_epoch")
+	attestationInfo := snUtils.GetSelectorFromNameFelt(
+		"get_attestation_info_by_operational_address",
+	)
+
+	newInvoker := func(t *testing.T, address *types.Address) *mocks.MockInvoker {
+		t.Helper()
+
+		mockCtrl := gomock.NewController(t)
+		invoker := mocks.NewMockInvoker(mockCtrl)
+		invoker.EXPECT().Address().Return(address).AnyTimes()
+		invoker.EXPECT().ValidationContracts().Return(contracts).AnyTimes()
+
+		return invoker
+	}
+
+	// Expects a transaction with the call to be built, estimated, signed, sent and accepted
+	expectInvoke := func(invoker *mocks.MockInvoker, function string, calldata *felt.Felt) {
+		invoker.EXPECT().
+			BuildInvokeTransaction([]rpc.InvokeFunctionCall{{
+				ContractAddress: contracts.Staking.Felt(),
+				FunctionName:    function,
+				CallData:        []*felt.Felt{calldata},
+			}}).
+			Return(rpc.BroadcastInvokeTxnV3{Version: rpc.TransactionV3}, nil)
+		invoker.EXPECT().SignInvokeTransaction(gomock.Any()).Times(2)
+		invoker.EXPECT().EstimateFee(gomock.Any()).Return(rpc.FeeEstimation{
+			FeeEstimationCommon: rpc.FeeEstimationCommon{
+				L1GasConsumed:     new(felt.Felt),
+				L1GasPrice:        new(felt.Felt),
+				L2GasConsumed:     new(felt.Felt).SetUint64(0x1000),
+				L2GasPrice:        new(felt.Felt).SetUint64(0x10),
+				L1DataGasConsumed: new(felt.Felt),
+				L1DataGasPrice:    new(felt.Felt),
+			},
+		}, nil)
+		invoker.EXPECT().
+			InvokeTransaction(gomock.Any()).
+			Return(rpc.AddInvokeTransactionResponse{Hash: txHash}, nil)
+		invoker.EXPECT().TransactionStatus(txHash).Return(&rpc.TxnStatusResult{
+			FinalityStatus:  rpc.TxnStatusAcceptedOnL2,
+			ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+		}, nil)
+	}
+
+	// Expects the attestation info of the operational address in the epoch, resolved to
+	// the staker
+	expectEpochInfo := func(
+		invoker *mocks.MockInvoker,
+		operational, resolvedStaker *types.Address,
+		epochID uint64,
+	) *gomock.Call {
+		return invoker.EXPECT().
+			Call(rpc.FunctionCall{
+				ContractAddress:    contracts.Staking.Felt(),
+				EntryPointSelector: attestationInfo,
+				Calldata:           []*felt.Felt{operational.Felt()},
+			}, gomock.Any()).
+			Return([]*felt.Felt{
+				resolvedStaker.Felt(),
+				new(felt.Felt).SetUint64(1e18),
+				new(felt.Felt).SetUint64(100),
+				new(felt.Felt).SetUint64(epochID),
+				new(felt.Felt).SetUint64(epochID * 100),
+			}, nil)
+	}
+	expectAttestationInfo := func(invoker *mocks.MockInvoker, resolvedStaker *types.Address) {
+		expectEpochInfo(invoker, &operationalAddress, resolvedStaker, 7)
+	}
+
+	// Expects the staker info, with the operational address currently attesting for it
+	oldOperationalAddress := types.AddressFromString("0x789")
+	expectStakerInfo := func(invoker *mocks.MockInvoker) {
+		none := new(felt.Felt).SetUint64(1)
+		invoker.EXPECT().
+			Call(rpc.FunctionCall{
+				ContractAddress:    contracts.Staking.Felt(),
+				EntryPointSelector: snUtils.GetSelectorFromNameFelt("staker_info_v1"),
+				Calldata:           []*felt.Felt{staker.Felt()},
+			}, gomock.Any()).
+			Return([]*felt.Felt{
+				staker.Felt(),
+				oldOperationalAddress.Felt(),
+				none,
+				new(felt.Felt).SetUint64(1e18),
+				new(felt.Felt),
+				none,
+			}, nil)
+	}
+
+	attestationDoneCall := rpc.FunctionCall{
+		ContractAddress:    contracts.Attest.Felt(),
+		EntryPointSelector: attestationDone,
+		Calldata:           []*felt.Felt{staker.Felt()},
+	}
+	notDone := []*felt.Felt{new(felt.Felt)}
+
+	t.Run("Declare the operational address", func(t *testing.T) {
+		invoker := newInvoker(t, &operationalAddress)
+		expectInvoke(invoker, "declare_operational_address", staker.Felt())
+
+		declaredBy, err := validator.DeclareOperationalAddress(
+			t.Context(), invoker, &staker, time.Minute, logger,
+		)
+		require.NoError(t, err)
+		require.Equal(t, txHash, declaredBy)
+	})
+
+	t.Run("Change the operational address after the staker attested", func(t *testing.T) {
+		invoker := newInvoker(t, &staker)
+		expectStakerInfo(invoker)
+		expectEpochInfo(invoker, &oldOperationalAddress, &staker, 7).Times(2)
+		gomock.InOrder(
+			invoker.EXPECT().Call(attestationDoneCall, gomock.Any()).Return(notDone, nil),
+			invoker.EXPECT().Call(attestationDoneCall, gomock.Any()).Return(
+				[]*felt.Felt{new(felt.Felt).SetUint64(1)}, nil,
+			),
+		)
+		expectInvoke(invoker, "change_operational_address", operationalAddress.Felt())
+		expectAttestationInfo(invoker, &staker)
+
+		changedBy, err := validator.ChangeOperationalAddress(
+			t.Context(), invoker, &operationalAddress, true, time.Minute, logger,
+		)
+		require.NoError(t, err)
+		require.Equal(t, txHash, changedBy)
+	})
+
+	t.Run("Staker not attesting by the end of the next epoch", func(t *testing.T) {
+		invoker := newInvoker(t, &staker)
+		expectStakerInfo(invoker)
+		invoker.EXPECT().Call(attestationDoneCall, gomock.Any()).Return(notDone, nil).Times(2)
+		gomock.InOrder(
+			expectEpochInfo(invoker, &oldOperationalAddress, &staker, 7),
+			expectEpochInfo(invoker, &oldOperationalAddress, &staker, 8),
+			expectEpochInfo(invoker, &oldOperationalAddress, &staker, 9),
+		)
+
+		_, err := validator.ChangeOperationalAddress(
+			t.Context(), invoker, &operationalAddress, true, time.Minute, logger,
+		)
+		require.ErrorContains(t, err, "didn't attest by the end of epoch 8")
+	})
+
+	t.Run("Waiting for the attestation is cancelled", func(t *testing.T) {
+		invoker := newInvoker(t, &staker)
+		expectStakerInfo(invoker)
+		expectEpochInfo(invoker, &oldOperationalAddress, &staker, 7).Times(2)
+		invoker.EXPECT().Call(attestationDoneCall, gomock.Any()).Return(notDone, nil)
+		// The wait only ends by the cancellation
+		validator.After = func(time.Duration) <-chan time.Time { return nil }
+		t.Cleanup(func() {
+			validator.After = func(time.Duration) <-chan time.Time { return time.After(0) }
+		})
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		_, err := validator.ChangeOperationalAddress(
+			ctx, invoker, &operationalAddress, true, time.Minute, logger,
+		)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Change the operational address without waiting", func(t *testing.T) {
+		invoker := newInvoker(t, &staker)
+		expectInvoke(invoker, "change_operational_address", operationalAddress.Felt())
+		expectAttestationInfo(invoker, &staker)
+
+		_, err := validator.ChangeOperationalAddress(
+			t.Context(), invoker, &operationalAddress, false, time.Minute, logger,
+		)
+		require.NoError(t, err)
+	})
+
+	t.Run("Operational address of another staker", func(t *testing.T) {
+		invoker := newInvoker(t, &operationalAddress)
+		otherStaker := types.AddressFromString("0x789")
+		expectAttestationInfo(invoker, &otherStaker)
+
+		_, err := validator.VerifyOperationalAddress(invoker, &staker)
+		require.ErrorContains(t, err, "belongs to staker 0x789")
+	})
+}
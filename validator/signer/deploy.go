@@ -0,0 +1,220 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/config"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	"github.com/NethermindEth/starknet-staking-v2/validator/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// Whether a contract is deployed at the address
+func isDeployed(
+	ctx context.Context, provider rpc.RPCProvider, address *felt.Felt,
+) (bool, error) {
+	_, err := provider.ClassHashAt(ctx, rpc.WithBlockTag(rpc.BlockTagPreConfirmed), address)
+	if err != nil {
+		var rpcErr *rpc.RPCError
+		if errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrContractNotFound.Code {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func buildDeployAccountTransaction(
+	ctx context.Context,
+	provider rpc.RPCProvider,
+	classHash *felt.Felt,
+	salt *felt.Felt,
+	constructorCalldata []*felt.Felt,
+) (rpc.BroadcastDeployAccountTxnV3, error) {
+	tip, err := rpc.EstimateTip(ctx, provider, constants.TipMultiplier)
+	if err != nil {
+		return rpc.BroadcastDeployAccountTxnV3{}, fmt.Errorf("failed to estimate tip: %w", err)
+	}
+	defaultResources := MakeDefaultResources()
+
+	// Taken from starknet.go `utils.BuildDeployAccountTxn`
+	return rpc.BroadcastDeployAccountTxnV3{
+		Type:                rpc.TransactionTypeDeployAccount,
+		Version:             rpc.TransactionV3,
+		Signature:           []*felt.Felt{},
+		Nonce:               new(felt.Felt),
+		ContractAddressSalt: salt,
+		ConstructorCalldata: constructorCalldata,
+		ClassHash:           classHash,
+		ResourceBounds:      &defaultResources,
+		Tip:                 tip,
+		PayMasterData:       []*felt.Felt{},
+		NonceDataMode:       rpc.DAModeL1,
+		FeeMode:             rpc.DAModeL1,
+	}, nil
+}
+
+func estimateDeployAccountFee(
+	ctx context.Context, provider rpc.RPCProvider, txn *rpc.BroadcastDeployAccountTxnV3,
+) (rpc.FeeEstimation, error) {
+	estimateFee, err := provider.EstimateFee(
+		ctx,
+		[]rpc.BroadcastTxn{txn},
+		[]rpc.SimulationFlag{},
+		rpc.WithBlockTag(rpc.BlockTagPreConfirmed),
+	)
+	if err != nil {
+		return rpc.FeeEstimation{}, err
+	}
+
+	return estimateFee[0], nil
+}
+
+func (s *InternalSigner) AccountDeployed() (bool, error) {
+	return isDeployed(s.ctx, s.Account.Provider, s.Account.Address)
+}
+
+func (s *InternalSigner) BuildDeployAccountTransaction(
+	classHash, salt *felt.Felt, constructorCalldata []*felt.Felt,
+) (rpc.BroadcastDeployAccountTxnV3, error) {
+	return buildDeployAccountTransaction(
+		s.ctx, s.Account.Provider, classHash, salt, constructorCalldata,
+	)
+}
+
+func (s *InternalSigner) EstimateDeployAccountFee(
+	txn *rpc.BroadcastDeployAccountTxnV3,
+) (rpc.FeeEstimation, error) {
+	return estimateDeployAccountFee(s.ctx, s.Account.Provider, txn)
+}
+
+func (s *InternalSigner) SignDeployAccountTransaction(
+	txn *rpc.BroadcastDeployAccountTxnV3,
+) error {
+	return s.Account.SignDeployAccountTransaction(s.ctx, txn, s.Account.Address)
+}
+
+func (s *InternalSigner) DeployAccountTransaction(
+	txn *rpc.BroadcastDeployAccountTxnV3,
+) (rpc.AddDeployAccountTransactionResponse, error) {
+	return s.Account.Provider.AddDeployAccountTransaction(s.ctx, txn)
+}
+
+func (s *InternalSigner) AccountPublicKey() (*felt.Felt, error) {
+	return s.publicKey, nil
+}
+
+func (s *ExternalSigner) AccountDeployed() (bool, error) {
+	return isDeployed(s.ctx, s.Provider, s.Address().Felt())
+}
+
+func (s *ExternalSigner) BuildDeployAccountTransaction(
+	classHash, salt *felt.Felt, constructorCalldata []*felt.Felt,
+) (rpc.BroadcastDeployAccountTxnV3, error) {
+	return buildDeployAccountTransaction(s.ctx, s.Provider, classHash, salt, constructorCalldata)
+}
+
+func (s *ExternalSigner) EstimateDeployAccountFee(
+	txn *rpc.BroadcastDeployAccountTxnV3,
+) (rpc.FeeEstimation, error) {
+	return estimateDeployAccountFee(s.ctx, s.Provider, txn)
+}
+
+func (s *ExternalSigner) DeployAccountTransaction(
+	txn *rpc.BroadcastDeployAccountTxnV3,
+) (rpc.AddDeployAccountTransactionResponse, error) {
+	return s.Provider.AddDeployAccountTransaction(s.ctx, txn)
+}
+
+// Signs the deploy account transaction of the operational account with the first
+// external signer able to
+func (s *ExternalSigner) SignDeployAccountTransaction(
+	txn *rpc.BroadcastDeployAccountTxnV3,
+) error {
+	signResp, err := s.sign(func(client *ExternalClient) (signer.Response, error) {
+		return client.HashAndSignDeployAccountTx(txn, &s.chainID)
+	})
+	if err != nil {
+		return err
+	}
+	txn.Signature = signResp.Signature
+
+	return nil
+}
+
+// Public key signatures of the external signers are verified against, either configured
+// or read with `ResolveExternalPublicKey`
+func (s *ExternalSigner) AccountPublicKey() (*felt.Felt, error) {
+	endpoint := s.signers.endpoints[0]
+	endpoint.mu.Lock()
+	defer endpoint.mu.Unlock()
+
+	publicKey, err := endpoint.client.accountPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	if publicKey == nil {
+		return nil, errors.New("the public key of the operational account is unknown")
+	}
+
+	return publicKey, nil
+}
+
+// Sets the public key of the signer configuration, if missing, to the single key the
+// external signers hold for the operational account. Used when the account isn't
+// deployed yet, so its public key can't be read from it.
+func ResolveExternalPublicKey(sig *config.Signer) error {
+	if sig.PublicKey != "" {
+		return nil
+	}
+
+	operationalAddress := types.AddressFromString(sig.OperationalAddress)
+	var errs []error
+	for _, url := range sig.SignerURLs() {
+		publicKey, err := externalAccountKey(url, sig, &operationalAddress)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("external signer %s: %w", url, err))
+
+			continue
+		}
+		sig.PublicKey = publicKey.String()
+
+		return nil
+	}
+
+	return fmt.Errorf("cannot read the operational account public key: %w", errors.Join(errs...))
+}
+
+func externalAccountKey(
+	url string, sig *config.Signer, operationalAddress *types.Address,
+) (*felt.Felt, error) {
+	client, err := externalClientFromConfig(url, sig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = client.Close() }()
+
+	publicKeys, err := client.PublicKeys()
+	if err != nil {
+		return nil, err
+	}
+	accountKeys := publicKeys.For(operationalAddress.Felt())
+	if accountKeys == nil {
+		return nil, fmt.Errorf("no key for operational account %s", operationalAddress)
+	}
+	if len(accountKeys.PublicKeys) != 1 {
+		return nil, fmt.Errorf(
+			"%d keys for operational account %s, expected a single one",
+			len(accountKeys.PublicKeys),
+			operationalAddress,
+		)
+	}
+
+	return accountKeys.PublicKeys[0], nil
+}
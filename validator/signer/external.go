@@ -4,10 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
 	junoUtils "github.com/NethermindEth/juno/utils"
@@ -16,11 +20,22 @@ import (
 	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
 	"github.com/NethermindEth/starknet-staking-v2/validator/types"
 	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/hash"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/NethermindEth/starknet.go/utils"
 )
 
-var _ Signer = (*ExternalSigner)(nil)
+var (
+	_ Signer          = (*ExternalSigner)(nil)
+	_ AccountDeployer = (*ExternalSigner)(nil)
+	_ Invoker         = (*ExternalSigner)(nil)
+)
+
+var errUnsupportedEndpoint = errors.New("endpoint not supported by the external signer")
+
+// Protocol versions the external client can speak, from oldest to newest
+var clientProtocolVersions = []signer.ProtocolVersion{signer.ProtocolV1, signer.ProtocolV2}
 
 // Used as a wrapper around an exgernal signer implementation
 type ExternalSigner struct {
@@ -28,7 +43,7 @@ type ExternalSigner struct {
 	Provider            *rpc.Provider
 	operationalAddress  types.Address
 	chainID             felt.Felt
-	url                 string
+	signers             *signerPool
 	validationContracts types.ValidationContracts
 	// If the account used represents a braavos account
 	braavos bool
@@ -48,31 +63,51 @@ func NewExternalSigner(
 	}
 	chainID := new(felt.Felt).SetBytes([]byte(chainIDStr))
 
+	signers, err := newSignerPool(sig, logger)
+	if err != nil {
+		return ExternalSigner{}, err
+	}
+
 	validationContracts := types.ValidationContractsFromAddresses(addresses.SetDefaults(chainIDStr))
 	logger.Infof("validation contracts: %s", validationContracts.String())
 
-	return ExternalSigner{
+	externalSigner := ExternalSigner{
 		ctx:                 ctx,
 		Provider:            provider,
 		operationalAddress:  types.AddressFromString(sig.OperationalAddress),
-		url:                 sig.ExternalURL,
+		signers:             signers,
 		chainID:             *chainID,
 		validationContracts: validationContracts,
 		braavos:             braavos,
-	}, nil
+	}
+	if sig.PublicKey == "" {
+		// Only read when the first signature to check is received
+		for _, endpoint := range signers.endpoints {
+			endpoint.client.SetPublicKeySource(func() (*felt.Felt, error) {
+				return FetchAccountPublicKey(&externalSigner)
+			})
+		}
+	}
+
+	return externalSigner, nil
 }
 
 func (s *ExternalSigner) BuildAttestTransaction(
 	blockhash *types.BlockHash,
 ) (rpc.BroadcastInvokeTxnV3, error) {
-	invokeCall := []rpc.InvokeFunctionCall{{
+	return s.BuildInvokeTransaction([]rpc.InvokeFunctionCall{{
 		ContractAddress: s.ValidationContracts().Attest.Felt(),
 		FunctionName:    "attest",
 		CallData:        []*felt.Felt{blockhash.Felt()},
-	}}
-	call := utils.InvokeFuncCallsToFunctionCalls(invokeCall)
+	}})
+}
+
+func (s *ExternalSigner) BuildInvokeTransaction(
+	calls []rpc.InvokeFunctionCall,
+) (rpc.BroadcastInvokeTxnV3, error) {
+	call := utils.InvokeFuncCallsToFunctionCalls(calls)
 	calldata := account.FmtCallDataCairo2(call)
-	defaultResources := makeDefaultResources()
+	defaultResources := MakeDefaultResources()
 
 	nonce, err := s.Provider.Nonce(
 		s.ctx,
@@ -89,7 +124,7 @@ func (s *ExternalSigner) BuildAttestTransaction(
 	}
 
 	// Taken from starknet.go `utils.BuildInvokeTxn`
-	attestTransaction := rpc.BroadcastInvokeTxnV3{
+	invokeTransaction := rpc.BroadcastInvokeTxnV3{
 		Type:                  rpc.TransactionTypeInvoke,
 		SenderAddress:         s.Address().Felt(),
 		Calldata:              calldata,
@@ -104,7 +139,7 @@ func (s *ExternalSigner) BuildAttestTransaction(
 		FeeMode:               rpc.DAModeL1,
 	}
 
-	return attestTransaction, nil
+	return invokeTransaction, nil
 }
 
 func (s *ExternalSigner) EstimateFee(
@@ -115,7 +150,9 @@ func (s *ExternalSigner) EstimateFee(
 		// The query bit txn version is used for custom validation logic from wallets/accounts
 		// when estimating fee/simulating txns
 		txn.Version = rpc.TransactionV3WithQueryBit
-		_, err := s.SignTransaction(txn)
+		// Transactions with the query bit can't be executed, so they are signed without an
+		// epoch
+		err := s.SignInvokeTransaction(txn)
 		if err != nil {
 			return rpc.FeeEstimation{}, err
 		}
@@ -139,10 +176,175 @@ func (s *ExternalSigner) EstimateFee(
 	return estimateFee[0], nil
 }
 
+// Signs the attestation with the epoch it is for, as known by the caller. Reading it from
+// the chain here could return the next epoch at an epoch boundary
 func (s *ExternalSigner) SignTransaction(
-	txn *rpc.BroadcastInvokeTxnV3,
+	txn *rpc.BroadcastInvokeTxnV3, epochID uint64,
 ) (*rpc.BroadcastInvokeTxnV3, error) {
-	return txn, SignInvokeTx(txn, &s.chainID, s.url)
+	signResp, err := s.sign(func(client *ExternalClient) (signer.Response, error) {
+		return client.HashAndSignAttestTx(txn, &s.chainID, epochID)
+	})
+	if err != nil {
+		return txn, err
+	}
+	txn.Signature = signResp.Signature
+
+	return txn, nil
+}
+
+// Signs a transaction other than an attestation, which is sent without an epoch
+func (s *ExternalSigner) SignInvokeTransaction(txn *rpc.BroadcastInvokeTxnV3) error {
+	signResp, err := s.sign(func(client *ExternalClient) (signer.Response, error) {
+		return client.HashAndSignTx(txn, &s.chainID)
+	})
+	if err != nil {
+		return err
+	}
+	txn.Signature = signResp.Signature
+
+	return nil
+}
+
+// Agrees on a protocol version with each external signer and checks its key. With
+// redundant signers, the ones failing are only logged and are checked again before their
+// next request. It fails if no signer passes the checks.
+func (s *ExternalSigner) Prepare(logger *junoUtils.ZapLogger) error {
+	var errs []error
+	for _, endpoint := range s.signers.endpoints {
+		endpoint.mu.Lock()
+		err := s.prepare(endpoint, logger)
+		endpoint.mu.Unlock()
+		if err != nil {
+			err = s.signers.wrap(endpoint, err)
+			if s.signers.redundant() {
+				logger.Warnf("%s, it is only used once the checks pass", err)
+			}
+			s.signers.setHealthy(endpoint, false, err)
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == len(s.signers.endpoints) {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+// Checks the key of each external signer, see `checkSignerKey`
+func (s *ExternalSigner) CheckSignerKey(logger *junoUtils.ZapLogger) error {
+	for _, endpoint := range s.signers.endpoints {
+		endpoint.mu.Lock()
+		err := s.checkSignerKey(endpoint, logger)
+		endpoint.mu.Unlock()
+		if err != nil {
+			return s.signers.wrap(endpoint, err)
+		}
+	}
+
+	return nil
+}
+
+// Negotiates the protocol with the signer and checks its key, unless it was already
+// done. Must be called with the endpoint lock held.
+func (s *ExternalSigner) prepare(endpoint *signerEndpoint, logger *junoUtils.ZapLogger) error {
+	if endpoint.prepared {
+		return nil
+	}
+	if err := s.negotiate(endpoint, logger); err != nil {
+		return err
+	}
+	if err := s.checkSignerKey(endpoint, logger); err != nil {
+		return err
+	}
+	endpoint.prepared = true
+
+	return nil
+}
+
+// Agrees on a protocol version with the external signer and checks it can sign the
+// transactions sent by the validator
+func (s *ExternalSigner) negotiate(endpoint *signerEndpoint, logger *junoUtils.ZapLogger) error {
+	capabilities, err := endpoint.client.Negotiate()
+	if err != nil {
+		return err
+	}
+
+	if !capabilities.SupportsTransaction(rpc.TransactionTypeInvoke, rpc.TransactionV3) {
+		return errors.New("external signer cannot sign INVOKE v3 transactions")
+	}
+	// Braavos accounts estimate fees with transactions signed with the query bit version
+	if s.braavos &&
+		!capabilities.SupportsTransaction(rpc.TransactionTypeInvoke, rpc.TransactionV3WithQueryBit) {
+		return errors.New(
+			"external signer cannot sign query bit INVOKE v3 transactions, needed by braavos" +
+				" accounts",
+		)
+	}
+	logger.Infof(
+		"using protocol version %d with external signer %s",
+		endpoint.client.protocolVersion,
+		endpoint.url,
+	)
+
+	return nil
+}
+
+// Checks the external signer holds an enabled key for the operational account, and that
+// the key matches the account public key. Signers without the public key endpoint are
+// not checked.
+func (s *ExternalSigner) checkSignerKey(
+	endpoint *signerEndpoint, logger *junoUtils.ZapLogger,
+) error {
+	client := &endpoint.client
+	publicKeys, err := client.PublicKeys()
+	if errors.Is(err, errUnsupportedEndpoint) {
+		logger.Warnf(
+			"external signer %s doesn't expose its public keys, cannot check them", endpoint.url,
+		)
+
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot get the external signer public keys: %w", err)
+	}
+
+	accountKeys := publicKeys.For(s.Address().Felt())
+	if accountKeys == nil {
+		return fmt.Errorf("external signer has no key for operational account %s", s.Address())
+	}
+	if !accountKeys.Enabled {
+		return fmt.Errorf(
+			"external signer key for operational account %s is disabled", s.Address(),
+		)
+	}
+
+	publicKey, err := client.accountPublicKey()
+	if err != nil {
+		if len(accountKeys.PublicKeys) > 1 {
+			// Accounts combining several keys don't necessarily expose a single public key
+			logger.Warnf("cannot check the keys of external signer %s: %s", endpoint.url, err)
+
+			return nil
+		}
+
+		return err
+	}
+	if publicKey == nil {
+		return nil
+	}
+	if !slices.ContainsFunc(accountKeys.PublicKeys, publicKey.Equal) {
+		return fmt.Errorf(
+			"external signer keys %s don't match the public key %s of operational account %s",
+			accountKeys.PublicKeys,
+			publicKey,
+			s.Address(),
+		)
+	}
+	logger.Infof(
+		"external signer %s key matches operational account %s", endpoint.url, s.Address(),
+	)
+
+	return nil
 }
 
 func (s *ExternalSigner) InvokeTransaction(
@@ -179,54 +381,291 @@ func (s *ExternalSigner) Nonce() (*felt.Felt, error) {
 	return s.Provider.Nonce(s.ctx, rpc.WithBlockTag(rpc.BlockTagPreConfirmed), s.Address().Felt())
 }
 
+// Sends signing requests to an external signer
+type ExternalClient struct {
+	url        string
+	auth       signer.ClientAuth
+	httpClient *http.Client
+	// If set, signatures returned by the signer are checked against it. Otherwise it is
+	// read from the source, if any, the first time it is needed
+	publicKey       *felt.Felt
+	publicKeySource func() (*felt.Felt, error)
+	// Set once negotiated with the signer. Requests are sent as v1 until then
+	protocolVersion signer.ProtocolVersion
+	capabilities    signer.Capabilities
+	// Set for a signer served over a Unix domain socket
+	socketPath string
+	// Set for a signer served over gRPC
+	grpc *grpcClient
+	// Time the signer has to answer each request. No limit if zero
+	timeout time.Duration
+}
+
+// Creates a client of the signer at the url, either an http(s) one, a
+// `unix:///path/to/socket` one for a signer served over a Unix domain socket or a
+// `grpc://host:port` one for a signer served over gRPC
+func NewExternalClient(url string, auth signer.ClientAuth) ExternalClient {
+	client := ExternalClient{
+		url:             url,
+		auth:            auth,
+		httpClient:      http.DefaultClient,
+		publicKey:       nil,
+		publicKeySource: nil,
+		protocolVersion: signer.ProtocolV1,
+		capabilities:    signer.V1Capabilities(),
+		socketPath:      "",
+		grpc:            nil,
+		timeout:         0,
+	}
+	if socketPath, ok := signer.SocketPath(url); ok {
+		// The host is ignored, requests are always sent through the socket
+		client.url = "http://signer"
+		client.socketPath = socketPath
+		//nolint:exhaustruct // Only specifying used fields
+		client.httpClient = &http.Client{Transport: unixSocketTransport(socketPath)}
+	}
+	if target, ok := signer.GRPCAddress(url); ok {
+		//nolint:exhaustruct // Connected when first used
+		client.grpc = &grpcClient{target: target}
+	}
+
+	return client
+}
+
+// Sends requests through the socket, after checking no other user can replace it
+func unixSocketTransport(socketPath string) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // stdlib
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		if err := signer.CheckSocketPermissions(socketPath); err != nil {
+			return nil, fmt.Errorf("refusing to use the signer socket: %w", err)
+		}
+		//nolint:exhaustruct // Using default values
+		var dialer net.Dialer
+
+		return dialer.DialContext(ctx, "unix", socketPath)
+	}
+
+	return transport
+}
+
+// Releases the connection to a gRPC signer, if any
+func (c *ExternalClient) Close() error {
+	if c.grpc == nil {
+		return nil
+	}
+
+	return c.grpc.close()
+}
+
+// Verifies the signatures returned by the signer against the public key
+func (c *ExternalClient) SetPublicKey(publicKey *felt.Felt) {
+	c.publicKey = publicKey
+}
+
+// Same as `SetPublicKey` with a key read from the source when first needed
+func (c *ExternalClient) SetPublicKeySource(source func() (*felt.Felt, error)) {
+	c.publicKeySource = source
+}
+
+// Fails the requests the signer doesn't answer within the timeout
+func (c *ExternalClient) SetTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// Returns the context of a request, carrying its timeout if any
+func (c *ExternalClient) requestContext() (context.Context, context.CancelFunc) {
+	if c.timeout == 0 {
+		return context.WithCancel(context.Background())
+	}
+
+	return context.WithTimeout(context.Background(), c.timeout)
+}
+
+// Connects to the signer over TLS with the given settings
+func (c *ExternalClient) SetTLSConfig(tlsConfig *signer.ClientTLSConfig) error {
+	if c.socketPath != "" {
+		return errors.New("tls is not supported over a unix socket")
+	}
+
+	config, err := tlsConfig.Config()
+	if err != nil {
+		return err
+	}
+	if c.grpc != nil {
+		c.grpc.tlsConfig = config
+
+		return nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // stdlib
+	transport.TLSClientConfig = config
+	//nolint:exhaustruct // Only specifying used fields
+	c.httpClient = &http.Client{Transport: transport}
+
+	return nil
+}
+
+// Returns the client of the signer at the external url of the configuration
+func ExternalClientFromConfig(sig *config.Signer) (ExternalClient, error) {
+	return externalClientFromConfig(sig.ExternalURL, sig)
+}
+
+func externalClientFromConfig(url string, sig *config.Signer) (ExternalClient, error) {
+	timeout, err := sig.SignerRequestTimeout()
+	if err != nil {
+		return ExternalClient{}, err
+	}
+
+	client := NewExternalClient(url, signer.ClientAuth{
+		ClientID: sig.AuthClientID,
+		Token:    sig.AuthToken.Reveal(),
+		HMACKey:  sig.AuthHMACKey.Reveal(),
+	})
+	client.SetTimeout(timeout)
+
+	tlsConfig := signer.ClientTLSConfig{
+		CAFile:     sig.TLSCACert,
+		CertFile:   sig.TLSClientCert,
+		KeyFile:    sig.TLSClientKey,
+		ServerName: sig.TLSServerName,
+	}
+	if tlsConfig.Enabled() {
+		if err := client.SetTLSConfig(&tlsConfig); err != nil {
+			return ExternalClient{}, err
+		}
+	}
+
+	if sig.PublicKey != "" {
+		publicKey, err := new(felt.Felt).SetString(sig.PublicKey)
+		if err != nil {
+			return ExternalClient{}, fmt.Errorf("invalid signer public key: %w", err)
+		}
+		client.SetPublicKey(publicKey)
+	}
+
+	return client, nil
+}
+
+// Signs the transaction with an unauthenticated request to the external signer
 func SignInvokeTx(
 	invokeTxnV3 *rpc.BroadcastInvokeTxnV3,
 	chainID *felt.Felt,
 	externalSignerURL string,
 ) error {
-	signResp, err := HashAndSignTx(invokeTxnV3, chainID, externalSignerURL)
+	//nolint:exhaustruct // No authentication
+	client := NewExternalClient(externalSignerURL, signer.ClientAuth{})
+	defer func() { _ = client.Close() }()
+
+	return client.SignInvokeTx(invokeTxnV3, chainID)
+}
+
+// Hashes and signs the transaction with an unauthenticated request to the external signer
+func HashAndSignTx(
+	invokeTxnV3 *rpc.BroadcastInvokeTxnV3,
+	chainID *felt.Felt,
+	externalSignerURL string,
+) (signer.Response, error) {
+	//nolint:exhaustruct // No authentication
+	client := NewExternalClient(externalSignerURL, signer.ClientAuth{})
+	defer func() { _ = client.Close() }()
+
+	return client.HashAndSignTx(invokeTxnV3, chainID)
+}
+
+func (c *ExternalClient) SignInvokeTx(
+	invokeTxnV3 *rpc.BroadcastInvokeTxnV3,
+	chainID *felt.Felt,
+) error {
+	signResp, err := c.HashAndSignTx(invokeTxnV3, chainID)
 	if err != nil {
 		return err
 	}
 
-	invokeTxnV3.Signature = []*felt.Felt{
-		signResp.Signature[0],
-		signResp.Signature[1],
-	}
+	invokeTxnV3.Signature = signResp.Signature
 
 	return nil
 }
 
-func HashAndSignTx(
+func (c *ExternalClient) HashAndSignTx(
 	invokeTxnV3 *rpc.BroadcastInvokeTxnV3,
 	chainID *felt.Felt,
-	externalSignerURL string,
 ) (signer.Response, error) {
-	// Create request body
-	reqBody := signer.Request{InvokeTxnV3: invokeTxnV3, ChainID: chainID}
-	jsonData, err := json.Marshal(&reqBody)
+	//nolint:exhaustruct // No epoch for transactions other than attest
+	return c.send(&signer.Request{InvokeTxnV3: invokeTxnV3, ChainID: chainID})
+}
+
+// Same as `HashAndSignTx` for an attest transaction of the given epoch, which lets the
+// signer refuse conflicting attestations
+func (c *ExternalClient) HashAndSignAttestTx(
+	invokeTxnV3 *rpc.BroadcastInvokeTxnV3,
+	chainID *felt.Felt,
+	epochID uint64,
+) (signer.Response, error) {
+	return c.send(&signer.Request{InvokeTxnV3: invokeTxnV3, ChainID: chainID, EpochID: &epochID})
+}
+
+// Hashes and signs the deploy account transaction. Only signers negotiated to v2 of the
+// protocol, over http, can sign them
+func (c *ExternalClient) HashAndSignDeployAccountTx(
+	txn *rpc.BroadcastDeployAccountTxnV3,
+	chainID *felt.Felt,
+) (signer.Response, error) {
+	if c.protocolVersion < signer.ProtocolV2 ||
+		!c.capabilities.SupportsTransaction(rpc.TransactionTypeDeployAccount, txn.Version) {
+		return signer.Response{}, fmt.Errorf(
+			"external signer cannot sign DEPLOY_ACCOUNT transactions of version %s", txn.Version,
+		)
+	}
+
+	//nolint:exhaustruct // Only specifying used fields
+	return c.send(&signer.Request{DeployAccountTxnV3: txn, ChainID: chainID})
+}
+
+func (c *ExternalClient) send(reqBody *signer.Request) (signer.Response, error) {
+	if c.protocolVersion >= signer.ProtocolV2 {
+		reqBody.Protocol = c.protocolVersion
+	}
+
+	var signResp signer.Response
+	var err error
+	if c.grpc != nil {
+		signResp, err = c.grpc.sign(c, reqBody)
+	} else {
+		signResp, err = c.signHTTP(reqBody)
+	}
 	if err != nil {
 		return signer.Response{}, err
 	}
 
-	signEndpoint := externalSignerURL + signer.SignEndpoint
-	//nolint:gosec,noctx // Trusting the configured external signer URL // TODO: Context not configured
-	resp, err := http.Post(signEndpoint, "application/json", bytes.NewBuffer(jsonData))
+	if err := c.verify(reqBody, &signResp); err != nil {
+		return signer.Response{}, err
+	}
+
+	return signResp, nil
+}
+
+func (c *ExternalClient) signHTTP(reqBody *signer.Request) (signer.Response, error) {
+	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
 		return signer.Response{}, err
 	}
-	defer func() { _ = resp.Body.Close() }() // Intentionally ignoring the error, will fix in future
 
-	// Read and decode response
-	body, err := io.ReadAll(resp.Body)
+	statusCode, body, err := c.do(http.MethodPost, signer.SignEndpoint, jsonData)
 	if err != nil {
 		return signer.Response{}, err
 	}
 
+	if statusCode == http.StatusForbidden {
+		var policyViolation signer.PolicyViolation
+		if err := json.Unmarshal(body, &policyViolation); err == nil && policyViolation.Code != "" {
+			return signer.Response{}, &policyViolation
+		}
+	}
+
 	// Check if status code indicates an error (non-2xx)
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+	if statusCode < 200 || statusCode >= 300 {
 		return signer.Response{},
-			fmt.Errorf("server error %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+			fmt.Errorf("server error %d: %s", statusCode, strings.TrimSpace(string(body)))
 	}
 
 	var signResp signer.Response
@@ -238,7 +677,213 @@ func HashAndSignTx(
 	return signResp, nil
 }
 
-func makeDefaultResources() rpc.ResourceBoundsMapping {
+// Returns the capabilities of the signer. Signers without the capabilities endpoint only
+// support v1 of the protocol.
+func (c *ExternalClient) Capabilities() (signer.Capabilities, error) {
+	if c.grpc != nil {
+		return c.grpc.capabilities(c)
+	}
+
+	statusCode, body, err := c.do(http.MethodGet, signer.CapabilitiesEndpoint, nil)
+	if err != nil {
+		return signer.Capabilities{}, err
+	}
+	if statusCode == http.StatusNotFound || statusCode == http.StatusMethodNotAllowed {
+		return signer.V1Capabilities(), nil
+	}
+	if statusCode != http.StatusOK {
+		return signer.Capabilities{},
+			fmt.Errorf("server error %d: %s", statusCode, strings.TrimSpace(string(body)))
+	}
+
+	var capabilities signer.Capabilities
+	if err := json.Unmarshal(body, &capabilities); err != nil {
+		return signer.Capabilities{}, err
+	}
+
+	return capabilities, nil
+}
+
+// Reads the capabilities of the signer and sends the following requests with the newest
+// protocol version both sides support
+func (c *ExternalClient) Negotiate() (signer.Capabilities, error) {
+	capabilities, err := c.Capabilities()
+	if err != nil {
+		return signer.Capabilities{}, fmt.Errorf("cannot get the external signer capabilities: %w", err)
+	}
+
+	version, ok := capabilities.Negotiate(clientProtocolVersions)
+	if !ok {
+		return signer.Capabilities{}, fmt.Errorf(
+			"no protocol version in common with the external signer: it supports %v, the"+
+				" validator %v",
+			capabilities.ProtocolVersions,
+			clientProtocolVersions,
+		)
+	}
+	c.protocolVersion = version
+	c.capabilities = capabilities
+
+	return capabilities, nil
+}
+
+// Checks the signer is ready to sign. Signers without the readiness endpoint are ready
+// as long as they answer.
+func (c *ExternalClient) Ready() error {
+	if c.grpc != nil {
+		return c.grpc.ready(c)
+	}
+
+	statusCode, body, err := c.do(http.MethodGet, signer.ReadyEndpoint, nil)
+	if err != nil {
+		return err
+	}
+	switch statusCode {
+	case http.StatusOK, http.StatusNotFound, http.StatusMethodNotAllowed:
+		return nil
+	default:
+		return fmt.Errorf("signer not ready %d: %s", statusCode, strings.TrimSpace(string(body)))
+	}
+}
+
+// Returns the public keys of the signer and the accounts they sign for
+func (c *ExternalClient) PublicKeys() (signer.PublicKeysResponse, error) {
+	if c.grpc != nil {
+		return c.grpc.publicKeys(c)
+	}
+
+	statusCode, body, err := c.do(http.MethodGet, signer.PublicKeyEndpoint, nil)
+	if err != nil {
+		return signer.PublicKeysResponse{}, err
+	}
+	if statusCode == http.StatusNotFound || statusCode == http.StatusMethodNotAllowed {
+		return signer.PublicKeysResponse{}, errUnsupportedEndpoint
+	}
+	if statusCode != http.StatusOK {
+		return signer.PublicKeysResponse{},
+			fmt.Errorf("server error %d: %s", statusCode, strings.TrimSpace(string(body)))
+	}
+
+	var publicKeys signer.PublicKeysResponse
+	if err := json.Unmarshal(body, &publicKeys); err != nil {
+		return signer.PublicKeysResponse{}, err
+	}
+
+	return publicKeys, nil
+}
+
+// Sends an authenticated request to the endpoint, returning the status code and body of
+// the response
+func (c *ExternalClient) do(method, endpoint string, reqBody []byte) (int, []byte, error) {
+	ctx, cancel := c.requestContext()
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx, method, c.url+endpoint, bytes.NewReader(reqBody),
+	)
+	if err != nil {
+		return 0, nil, err
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if err := c.auth.Apply(req, reqBody); err != nil {
+		return 0, nil, err
+	}
+
+	//nolint:gosec // Trusting the configured external signer URL
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }() // Intentionally ignoring the error, will fix in future
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return resp.StatusCode, body, nil
+}
+
+// Checks the signer signed the hash of the transaction sent and, if the public key is
+// known, that the signature is valid for it. Catches a faulty signer, or one using the
+// wrong key, before paying for a transaction that fails on-chain. Signatures combining
+// several keys have an account specific format and are only checked by the account.
+func (c *ExternalClient) verify(reqBody *signer.Request, signResp *signer.Response) error {
+	txHash, err := requestHash(reqBody)
+	if err != nil {
+		return fmt.Errorf("cannot compute the transaction hash: %w", err)
+	}
+
+	if signResp.TxHash != nil && !signResp.TxHash.Equal(txHash) {
+		return fmt.Errorf(
+			"external signer signed transaction hash %s, expected %s", signResp.TxHash, txHash,
+		)
+	}
+
+	if len(signResp.Signature) == 0 || slices.Contains(signResp.Signature, nil) {
+		return errors.New("external signer returned an empty signature")
+	}
+	if len(signResp.Signature) != 2 { //nolint:mnd // ECDSA `r` and `s` values
+		return nil
+	}
+
+	publicKey, err := c.accountPublicKey()
+	if err != nil {
+		return err
+	}
+	if publicKey == nil {
+		return nil
+	}
+	valid, err := curve.VerifyFelts(
+		txHash, signResp.Signature[0], signResp.Signature[1], publicKey,
+	)
+	if err != nil || !valid {
+		return fmt.Errorf(
+			"external signer returned an invalid signature for transaction hash %s with"+
+				" public key %s",
+			txHash,
+			publicKey,
+		)
+	}
+
+	return nil
+}
+
+// Hash of the transaction of the request, invoke or deploy account
+func requestHash(reqBody *signer.Request) (*felt.Felt, error) {
+	if txn := reqBody.DeployAccountTxnV3; txn != nil {
+		address := account.PrecomputeAccountAddress(
+			txn.ContractAddressSalt, txn.ClassHash, txn.ConstructorCalldata,
+		)
+
+		return hash.TransactionHashDeployAccountV3(txn, address, reqBody.ChainID)
+	}
+
+	return hash.TransactionHashInvokeV3(reqBody.InvokeTxnV3, reqBody.ChainID)
+}
+
+// Returns the public key signatures are verified against, reading it from its source
+// the first time. Nil if it is unknown.
+func (c *ExternalClient) accountPublicKey() (*felt.Felt, error) {
+	if c.publicKey == nil && c.publicKeySource != nil {
+		publicKey, err := c.publicKeySource()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"cannot read the account public key to verify the external signer"+
+					" signatures, set it in the signer configuration instead: %w",
+				err,
+			)
+		}
+		c.publicKey = publicKey
+	}
+
+	return c.publicKey, nil
+}
+
+// Zero resource bounds, set on transactions before their fee is estimated
+func MakeDefaultResources() rpc.ResourceBoundsMapping {
 	return rpc.ResourceBoundsMapping{
 		L1Gas: rpc.ResourceBounds{
 			MaxAmount:       "0x0",
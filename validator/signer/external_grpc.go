@@ -0,0 +1,198 @@
+package signer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/signer/signerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Sends the external client requests to a signer served over gRPC
+type grpcClient struct {
+	target string
+	// If set, the connection uses TLS. Otherwise it is in plain text
+	tlsConfig *tls.Config
+
+	mu     sync.Mutex
+	conn   *grpc.ClientConn
+	client signerpb.SignerClient
+	health healthpb.HealthClient
+}
+
+// Returns the client of the signer service, connecting on the first call
+func (g *grpcClient) signerClient() (signerpb.SignerClient, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.client != nil {
+		return g.client, nil
+	}
+
+	transportCredentials := insecure.NewCredentials()
+	if g.tlsConfig != nil {
+		transportCredentials = credentials.NewTLS(g.tlsConfig)
+	}
+	conn, err := grpc.NewClient(g.target, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to the grpc signer: %w", err)
+	}
+	g.conn = conn
+	g.client = signerpb.NewSignerClient(conn)
+	g.health = healthpb.NewHealthClient(conn)
+
+	return g.client, nil
+}
+
+// Returns the client of the health service, connecting on the first call
+func (g *grpcClient) healthClient() (healthpb.HealthClient, error) {
+	if _, err := g.signerClient(); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.health, nil
+}
+
+func (g *grpcClient) close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.conn == nil {
+		return nil
+	}
+	err := g.conn.Close()
+	g.conn = nil
+	g.client = nil
+	g.health = nil
+
+	return err
+}
+
+// Returns the context of a request of the client, carrying its credentials and timeout.
+// Only bearer tokens are supported over gRPC
+func grpcContext(c *ExternalClient) (context.Context, context.CancelFunc, error) {
+	ctx, cancel := c.requestContext()
+	switch {
+	case c.auth.Token != "":
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+c.auth.Token)
+
+		return ctx, cancel, nil
+	case c.auth.HMACKey != "":
+		cancel()
+
+		return nil, nil, errors.New("hmac authentication is not supported over grpc")
+	default:
+		return ctx, cancel, nil
+	}
+}
+
+func (g *grpcClient) sign(c *ExternalClient, req *signer.Request) (signer.Response, error) {
+	if req.DeployAccountTxnV3 != nil {
+		return signer.Response{}, errors.New("deploy account transactions cannot be sent over grpc")
+	}
+	client, err := g.signerClient()
+	if err != nil {
+		return signer.Response{}, err
+	}
+	ctx, cancel, err := grpcContext(c)
+	if err != nil {
+		return signer.Response{}, err
+	}
+	defer cancel()
+
+	resp, err := client.Sign(ctx, req.ToProto())
+	if err != nil {
+		if policyViolation, ok := signer.PolicyViolationFromStatus(err); ok {
+			return signer.Response{}, policyViolation
+		}
+
+		return signer.Response{}, fmt.Errorf("server error: %w", err)
+	}
+
+	return signer.ResponseFromProto(resp)
+}
+
+func (g *grpcClient) publicKeys(c *ExternalClient) (signer.PublicKeysResponse, error) {
+	client, err := g.signerClient()
+	if err != nil {
+		return signer.PublicKeysResponse{}, err
+	}
+	ctx, cancel, err := grpcContext(c)
+	if err != nil {
+		return signer.PublicKeysResponse{}, err
+	}
+	defer cancel()
+
+	resp, err := client.GetPublicKeys(ctx, &signerpb.GetPublicKeysRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return signer.PublicKeysResponse{}, errUnsupportedEndpoint
+	}
+	if err != nil {
+		return signer.PublicKeysResponse{}, fmt.Errorf("server error: %w", err)
+	}
+
+	return signer.PublicKeysResponseFromProto(resp)
+}
+
+func (g *grpcClient) capabilities(c *ExternalClient) (signer.Capabilities, error) {
+	client, err := g.signerClient()
+	if err != nil {
+		return signer.Capabilities{}, err
+	}
+	ctx, cancel, err := grpcContext(c)
+	if err != nil {
+		return signer.Capabilities{}, err
+	}
+	defer cancel()
+
+	resp, err := client.GetCapabilities(ctx, &signerpb.GetCapabilitiesRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return signer.V1Capabilities(), nil
+	}
+	if err != nil {
+		return signer.Capabilities{}, fmt.Errorf("server error: %w", err)
+	}
+
+	return signer.CapabilitiesFromProto(resp), nil
+}
+
+// Checks the signer service is serving. Signers without the health service are ready as
+// long as they answer
+func (g *grpcClient) ready(c *ExternalClient) error {
+	client, err := g.healthClient()
+	if err != nil {
+		return err
+	}
+	ctx, cancel, err := grpcContext(c)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{
+		Service: signerpb.Signer_ServiceDesc.ServiceName,
+	})
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("server error: %w", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("signer not ready: %s", resp.GetStatus())
+	}
+
+	return nil
+}
@@ -1,18 +1,35 @@
 package signer_test
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"io"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/secret"
 	s "github.com/NethermindEth/starknet-staking-v2/signer"
 	"github.com/NethermindEth/starknet-staking-v2/validator"
 	"github.com/NethermindEth/starknet-staking-v2/validator/config"
 	"github.com/NethermindEth/starknet-staking-v2/validator/signer"
 	"github.com/NethermindEth/starknet-staking-v2/validator/types"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/hash"
 	"github.com/NethermindEth/starknet.go/rpc"
 	snUtils "github.com/NethermindEth/starknet.go/utils"
 	"github.com/stretchr/testify/require"
@@ -417,18 +434,12 @@ func TestHashAndSignTx(t *testing.T) {
 				}))
 		defer mockServer.Close()
 
-		invokeTxnV3 := snUtils.BuildInvokeTxn(
-			utils.HexToFelt(t, "0x123"),
-			new(felt.Felt).SetUint64(1),
-			[]*felt.Felt{},
-			&rpc.ResourceBoundsMapping{},
-			nil,
-		)
+		invokeTxnV3 := newSignableTxn(t)
 		chainID := new(felt.Felt).SetUint64(1)
 		res, err := signer.HashAndSignTx(invokeTxnV3, chainID, mockServer.URL)
 
 		expectedResult := s.Response{
-			Signature: [2]*felt.Felt{
+			Signature: []*felt.Felt{
 				new(felt.Felt).SetUint64(0x123),
 				new(felt.Felt).SetUint64(0x456),
 			},
@@ -437,3 +448,728 @@ func TestHashAndSignTx(t *testing.T) {
 		require.Equal(t, expectedResult, res)
 	})
 }
+
+func TestExternalClientAuthentication(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+
+	remoteSigner, err := s.New("0x123", logger)
+	require.NoError(t, err)
+	//nolint:exhaustruct // Only specifying used fields
+	authConfig := s.AuthConfig{Clients: []s.ClientCredential{
+		{ID: "bearer-client", Token: secret.New("some token")},
+		{ID: "hmac-client", HMACKey: secret.New("some key")},
+	}}
+	require.NoError(t, authConfig.Check())
+	remoteSigner.SetAuthenticator(
+		s.NewAuthenticator(&authConfig, s.DefaultReplayWindow, logger),
+	)
+
+	mockServer := httptest.NewServer(remoteSigner.Handler())
+	defer mockServer.Close()
+
+	invokeTxnV3 := snUtils.BuildInvokeTxn(
+		utils.HexToFelt(t, "0x123"),
+		new(felt.Felt).SetUint64(1),
+		[]*felt.Felt{new(felt.Felt).SetUint64(1)},
+		&rpc.ResourceBoundsMapping{
+			L1Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+			L1DataGas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+			L2Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		},
+		nil,
+	)
+	invokeTxnV3.Tip = "0x0"
+	chainID := new(felt.Felt).SetUint64(1)
+
+	t.Run("Unauthenticated request is rejected", func(t *testing.T) {
+		res, err := signer.HashAndSignTx(invokeTxnV3, chainID, mockServer.URL)
+
+		require.Zero(t, res)
+		require.ErrorContains(t, err, fmt.Sprintf("server error %d", http.StatusUnauthorized))
+	})
+
+	t.Run("Wrong bearer token is rejected", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		client := signer.NewExternalClient(mockServer.URL, s.ClientAuth{Token: "other token"})
+		res, err := client.HashAndSignTx(invokeTxnV3, chainID)
+
+		require.Zero(t, res)
+		require.ErrorContains(t, err, "invalid bearer token")
+	})
+
+	t.Run("Wrong hmac key is rejected", func(t *testing.T) {
+		client := signer.NewExternalClient(
+			mockServer.URL,
+			//nolint:exhaustruct // Only specifying used fields
+			s.ClientAuth{ClientID: "hmac-client", HMACKey: "other key"},
+		)
+		res, err := client.HashAndSignTx(invokeTxnV3, chainID)
+
+		require.Zero(t, res)
+		require.ErrorContains(t, err, "invalid request signature")
+	})
+
+	t.Run("Successful request with a bearer token", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		client := signer.NewExternalClient(mockServer.URL, s.ClientAuth{Token: "some token"})
+		res, err := client.HashAndSignTx(invokeTxnV3, chainID)
+
+		require.NoError(t, err)
+		require.NotNil(t, res.Signature[0])
+	})
+
+	t.Run("Successful request with a hmac key", func(t *testing.T) {
+		client := signer.NewExternalClient(
+			mockServer.URL,
+			//nolint:exhaustruct // Only specifying used fields
+			s.ClientAuth{ClientID: "hmac-client", HMACKey: "some key"},
+		)
+		res, err := client.HashAndSignTx(invokeTxnV3, chainID)
+
+		require.NoError(t, err)
+		require.NotNil(t, res.Signature[0])
+	})
+
+	t.Run("Replayed hmac request is rejected", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		auth := s.ClientAuth{ClientID: "hmac-client", HMACKey: "some key"}
+		body, err := json.Marshal(&s.Request{InvokeTxnV3: invokeTxnV3, ChainID: chainID})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, s.SignEndpoint, bytes.NewReader(body))
+		require.NoError(t, auth.Apply(req, body))
+
+		handler := remoteSigner.Handler()
+
+		first := httptest.NewRecorder()
+		handler.ServeHTTP(first, req.Clone(t.Context()))
+		require.NotEqual(t, http.StatusUnauthorized, first.Code)
+
+		replayed := req.Clone(t.Context())
+		replayed.Body = io.NopCloser(bytes.NewReader(body))
+		second := httptest.NewRecorder()
+		handler.ServeHTTP(second, replayed)
+		require.Equal(t, http.StatusUnauthorized, second.Code)
+		require.Contains(t, second.Body.String(), "replayed request")
+	})
+}
+
+func TestExternalClientTLS(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+	certs := generateTestCertificates(t)
+
+	remoteSigner, err := s.New("0x123", logger)
+	require.NoError(t, err)
+
+	invokeTxnV3 := snUtils.BuildInvokeTxn(
+		utils.HexToFelt(t, "0x123"),
+		new(felt.Felt).SetUint64(1),
+		[]*felt.Felt{new(felt.Felt).SetUint64(1)},
+		&rpc.ResourceBoundsMapping{
+			L1Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+			L1DataGas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+			L2Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		},
+		nil,
+	)
+	invokeTxnV3.Tip = "0x0"
+	chainID := new(felt.Felt).SetUint64(1)
+
+	startServer := func(t *testing.T, serverTLS *s.TLSConfig) *httptest.Server {
+		t.Helper()
+
+		tlsConfig, err := serverTLS.ServerConfig()
+		require.NoError(t, err)
+
+		server := httptest.NewUnstartedServer(remoteSigner.Handler())
+		server.TLS = tlsConfig
+		server.StartTLS()
+		t.Cleanup(server.Close)
+
+		return server
+	}
+
+	//nolint:exhaustruct // No client certificate verification
+	server := startServer(t, &s.TLSConfig{CertFile: certs.serverCert, KeyFile: certs.serverKey})
+	mutualServer := startServer(t, &s.TLSConfig{
+		CertFile:     certs.serverCert,
+		KeyFile:      certs.serverKey,
+		ClientCAFile: certs.caCert,
+	})
+
+	newClient := func(t *testing.T, url string, sig *config.Signer) signer.ExternalClient {
+		t.Helper()
+
+		sig.ExternalURL = url
+		client, err := signer.ExternalClientFromConfig(sig)
+		require.NoError(t, err)
+
+		return client
+	}
+
+	t.Run("Signer certificate not trusted without the CA", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		client := newClient(t, server.URL, &config.Signer{})
+		_, err := client.HashAndSignTx(invokeTxnV3, chainID)
+		require.ErrorContains(t, err, "certificate signed by unknown authority")
+	})
+
+	t.Run("Signer certificate verified with the CA", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		client := newClient(t, server.URL, &config.Signer{TLSCACert: certs.caCert})
+		res, err := client.HashAndSignTx(invokeTxnV3, chainID)
+		require.NoError(t, err)
+		require.NotNil(t, res.Signature[0])
+	})
+
+	t.Run("Signer certificate not valid for the pinned server name", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		client := newClient(t, server.URL, &config.Signer{
+			TLSCACert:     certs.caCert,
+			TLSServerName: "other.signer",
+		})
+		_, err := client.HashAndSignTx(invokeTxnV3, chainID)
+		require.ErrorContains(t, err, "other.signer")
+	})
+
+	t.Run("Signer requiring a client certificate rejects the client", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		client := newClient(t, mutualServer.URL, &config.Signer{TLSCACert: certs.caCert})
+		_, err := client.HashAndSignTx(invokeTxnV3, chainID)
+		require.Error(t, err)
+	})
+
+	t.Run("Signer requiring a client certificate accepts the client", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		client := newClient(t, mutualServer.URL, &config.Signer{
+			TLSCACert:     certs.caCert,
+			TLSClientCert: certs.clientCert,
+			TLSClientKey:  certs.clientKey,
+			TLSServerName: "localhost",
+		})
+		res, err := client.HashAndSignTx(invokeTxnV3, chainID)
+		require.NoError(t, err)
+		require.NotNil(t, res.Signature[0])
+	})
+}
+
+type testCertificates struct {
+	caCert     string
+	serverCert string
+	serverKey  string
+	clientCert string
+	clientKey  string
+}
+
+// Generates a CA together with a server and a client certificate issued by it
+func generateTestCertificates(t *testing.T) testCertificates {
+	t.Helper()
+
+	dir := t.TempDir()
+	writePEM := func(name, blockType string, data []byte) string {
+		path := filepath.Join(dir, name)
+		//nolint:exhaustruct // Only specifying used fields
+		encoded := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data})
+		require.NoError(t, os.WriteFile(path, encoded, 0o600))
+
+		return path
+	}
+
+	newKey := func() *ecdsa.PrivateKey {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		return key
+	}
+
+	caKey := newKey()
+	//nolint:exhaustruct // Only specifying used fields
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	issue := func(serial int64, name string, usage x509.ExtKeyUsage) (string, string) {
+		key := newKey()
+		//nolint:exhaustruct // Only specifying used fields
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     []string{"localhost"},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+
+		return writePEM(name+".crt", "CERTIFICATE", der),
+			writePEM(name+".key", "EC PRIVATE KEY", keyDER)
+	}
+
+	serverCert, serverKey := issue(2, "server", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := issue(3, "client", x509.ExtKeyUsageClientAuth)
+
+	return testCertificates{
+		caCert:     writePEM("ca.crt", "CERTIFICATE", caDER),
+		serverCert: serverCert,
+		serverKey:  serverKey,
+		clientCert: clientCert,
+		clientKey:  clientKey,
+	}
+}
+
+func TestExternalClientPolicyViolation(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+
+	remoteSigner, err := s.New("0x123", logger)
+	require.NoError(t, err)
+	//nolint:exhaustruct // Only specifying used fields
+	policy, err := s.NewPolicy(&s.PolicyConfig{ChainIDs: []string{"SN_MAIN"}})
+	require.NoError(t, err)
+	remoteSigner.SetPolicy(policy)
+
+	mockServer := httptest.NewServer(remoteSigner.Handler())
+	defer mockServer.Close()
+
+	invokeTxnV3 := snUtils.BuildInvokeTxn(
+		utils.HexToFelt(t, "0x123"),
+		new(felt.Felt).SetUint64(1),
+		[]*felt.Felt{new(felt.Felt).SetUint64(1)},
+		&rpc.ResourceBoundsMapping{},
+		nil,
+	)
+	chainID := new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))
+
+	res, err := signer.HashAndSignTx(invokeTxnV3, chainID, mockServer.URL)
+	require.Zero(t, res)
+
+	policyViolation, ok := s.AsPolicyViolation(err)
+	require.True(t, ok)
+	require.Equal(t, s.ReasonChainIDNotAllowed, policyViolation.Code)
+}
+
+func TestExternalClientVerifiesSignatures(t *testing.T) {
+	remoteSigner, err := s.New("0x123", utils.NewNopZapLogger())
+	require.NoError(t, err)
+	mockServer := httptest.NewServer(remoteSigner.Handler())
+	defer mockServer.Close()
+
+	invokeTxnV3 := newSignableTxn(t)
+	chainID := new(felt.Felt).SetUint64(1)
+	txHash, err := hash.TransactionHashInvokeV3(invokeTxnV3, chainID)
+	require.NoError(t, err)
+
+	publicKeyOf := func(privateKey int64) *felt.Felt {
+		publicKey, _ := curve.PrivateKeyToPoint(big.NewInt(privateKey))
+
+		return new(felt.Felt).SetBigInt(publicKey)
+	}
+
+	t.Run("Signature valid for the public key", func(t *testing.T) {
+		//nolint:exhaustruct // No authentication
+		client := signer.NewExternalClient(mockServer.URL, s.ClientAuth{})
+		client.SetPublicKey(publicKeyOf(0x123))
+
+		res, err := client.HashAndSignTx(invokeTxnV3, chainID)
+		require.NoError(t, err)
+		require.Equal(t, txHash, res.TxHash)
+	})
+
+	t.Run("Signature made with another key", func(t *testing.T) {
+		//nolint:exhaustruct // No authentication
+		client := signer.NewExternalClient(mockServer.URL, s.ClientAuth{})
+		client.SetPublicKey(publicKeyOf(0x456))
+
+		res, err := client.HashAndSignTx(invokeTxnV3, chainID)
+		require.Zero(t, res)
+		require.ErrorContains(t, err, "external signer returned an invalid signature")
+	})
+
+	t.Run("Signature combining several keys", func(t *testing.T) {
+		multisigSigner, err := s.NewWithAccountKeys([]s.AccountKey{
+			{Account: *invokeTxnV3.SenderAddress, PrivateKey: big.NewInt(0x123), Enabled: true},
+			{Account: *invokeTxnV3.SenderAddress, PrivateKey: big.NewInt(0x456), Enabled: true},
+		}, utils.NewNopZapLogger())
+		require.NoError(t, err)
+		multisigServer := httptest.NewServer(multisigSigner.Handler())
+		defer multisigServer.Close()
+
+		//nolint:exhaustruct // No authentication
+		client := signer.NewExternalClient(multisigServer.URL, s.ClientAuth{})
+		client.SetPublicKey(publicKeyOf(0x123))
+
+		txn := *invokeTxnV3
+		require.NoError(t, client.SignInvokeTx(&txn, chainID))
+		require.Len(t, txn.Signature, 4)
+	})
+
+	t.Run("Signature for another transaction hash", func(t *testing.T) {
+		otherSigner := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_, err := w.Write(
+					[]byte(`{"signature": ["0x123", "0x456"], "transaction_hash": "0x789"}`),
+				)
+				require.NoError(t, err)
+			}))
+		defer otherSigner.Close()
+
+		res, err := signer.HashAndSignTx(invokeTxnV3, chainID, otherSigner.URL)
+		require.Zero(t, res)
+		require.ErrorContains(t, err, "external signer signed transaction hash 0x789")
+	})
+}
+
+func TestExternalClientDeployAccount(t *testing.T) {
+	chainID := new(felt.Felt).SetUint64(1)
+	publicKey, _ := curve.PrivateKeyToPoint(big.NewInt(0x123))
+	publicKeyFelt := new(felt.Felt).SetBigInt(publicKey)
+
+	accountClass, err := s.NewAccountClass(s.AccountTypeOpenZeppelin, nil)
+	require.NoError(t, err)
+	address := accountClass.Address(publicKeyFelt, publicKeyFelt)
+	txn := rpc.BroadcastDeployAccountTxnV3{
+		Type:                rpc.TransactionTypeDeployAccount,
+		Version:             rpc.TransactionV3,
+		Signature:           []*felt.Felt{},
+		Nonce:               new(felt.Felt),
+		ContractAddressSalt: publicKeyFelt,
+		ConstructorCalldata: accountClass.ConstructorCalldata(publicKeyFelt),
+		ClassHash:           accountClass.ClassHash,
+		ResourceBounds: &rpc.ResourceBoundsMapping{
+			L1Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+			L1DataGas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+			L2Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		},
+		Tip:           "0x0",
+		PayMasterData: []*felt.Felt{},
+		NonceDataMode: rpc.DAModeL1,
+		FeeMode:       rpc.DAModeL1,
+	}
+
+	remoteSigner, err := s.NewWithAccountKeys([]s.AccountKey{
+		{Account: *address, PrivateKey: big.NewInt(0x123), Enabled: true},
+	}, utils.NewNopZapLogger())
+	require.NoError(t, err)
+	server := httptest.NewServer(remoteSigner.Handler())
+	defer server.Close()
+
+	t.Run("Signer supporting deploy account transactions", func(t *testing.T) {
+		//nolint:exhaustruct // No authentication
+		client := signer.NewExternalClient(server.URL, s.ClientAuth{})
+		client.SetPublicKey(publicKeyFelt)
+		_, err := client.Negotiate()
+		require.NoError(t, err)
+
+		res, err := client.HashAndSignDeployAccountTx(&txn, chainID)
+		require.NoError(t, err)
+
+		txHash, err := hash.TransactionHashDeployAccountV3(&txn, address, chainID)
+		require.NoError(t, err)
+		require.Equal(t, txHash, res.TxHash)
+	})
+
+	t.Run("Deploy account transactions require v2", func(t *testing.T) {
+		//nolint:exhaustruct // No authentication
+		client := signer.NewExternalClient(server.URL, s.ClientAuth{})
+
+		_, err := client.HashAndSignDeployAccountTx(&txn, chainID)
+		require.ErrorContains(t, err, "external signer cannot sign DEPLOY_ACCOUNT transactions")
+	})
+
+	t.Run("Resolve the public key from the signer", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		configSigner := config.Signer{ExternalURL: server.URL, OperationalAddress: address.String()}
+		require.NoError(t, signer.ResolveExternalPublicKey(&configSigner))
+		require.Equal(t, publicKeyFelt.String(), configSigner.PublicKey)
+	})
+
+	t.Run("No signer key for the operational address", func(t *testing.T) {
+		//nolint:exhaustruct // Only specifying used fields
+		configSigner := config.Signer{ExternalURL: server.URL, OperationalAddress: "0x456"}
+		err := signer.ResolveExternalPublicKey(&configSigner)
+		require.ErrorContains(t, err, "no key for operational account 0x456")
+	})
+}
+
+// Returns a transaction with all the fields required to compute its hash
+func newSignableTxn(t *testing.T) *rpc.BroadcastInvokeTxnV3 {
+	t.Helper()
+
+	invokeTxnV3 := snUtils.BuildInvokeTxn(
+		utils.HexToFelt(t, "0x123"),
+		new(felt.Felt).SetUint64(1),
+		[]*felt.Felt{new(felt.Felt).SetUint64(1)},
+		&rpc.ResourceBoundsMapping{
+			L1Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+			L1DataGas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+			L2Gas:     rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		},
+		nil,
+	)
+	invokeTxnV3.Tip = "0x0"
+
+	return invokeTxnV3
+}
+
+func TestCheckSignerKey(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+	operationalAddress := utils.HexToFelt(t, "0x123")
+
+	mockRPC := validator.MockRPCServer(t, operationalAddress, "")
+	defer mockRPC.Close()
+	provider, err := rpc.NewProvider(t.Context(), mockRPC.URL)
+	require.NoError(t, err)
+
+	publicKey, _ := curve.PrivateKeyToPoint(big.NewInt(0x123))
+
+	newExternalSigner := func(t *testing.T, signerURL string) *signer.ExternalSigner {
+		t.Helper()
+
+		externalSigner, err := signer.NewExternalSigner(
+			t.Context(),
+			provider,
+			logger,
+			&config.Signer{
+				ExternalURL:        signerURL,
+				OperationalAddress: operationalAddress.String(),
+				PublicKey:          "0x" + publicKey.Text(16),
+			},
+			new(config.ContractAddresses).SetDefaults("SN_SEPOLIA"),
+			false,
+		)
+		require.NoError(t, err)
+
+		return &externalSigner
+	}
+
+	serve := func(t *testing.T, keys []s.AccountKey) string {
+		t.Helper()
+
+		remoteSigner, err := s.NewWithAccountKeys(keys, logger)
+		require.NoError(t, err)
+		server := httptest.NewServer(remoteSigner.Handler())
+		t.Cleanup(server.Close)
+
+		return server.URL
+	}
+
+	t.Run("Signer key matches the account", func(t *testing.T) {
+		signerURL := serve(t, []s.AccountKey{
+			{Account: *operationalAddress, PrivateKey: big.NewInt(0x123), Enabled: true},
+		})
+		require.NoError(t, newExternalSigner(t, signerURL).CheckSignerKey(logger))
+	})
+
+	t.Run("Signer key doesn't match the account", func(t *testing.T) {
+		signerURL := serve(t, []s.AccountKey{
+			{Account: *operationalAddress, PrivateKey: big.NewInt(0x456), Enabled: true},
+		})
+		err := newExternalSigner(t, signerURL).CheckSignerKey(logger)
+		require.ErrorContains(t, err, "don't match the public key")
+	})
+
+	t.Run("Signer without key for the account", func(t *testing.T) {
+		signerURL := serve(t, []s.AccountKey{
+			{Account: *utils.HexToFelt(t, "0x456"), PrivateKey: big.NewInt(0x123), Enabled: true},
+		})
+		err := newExternalSigner(t, signerURL).CheckSignerKey(logger)
+		require.ErrorContains(t, err, "external signer has no key for operational account")
+	})
+
+	t.Run("Signer key for the account is disabled", func(t *testing.T) {
+		signerURL := serve(t, []s.AccountKey{
+			{Account: *operationalAddress, PrivateKey: big.NewInt(0x123), Enabled: false},
+		})
+		err := newExternalSigner(t, signerURL).CheckSignerKey(logger)
+		require.ErrorContains(t, err, "is disabled")
+	})
+
+	t.Run("Signer without the public key endpoint", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		require.NoError(t, newExternalSigner(t, server.URL).CheckSignerKey(logger))
+	})
+}
+
+func TestExternalClientNegotiation(t *testing.T) {
+	remoteSigner, err := s.New("0x123", utils.NewNopZapLogger())
+	require.NoError(t, err)
+
+	invokeTxnV3 := newSignableTxn(t)
+	chainID := new(felt.Felt).SetUint64(1)
+
+	// Records the protocol version of the signing requests before forwarding them
+	var requestVersions []s.ProtocolVersion
+	recordingHandler := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == s.SignEndpoint {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				var req s.Request
+				require.NoError(t, json.Unmarshal(body, &req))
+				requestVersions = append(requestVersions, req.Protocol)
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	t.Run("Signer supporting v2", func(t *testing.T) {
+		requestVersions = nil
+		server := httptest.NewServer(recordingHandler(remoteSigner.Handler()))
+		defer server.Close()
+
+		client := signer.NewExternalClient(server.URL, s.ClientAuth{})
+		capabilities, err := client.Negotiate()
+		require.NoError(t, err)
+		require.True(t, capabilities.VariableLengthSignatures)
+
+		res, err := client.HashAndSignTx(invokeTxnV3, chainID)
+		require.NoError(t, err)
+		require.Equal(t, s.ProtocolV2, res.Protocol)
+		require.Equal(t, []s.ProtocolVersion{s.ProtocolV2}, requestVersions)
+	})
+
+	t.Run("Signer without the capabilities endpoint", func(t *testing.T) {
+		requestVersions = nil
+		legacyMux := http.NewServeMux()
+		legacyMux.Handle(s.SignEndpoint, remoteSigner.Handler())
+		server := httptest.NewServer(recordingHandler(legacyMux))
+		defer server.Close()
+
+		client := signer.NewExternalClient(server.URL, s.ClientAuth{})
+		capabilities, err := client.Negotiate()
+		require.NoError(t, err)
+		require.Equal(t, s.V1Capabilities(), capabilities)
+
+		_, err = client.HashAndSignTx(invokeTxnV3, chainID)
+		require.NoError(t, err)
+		require.Equal(t, []s.ProtocolVersion{0}, requestVersions)
+	})
+
+	t.Run("No protocol version in common", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_, err := w.Write([]byte(`{"protocol_versions": [3]}`))
+				require.NoError(t, err)
+			}))
+		defer server.Close()
+
+		client := signer.NewExternalClient(server.URL, s.ClientAuth{})
+		_, err := client.Negotiate()
+		require.ErrorContains(t, err, "no protocol version in common")
+	})
+}
+
+func TestExternalClientUnixSocket(t *testing.T) {
+	remoteSigner, err := s.New("0x123", utils.NewNopZapLogger())
+	require.NoError(t, err)
+
+	socketPath := filepath.Join(t.TempDir(), "signer.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	server := httptest.NewUnstartedServer(remoteSigner.Handler())
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+	require.NoError(t, os.Chmod(socketPath, s.SocketFileMode))
+
+	invokeTxnV3 := newSignableTxn(t)
+	chainID := new(felt.Felt).SetUint64(1)
+
+	t.Run("Sign through the socket", func(t *testing.T) {
+		res, err := signer.HashAndSignTx(invokeTxnV3, chainID, s.UnixSocketScheme+socketPath)
+		require.NoError(t, err)
+		require.Len(t, res.Signature, 2)
+	})
+
+	t.Run("Socket writable by any user", func(t *testing.T) {
+		require.NoError(t, os.Chmod(socketPath, 0o666))
+		defer func() { require.NoError(t, os.Chmod(socketPath, s.SocketFileMode)) }()
+
+		_, err := signer.HashAndSignTx(invokeTxnV3, chainID, s.UnixSocketScheme+socketPath)
+		require.ErrorContains(t, err, "refusing to use the signer socket")
+	})
+
+	t.Run("TLS over the socket", func(t *testing.T) {
+		client := signer.NewExternalClient(s.UnixSocketScheme+socketPath, s.ClientAuth{})
+		err := client.SetTLSConfig(&s.ClientTLSConfig{CAFile: "ca.pem"})
+		require.ErrorContains(t, err, "tls is not supported over a unix socket")
+	})
+}
+
+func TestExternalClientGRPC(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+	invokeTxnV3 := newSignableTxn(t)
+	chainID := new(felt.Felt).SetUint64(1)
+
+	remoteSigner, err := s.NewWithAccountKeys([]s.AccountKey{
+		{Account: *invokeTxnV3.SenderAddress, PrivateKey: big.NewInt(0x123), Enabled: true},
+	}, logger)
+	require.NoError(t, err)
+	authConfig := s.AuthConfig{Clients: []s.ClientCredential{
+		{ID: "validator", Token: secret.New("some token")},
+	}}
+	remoteSigner.SetAuthenticator(s.NewAuthenticator(&authConfig, time.Minute, logger))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := remoteSigner.GRPCServer()
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+	signerURL := s.GRPCScheme + listener.Addr().String()
+
+	publicKey, _ := curve.PrivateKeyToPoint(big.NewInt(0x123))
+
+	t.Run("Sign over grpc", func(t *testing.T) {
+		client := signer.NewExternalClient(signerURL, s.ClientAuth{Token: "some token"})
+		defer client.Close()
+		client.SetPublicKey(new(felt.Felt).SetBigInt(publicKey))
+
+		capabilities, err := client.Negotiate()
+		require.NoError(t, err)
+		require.True(t, capabilities.VariableLengthSignatures)
+
+		res, err := client.HashAndSignTx(invokeTxnV3, chainID)
+		require.NoError(t, err)
+		require.Equal(t, s.ProtocolV2, res.Protocol)
+
+		publicKeys, err := client.PublicKeys()
+		require.NoError(t, err)
+		require.True(t, publicKeys.For(invokeTxnV3.SenderAddress).Enabled)
+
+		require.NoError(t, client.Ready())
+	})
+
+	t.Run("Wrong token", func(t *testing.T) {
+		client := signer.NewExternalClient(signerURL, s.ClientAuth{Token: "wrong token"})
+		defer client.Close()
+
+		_, err := client.HashAndSignTx(invokeTxnV3, chainID)
+		require.ErrorContains(t, err, "invalid bearer token")
+	})
+
+	t.Run("HMAC authentication", func(t *testing.T) {
+		client := signer.NewExternalClient(
+			signerURL, s.ClientAuth{ClientID: "validator", HMACKey: "some key"},
+		)
+		defer client.Close()
+
+		_, err := client.HashAndSignTx(invokeTxnV3, chainID)
+		require.ErrorContains(t, err, "hmac authentication is not supported over grpc")
+	})
+}
@@ -0,0 +1,222 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	junoUtils "github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/config"
+	"github.com/NethermindEth/starknet-staking-v2/validator/metrics"
+)
+
+// Implemented by the signers reporting metrics of their own
+type Traced interface {
+	SetTracer(tracer metrics.Tracer)
+}
+
+// One of the redundant external signers holding the operational account key
+type signerEndpoint struct {
+	url    string
+	client ExternalClient
+	// Cleared when a request or a health check fails, set again when one succeeds
+	healthy atomic.Bool
+
+	// Guards the fields below. Requests to the same signer are sent one at a time
+	mu sync.Mutex
+	// Set once the protocol is negotiated and the signer key checked
+	prepared bool
+}
+
+// External signers in priority order. Requests are sent to the first healthy one and to
+// the following ones when it fails
+type signerPool struct {
+	endpoints           []*signerEndpoint
+	healthCheckInterval time.Duration
+	logger              *junoUtils.ZapLogger
+
+	tracerMu sync.RWMutex
+	tracer   metrics.Tracer
+}
+
+func newSignerPool(sig *config.Signer, logger *junoUtils.ZapLogger) (*signerPool, error) {
+	healthCheckInterval, err := sig.SignerHealthCheckInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	urls := sig.SignerURLs()
+	if len(urls) == 0 {
+		return nil, errors.New("external url is not set for the external signer")
+	}
+	endpoints := make([]*signerEndpoint, len(urls))
+	for i, url := range urls {
+		client, err := externalClientFromConfig(url, sig)
+		if err != nil {
+			return nil, err
+		}
+		//nolint:exhaustruct // Healthy and not prepared until used
+		endpoints[i] = &signerEndpoint{url: url, client: client}
+		endpoints[i].healthy.Store(true)
+	}
+
+	return &signerPool{
+		endpoints:           endpoints,
+		healthCheckInterval: healthCheckInterval,
+		logger:              logger,
+		tracerMu:            sync.RWMutex{},
+		tracer:              metrics.NewNoOpMetrics(),
+	}, nil
+}
+
+func (p *signerPool) redundant() bool {
+	return len(p.endpoints) > 1
+}
+
+func (p *signerPool) setTracer(tracer metrics.Tracer) {
+	p.tracerMu.Lock()
+	defer p.tracerMu.Unlock()
+
+	p.tracer = tracer
+}
+
+func (p *signerPool) tracerOf() metrics.Tracer {
+	p.tracerMu.RLock()
+	defer p.tracerMu.RUnlock()
+
+	return p.tracer
+}
+
+// Tells which signer failed when there are several of them
+func (p *signerPool) wrap(endpoint *signerEndpoint, err error) error {
+	if !p.redundant() {
+		return err
+	}
+
+	return fmt.Errorf("external signer %s: %w", endpoint.url, err)
+}
+
+// Returns the healthy signers followed by the unhealthy ones, both in priority order.
+// Unhealthy signers are still tried as a last resort.
+func (p *signerPool) byPriority() []*signerEndpoint {
+	ordered := make([]*signerEndpoint, 0, len(p.endpoints))
+	for _, healthy := range []bool{true, false} {
+		for _, endpoint := range p.endpoints {
+			if endpoint.healthy.Load() == healthy {
+				ordered = append(ordered, endpoint)
+			}
+		}
+	}
+
+	return ordered
+}
+
+func (p *signerPool) setHealthy(endpoint *signerEndpoint, healthy bool, err error) {
+	if endpoint.healthy.Swap(healthy) != healthy {
+		if healthy {
+			p.logger.Infof("external signer %s is healthy again", endpoint.url)
+		} else {
+			p.logger.Warnw("external signer is unhealthy", "signer", endpoint.url, "error", err)
+		}
+	}
+	p.tracerOf().UpdateExternalSignerHealth(endpoint.url, healthy)
+}
+
+func (p *signerPool) signed(endpoint *signerEndpoint) {
+	p.setHealthy(endpoint, true, nil)
+	p.tracerOf().RecordExternalSignature(endpoint.url)
+	if p.redundant() {
+		p.logger.Infow("transaction signed", "signer", endpoint.url)
+	} else {
+		p.logger.Debugw("transaction signed", "signer", endpoint.url)
+	}
+}
+
+func (p *signerPool) failed(endpoint *signerEndpoint, err error) {
+	p.setHealthy(endpoint, false, err)
+	p.tracerOf().RecordExternalSignerFailure(endpoint.url)
+}
+
+// Checks the readiness of every signer until the context is done, so that requests are
+// sent to the healthy ones first
+func (p *signerPool) monitor(ctx context.Context) {
+	ticker := time.NewTicker(p.healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, endpoint := range p.endpoints {
+			err := endpoint.client.Ready()
+			p.setHealthy(endpoint, err == nil, err)
+		}
+	}
+}
+
+// Reports the signatures and the failures of the external signers to the tracer
+func (s *ExternalSigner) SetTracer(tracer metrics.Tracer) {
+	s.signers.setTracer(tracer)
+}
+
+// Checks the health of redundant signers every health check interval until the signer
+// context is done. Returns right away with a single signer.
+func (s *ExternalSigner) MonitorSigners() {
+	if !s.signers.redundant() {
+		return
+	}
+	s.signers.monitor(s.ctx)
+}
+
+// Sends the request to the signers in priority order until one of them signs it.
+// Requests refused by a signer policy are not sent to the following ones, which would
+// otherwise sign what it refused.
+func (s *ExternalSigner) sign(
+	request func(client *ExternalClient) (signer.Response, error),
+) (signer.Response, error) {
+	var errs []error
+	for _, endpoint := range s.signers.byPriority() {
+		signResp, err := s.signWith(endpoint, request)
+		if err == nil {
+			return signResp, nil
+		}
+		if _, ok := signer.AsPolicyViolation(err); ok {
+			return signer.Response{}, err
+		}
+		errs = append(errs, s.signers.wrap(endpoint, err))
+	}
+
+	return signer.Response{}, errors.Join(errs...)
+}
+
+func (s *ExternalSigner) signWith(
+	endpoint *signerEndpoint,
+	request func(client *ExternalClient) (signer.Response, error),
+) (signer.Response, error) {
+	endpoint.mu.Lock()
+	defer endpoint.mu.Unlock()
+
+	if err := s.prepare(endpoint, s.signers.logger); err != nil {
+		s.signers.failed(endpoint, err)
+
+		return signer.Response{}, err
+	}
+
+	signResp, err := request(&endpoint.client)
+	if err != nil {
+		if _, ok := signer.AsPolicyViolation(err); !ok {
+			s.signers.failed(endpoint, err)
+		}
+
+		return signer.Response{}, err
+	}
+	s.signers.signed(endpoint)
+
+	return signResp, nil
+}
@@ -0,0 +1,326 @@
+package signer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/utils"
+	s "github.com/NethermindEth/starknet-staking-v2/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/config"
+	"github.com/NethermindEth/starknet-staking-v2/validator/metrics"
+	"github.com/NethermindEth/starknet-staking-v2/validator/signer"
+	"github.com/NethermindEth/starknet.go/curve"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// Records the signers reported to the tracer
+type signerTracer struct {
+	metrics.NoOpMetrics
+
+	mu         sync.Mutex
+	signatures []string
+	failures   []string
+}
+
+func (t *signerTracer) RecordExternalSignature(signerURL string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.signatures = append(t.signatures, signerURL)
+}
+
+func (t *signerTracer) RecordExternalSignerFailure(signerURL string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.failures = append(t.failures, signerURL)
+}
+
+// Answers the chain id
+func chainIDRPCServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		var result string
+		switch req.Method {
+		case "starknet_chainId":
+			result = `"0x534e5f5345504f4c4941"`
+		case "starknet_specVersion":
+			result = `"v0.9.0"`
+		default:
+			http.Error(w, "unexpected method "+req.Method, http.StatusBadRequest)
+
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"jsonrpc": "2.0", "result": %s, "id": %s}`, result, req.ID)
+	}))
+}
+
+func TestExternalSignerFailover(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+	operationalAddress := utils.HexToFelt(t, "0x123")
+
+	mockRPC := chainIDRPCServer(t)
+	defer mockRPC.Close()
+	provider, err := rpc.NewProvider(t.Context(), mockRPC.URL)
+	require.NoError(t, err)
+
+	publicKey, _ := curve.PrivateKeyToPoint(big.NewInt(0x123))
+
+	// Serves a signer holding the operational account key, counting the sign requests
+	serve := func(
+		t *testing.T, policy *s.Policy, delay time.Duration,
+	) (*httptest.Server, *atomic.Int32) {
+		t.Helper()
+
+		remoteSigner, err := s.NewWithAccountKeys([]s.AccountKey{
+			{Account: *operationalAddress, PrivateKey: big.NewInt(0x123), Enabled: true},
+		}, logger)
+		require.NoError(t, err)
+		if policy != nil {
+			remoteSigner.SetPolicy(policy)
+		}
+		handler := remoteSigner.Handler()
+
+		var signRequests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == s.SignEndpoint {
+					signRequests.Add(1)
+					time.Sleep(delay)
+				}
+				handler.ServeHTTP(w, r)
+			}))
+		t.Cleanup(server.Close)
+
+		return server, &signRequests
+	}
+
+	down := func(t *testing.T) string {
+		t.Helper()
+
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		return server.URL
+	}
+
+	newExternalSigner := func(
+		t *testing.T, urls []string, requestTimeout string,
+	) (*signer.ExternalSigner, *signerTracer) {
+		t.Helper()
+
+		externalSigner, err := signer.NewExternalSigner(
+			t.Context(),
+			provider,
+			logger,
+			&config.Signer{
+				ExternalURL:        urls[0],
+				ExternalURLs:       urls[1:],
+				RequestTimeout:     requestTimeout,
+				OperationalAddress: operationalAddress.String(),
+				PublicKey:          "0x" + publicKey.Text(16),
+			},
+			new(config.ContractAddresses).SetDefaults("SN_SEPOLIA"),
+			false,
+		)
+		require.NoError(t, err)
+		//nolint:exhaustruct // Nothing recorded yet
+		tracer := &signerTracer{}
+		externalSigner.SetTracer(tracer)
+
+		return &externalSigner, tracer
+	}
+
+	sign := func(t *testing.T, externalSigner *signer.ExternalSigner) error {
+		t.Helper()
+
+		txn := newSignableTxn(t)
+		txn.SenderAddress = operationalAddress
+		_, err := externalSigner.SignTransaction(txn, 1)
+		if err == nil {
+			require.Len(t, txn.Signature, 2)
+		}
+
+		return err
+	}
+
+	t.Run("Attestations are signed for the epoch of the caller", func(t *testing.T) {
+		remoteSigner, err := s.NewWithAccountKeys([]s.AccountKey{
+			{Account: *operationalAddress, PrivateKey: big.NewInt(0x123), Enabled: true},
+		}, logger)
+		require.NoError(t, err)
+		handler := remoteSigner.Handler()
+
+		var epochID atomic.Uint64
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == s.SignEndpoint {
+					body, _ := io.ReadAll(r.Body)
+					var req s.Request
+					if json.Unmarshal(body, &req) == nil && req.EpochID != nil {
+						epochID.Store(*req.EpochID)
+					}
+					r.Body = io.NopCloser(bytes.NewReader(body))
+				}
+				handler.ServeHTTP(w, r)
+			}))
+		t.Cleanup(server.Close)
+		externalSigner, _ := newExternalSigner(t, []string{server.URL}, "")
+		require.NoError(t, externalSigner.Prepare(logger))
+
+		txn := newSignableTxn(t)
+		txn.SenderAddress = operationalAddress
+		_, err = externalSigner.SignTransaction(txn, 42)
+		require.NoError(t, err)
+		require.Equal(t, uint64(42), epochID.Load())
+	})
+
+	t.Run("First signer signs when healthy", func(t *testing.T) {
+		primary, primaryRequests := serve(t, nil, 0)
+		backup, backupRequests := serve(t, nil, 0)
+		externalSigner, tracer := newExternalSigner(t, []string{primary.URL, backup.URL}, "")
+		require.NoError(t, externalSigner.Prepare(logger))
+
+		require.NoError(t, sign(t, externalSigner))
+		require.Equal(t, int32(1), primaryRequests.Load())
+		require.Zero(t, backupRequests.Load())
+		require.Equal(t, []string{primary.URL}, tracer.signatures)
+	})
+
+	t.Run("Next signer signs when the first one goes down", func(t *testing.T) {
+		primary, _ := serve(t, nil, 0)
+		backup, backupRequests := serve(t, nil, 0)
+		externalSigner, tracer := newExternalSigner(t, []string{primary.URL, backup.URL}, "")
+		require.NoError(t, externalSigner.Prepare(logger))
+		primary.Close()
+
+		require.NoError(t, sign(t, externalSigner))
+		require.Equal(t, int32(1), backupRequests.Load())
+		require.Equal(t, []string{primary.URL}, tracer.failures)
+		require.Equal(t, []string{backup.URL}, tracer.signatures)
+	})
+
+	t.Run("Starts with the first signer down", func(t *testing.T) {
+		primary := down(t)
+		backup, backupRequests := serve(t, nil, 0)
+		externalSigner, tracer := newExternalSigner(t, []string{primary, backup.URL}, "")
+		require.NoError(t, externalSigner.Prepare(logger))
+
+		// The unhealthy signer is only tried after the healthy ones
+		require.NoError(t, sign(t, externalSigner))
+		require.Equal(t, int32(1), backupRequests.Load())
+		require.Empty(t, tracer.failures)
+		require.Equal(t, []string{backup.URL}, tracer.signatures)
+	})
+
+	t.Run("Next signer signs when the first one times out", func(t *testing.T) {
+		primary, primaryRequests := serve(t, nil, time.Second)
+		backup, backupRequests := serve(t, nil, 0)
+		externalSigner, tracer := newExternalSigner(
+			t, []string{primary.URL, backup.URL}, "100ms",
+		)
+		require.NoError(t, externalSigner.Prepare(logger))
+
+		require.NoError(t, sign(t, externalSigner))
+		require.Equal(t, int32(1), primaryRequests.Load())
+		require.Equal(t, int32(1), backupRequests.Load())
+		require.Equal(t, []string{primary.URL}, tracer.failures)
+		require.Equal(t, []string{backup.URL}, tracer.signatures)
+
+		// The unhealthy signer is only tried after the healthy ones
+		require.NoError(t, sign(t, externalSigner))
+		require.Equal(t, int32(1), primaryRequests.Load())
+		require.Equal(t, int32(2), backupRequests.Load())
+	})
+
+	t.Run("Request refused by the policy is not sent to the next signer", func(t *testing.T) {
+		policy, err := s.NewPolicy(&s.PolicyConfig{AttestContracts: []string{"0x456"}})
+		require.NoError(t, err)
+		primary, primaryRequests := serve(t, policy, 0)
+		backup, backupRequests := serve(t, nil, 0)
+		externalSigner, tracer := newExternalSigner(t, []string{primary.URL, backup.URL}, "")
+		require.NoError(t, externalSigner.Prepare(logger))
+
+		err = sign(t, externalSigner)
+		_, ok := s.AsPolicyViolation(err)
+		require.True(t, ok)
+		require.Equal(t, int32(1), primaryRequests.Load())
+		require.Zero(t, backupRequests.Load())
+		require.Empty(t, tracer.failures)
+	})
+
+	t.Run("All signers down", func(t *testing.T) {
+		primary := down(t)
+		backup := down(t)
+		externalSigner, tracer := newExternalSigner(t, []string{primary, backup}, "")
+		require.Error(t, externalSigner.Prepare(logger))
+
+		err := sign(t, externalSigner)
+		require.ErrorContains(t, err, "external signer "+primary)
+		require.ErrorContains(t, err, "external signer "+backup)
+		require.Equal(t, []string{primary, backup}, tracer.failures)
+	})
+}
+
+func TestExternalClientReady(t *testing.T) {
+	remoteSigner, err := s.New("0x123", utils.NewNopZapLogger())
+	require.NoError(t, err)
+
+	t.Run("Ready signer", func(t *testing.T) {
+		server := httptest.NewServer(remoteSigner.Handler())
+		defer server.Close()
+
+		//nolint:exhaustruct // No authentication
+		client := signer.NewExternalClient(server.URL, s.ClientAuth{})
+		require.NoError(t, client.Ready())
+	})
+
+	t.Run("Signer without the readiness endpoint", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		//nolint:exhaustruct // No authentication
+		client := signer.NewExternalClient(server.URL, s.ClientAuth{})
+		require.NoError(t, client.Ready())
+	})
+
+	t.Run("Signer not ready", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, `{"status": "not ready"}`, http.StatusServiceUnavailable)
+			}))
+		defer server.Close()
+
+		//nolint:exhaustruct // No authentication
+		client := signer.NewExternalClient(server.URL, s.ClientAuth{})
+		require.ErrorContains(t, client.Ready(), "signer not ready 503")
+	})
+
+	t.Run("Signer down", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		//nolint:exhaustruct // No authentication
+		client := signer.NewExternalClient(server.URL, s.ClientAuth{})
+		require.Error(t, client.Ready())
+	})
+}
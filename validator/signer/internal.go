@@ -7,6 +7,7 @@ import (
 
 	"github.com/NethermindEth/juno/core/felt"
 	junoUtils "github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/keystore"
 	"github.com/NethermindEth/starknet-staking-v2/validator/config"
 	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
 	"github.com/NethermindEth/starknet-staking-v2/validator/types"
@@ -17,11 +18,19 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
-var _ Signer = (*InternalSigner)(nil)
+var (
+	_ Signer          = (*InternalSigner)(nil)
+	_ AccountDeployer = (*InternalSigner)(nil)
+	_ Invoker         = (*InternalSigner)(nil)
+)
+
+// Environment variable read for the keystore password when no password file is set
+const KeystorePasswordEnv = "SIGNER_KEYSTORE_PASSWORD"
 
 type InternalSigner struct {
-	ctx     context.Context
-	Account account.Account
+	ctx       context.Context
+	Account   account.Account
+	publicKey *felt.Felt
 	// If the account used represents a braavos account
 	braavos             bool
 	validationContracts types.ValidationContracts
@@ -35,17 +44,67 @@ func NewInternalSigner(
 	addresses *config.ContractAddresses,
 	braavos bool,
 ) (InternalSigner, error) {
-	privateKey, ok := new(big.Int).SetString(signer.PrivKey, 0)
+	privateKey, ok := new(big.Int).SetString(signer.PrivKey.Reveal(), 0)
 	if !ok {
-		return InternalSigner{},
-			errors.Errorf("cannot turn private key %s into a big int", privateKey)
+		// The private key value is purposely left out of the error
+		return InternalSigner{}, errors.New("cannot turn private key into a big int")
+	}
+
+	return newInternalSigner(
+		ctx, provider, logger, privateKey, signer.OperationalAddress, addresses, braavos,
+	)
+}
+
+// Creates an internal signer whose private key is stored in an encrypted keystore file.
+// The keystore password is taken from the configured password, the configured password
+// file, the `SIGNER_KEYSTORE_PASSWORD` env var or typed in interactively, in that order
+func NewKeystoreSigner(
+	ctx context.Context,
+	provider *rpc.Provider,
+	logger *junoUtils.ZapLogger,
+	signer *config.Signer,
+	addresses *config.ContractAddresses,
+	braavos bool,
+) (InternalSigner, error) {
+	password := signer.KeystorePassword.Reveal()
+	if signer.KeystorePassword.IsZero() {
+		passwordSource := keystore.PasswordSource{
+			File:   signer.KeystorePasswordFile,
+			Env:    KeystorePasswordEnv,
+			Prompt: fmt.Sprintf("Enter password for keystore %s: ", signer.Keystore),
+		}
+		var err error
+		password, err = passwordSource.Read()
+		if err != nil {
+			return InternalSigner{}, err
+		}
 	}
 
+	privateKey, err := keystore.Open(signer.Keystore, password)
+	if err != nil {
+		return InternalSigner{}, errors.Errorf("cannot open keystore %s: %w", signer.Keystore, err)
+	}
+	logger.Infof("loaded signing key from keystore %s", signer.Keystore)
+
+	return newInternalSigner(
+		ctx, provider, logger, privateKey, signer.OperationalAddress, addresses, braavos,
+	)
+}
+
+func newInternalSigner(
+	ctx context.Context,
+	provider *rpc.Provider,
+	logger *junoUtils.ZapLogger,
+	privateKey *big.Int,
+	operationalAddress string,
+	addresses *config.ContractAddresses,
+	braavos bool,
+) (InternalSigner, error) {
 	publicKey, _ := curve.PrivateKeyToPoint(privateKey)
 	publicKeyStr := publicKey.String()
 	ks := account.SetNewMemKeystore(publicKeyStr, privateKey)
 
-	accountAddr := types.AddressFromString(signer.OperationalAddress)
+	accountAddr := types.AddressFromString(operationalAddress)
 	acc, err := account.NewAccount(provider, accountAddr.Felt(), publicKeyStr, ks, 2)
 	if err != nil {
 		return InternalSigner{}, errors.Errorf("cannot create internal signer: %w", err)
@@ -65,6 +124,7 @@ func NewInternalSigner(
 	return InternalSigner{
 		ctx:                 ctx,
 		Account:             *acc,
+		publicKey:           new(felt.Felt).SetBigInt(publicKey),
 		braavos:             braavos,
 		validationContracts: validationContracts,
 	}, nil
@@ -79,11 +139,16 @@ func (s *InternalSigner) TransactionStatus(transactionHash *felt.Felt) (
 func (s *InternalSigner) BuildAttestTransaction(
 	blockhash *types.BlockHash,
 ) (rpc.BroadcastInvokeTxnV3, error) {
-	calls := []rpc.InvokeFunctionCall{{
+	return s.BuildInvokeTransaction([]rpc.InvokeFunctionCall{{
 		ContractAddress: s.ValidationContracts().Attest.Felt(),
 		FunctionName:    "attest",
 		CallData:        []*felt.Felt{blockhash.Felt().Clone()},
-	}}
+	}})
+}
+
+func (s *InternalSigner) BuildInvokeTransaction(
+	calls []rpc.InvokeFunctionCall,
+) (rpc.BroadcastInvokeTxnV3, error) {
 	calldata, err := s.Account.FmtCalldata(utils.InvokeFuncCallsToFunctionCalls(calls))
 	if err != nil {
 		return rpc.BroadcastInvokeTxnV3{}, fmt.Errorf("failed to format calldata: %w", err)
@@ -94,7 +159,7 @@ func (s *InternalSigner) BuildAttestTransaction(
 		return rpc.BroadcastInvokeTxnV3{}, fmt.Errorf("failed to update the account nonce: %w", err)
 	}
 
-	defaultResources := makeDefaultResources()
+	defaultResources := MakeDefaultResources()
 
 	tip, err := rpc.EstimateTip(s.ctx, s.Account.Provider, constants.TipMultiplier)
 	if err != nil {
@@ -102,7 +167,7 @@ func (s *InternalSigner) BuildAttestTransaction(
 	}
 
 	// Taken from starknet.go `utils.BuildInvokeTxn`
-	attestTransaction := rpc.BroadcastInvokeTxnV3{
+	invokeTransaction := rpc.BroadcastInvokeTxnV3{
 		Type:                  rpc.TransactionTypeInvoke,
 		SenderAddress:         s.Account.Address,
 		Calldata:              calldata,
@@ -117,7 +182,7 @@ func (s *InternalSigner) BuildAttestTransaction(
 		FeeMode:               rpc.DAModeL1,
 	}
 
-	return attestTransaction, nil
+	return invokeTransaction, nil
 }
 
 func (s *InternalSigner) EstimateFee(txn *rpc.BroadcastInvokeTxnV3) (rpc.FeeEstimation, error) {
@@ -126,7 +191,7 @@ func (s *InternalSigner) EstimateFee(txn *rpc.BroadcastInvokeTxnV3) (rpc.FeeEsti
 		// The query bit txn version is used for custom validation logic from wallets/accounts
 		// when estimating fee/simulating txns
 		txn.Version = rpc.TransactionV3WithQueryBit
-		_, err := s.SignTransaction(txn)
+		err := s.SignInvokeTransaction(txn)
 		if err != nil {
 			return rpc.FeeEstimation{}, err
 		}
@@ -150,12 +215,17 @@ func (s *InternalSigner) EstimateFee(txn *rpc.BroadcastInvokeTxnV3) (rpc.FeeEsti
 	return estimateFee[0], nil
 }
 
+// The epoch is only used by external signers
 func (s *InternalSigner) SignTransaction(
-	txn *rpc.BroadcastInvokeTxnV3,
+	txn *rpc.BroadcastInvokeTxnV3, _ uint64,
 ) (*rpc.BroadcastInvokeTxnV3, error) {
 	return txn, s.Account.SignInvokeTransaction(s.ctx, txn)
 }
 
+func (s *InternalSigner) SignInvokeTransaction(txn *rpc.BroadcastInvokeTxnV3) error {
+	return s.Account.SignInvokeTransaction(s.ctx, txn)
+}
+
 func (s *InternalSigner) InvokeTransaction(
 	txn *rpc.BroadcastInvokeTxnV3,
 ) (rpc.AddInvokeTransactionResponse, error) {
@@ -8,11 +8,15 @@ import (
 	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/keystore"
 	"github.com/NethermindEth/starknet-staking-v2/mocks"
+	"github.com/NethermindEth/starknet-staking-v2/secret"
 	signerP "github.com/NethermindEth/starknet-staking-v2/signer"
 	"github.com/NethermindEth/starknet-staking-v2/validator"
 	"github.com/NethermindEth/starknet-staking-v2/validator/config"
@@ -56,10 +60,7 @@ func TestNewInternalSigner(t *testing.T) {
 		)
 
 		require.Equal(t, signer.InternalSigner{}, validatorAccount)
-		expectedErrorMsg := fmt.Sprintf(
-			"cannot turn private key %s into a big int", (*big.Int)(nil),
-		)
-		require.Equal(t, expectedErrorMsg, err.Error())
+		require.EqualError(t, err, "cannot turn private key into a big int")
 	})
 	t.Run("Successful account creation", func(t *testing.T) {
 		if !loadedEnvVars {
@@ -70,7 +71,7 @@ func TestNewInternalSigner(t *testing.T) {
 		require.NoError(t, err)
 
 		configSigner := config.Signer{
-			PrivKey:            "0x123",
+			PrivKey:            secret.New("0x123"),
 			OperationalAddress: "0x456",
 		}
 
@@ -102,6 +103,98 @@ func TestNewInternalSigner(t *testing.T) {
 	})
 }
 
+func TestNewKeystoreSigner(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+	contractAddresses := new(config.ContractAddresses).SetDefaults("SN_SEPOLIA")
+
+	keystorePath := filepath.Join(t.TempDir(), "keystore.json")
+	ks, err := keystore.Encrypt(big.NewInt(0x123), "password", keystore.LightScryptN)
+	require.NoError(t, err)
+	require.NoError(t, ks.Save(keystorePath))
+
+	t.Run("Error: password not provided", func(t *testing.T) {
+		_, err := signer.NewKeystoreSigner(
+			t.Context(),
+			nil,
+			logger,
+			&config.Signer{Keystore: keystorePath, OperationalAddress: "0x456"},
+			contractAddresses,
+			false,
+		)
+		require.ErrorContains(t, err, "keystore password not provided")
+	})
+
+	t.Run("Error: wrong keystore password", func(t *testing.T) {
+		t.Setenv(signer.KeystorePasswordEnv, "wrong password")
+
+		_, err := signer.NewKeystoreSigner(
+			t.Context(),
+			nil,
+			logger,
+			&config.Signer{Keystore: keystorePath, OperationalAddress: "0x456"},
+			contractAddresses,
+			false,
+		)
+		require.ErrorIs(t, err, keystore.ErrDecrypt)
+	})
+
+	t.Run("Successful signer creation", func(t *testing.T) {
+		passwordFile := filepath.Join(t.TempDir(), "password")
+		require.NoError(t, os.WriteFile(passwordFile, []byte("password"), 0o600))
+
+		mockRPC := validator.MockRPCServer(t, utils.HexToFelt(t, "0x456"), "")
+		defer mockRPC.Close()
+		provider, err := rpc.NewProvider(t.Context(), mockRPC.URL)
+		require.NoError(t, err)
+
+		keystoreSigner, err := signer.NewKeystoreSigner(
+			t.Context(),
+			provider,
+			logger,
+			&config.Signer{
+				Keystore:             keystorePath,
+				KeystorePasswordFile: passwordFile,
+				OperationalAddress:   "0x456",
+			},
+			contractAddresses,
+			false,
+		)
+		require.NoError(t, err)
+
+		// This is the public key for private key "0x123"
+		publicKey := "2443263864760624031255983690848140455871762770061978316256189704907682682390"
+		ks := account.SetNewMemKeystore(publicKey, big.NewInt(0x123))
+		expectedAccount, err := account.NewAccount(
+			provider, utils.HexToFelt(t, "0x456"), publicKey, ks, 2,
+		)
+		require.NoError(t, err)
+		require.Equal(t, expectedAccount, &keystoreSigner.Account)
+	})
+
+	t.Run("Configured password read before the env var", func(t *testing.T) {
+		t.Setenv(signer.KeystorePasswordEnv, "wrong password")
+
+		mockRPC := validator.MockRPCServer(t, utils.HexToFelt(t, "0x456"), "")
+		defer mockRPC.Close()
+		provider, err := rpc.NewProvider(t.Context(), mockRPC.URL)
+		require.NoError(t, err)
+
+		_, err = signer.NewKeystoreSigner(
+			t.Context(),
+			provider,
+			logger,
+			&config.Signer{
+				Keystore:           keystorePath,
+				KeystorePassword:   secret.New("password"),
+				OperationalAddress: "0x456",
+			},
+			contractAddresses,
+			false,
+		)
+		require.NoError(t, err)
+	})
+}
+
 // func createMockRPCServer(
 // 	t *testing.T, addInvoke func(w http.ResponseWriter, r *http.Request),
 // ) *httptest.Server {
@@ -0,0 +1,165 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	junoUtils "github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/validator/config"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+// Creates a signer backend out of the validator configuration
+type Constructor interface {
+	New(
+		ctx context.Context,
+		provider *rpc.Provider,
+		logger *junoUtils.ZapLogger,
+		signer *config.Signer,
+		addresses *config.ContractAddresses,
+		braavos bool,
+	) (Signer, error)
+}
+
+// Allows using ordinary functions as signer constructors
+type ConstructorFunc func(
+	ctx context.Context,
+	provider *rpc.Provider,
+	logger *junoUtils.ZapLogger,
+	signer *config.Signer,
+	addresses *config.ContractAddresses,
+	braavos bool,
+) (Signer, error)
+
+func (f ConstructorFunc) New(
+	ctx context.Context,
+	provider *rpc.Provider,
+	logger *junoUtils.ZapLogger,
+	signer *config.Signer,
+	addresses *config.ContractAddresses,
+	braavos bool,
+) (Signer, error) {
+	return f(ctx, provider, logger, signer, addresses, braavos)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[config.SignerType]Constructor{
+		config.InternalSigner: ConstructorFunc(newInternalBackend),
+		config.ExternalSigner: ConstructorFunc(newExternalBackend),
+		config.KeystoreSigner: ConstructorFunc(newKeystoreBackend),
+	}
+)
+
+// Makes a signer backend available under the given type. It fails if the type
+// is already taken by another backend
+func Register(signerType config.SignerType, constructor Constructor) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[signerType]; ok {
+		return fmt.Errorf("signer type %q is already registered", signerType)
+	}
+	registry[signerType] = constructor
+
+	return nil
+}
+
+// Returns the signer types a backend is registered for, sorted by name
+func RegisteredTypes() []config.SignerType {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return slices.Sorted(maps.Keys(registry))
+}
+
+func init() {
+	config.RegisteredSignerTypes = RegisteredTypes
+}
+
+// Creates the signer backend selected by the signer configuration
+func New(
+	ctx context.Context,
+	provider *rpc.Provider,
+	logger *junoUtils.ZapLogger,
+	signer *config.Signer,
+	addresses *config.ContractAddresses,
+	braavos bool,
+) (Signer, error) {
+	signerType, err := signer.ResolveType()
+	if err != nil {
+		return nil, err
+	}
+
+	registryMu.RLock()
+	constructor, ok := registry[signerType]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no signer backend registered for type %q", signerType)
+	}
+
+	return constructor.New(ctx, provider, logger, signer, addresses, braavos)
+}
+
+func newInternalBackend(
+	ctx context.Context,
+	provider *rpc.Provider,
+	logger *junoUtils.ZapLogger,
+	signer *config.Signer,
+	addresses *config.ContractAddresses,
+	braavos bool,
+) (Signer, error) {
+	internalSigner, err := NewInternalSigner(ctx, provider, logger, signer, addresses, braavos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialise internal signer: %w", err)
+	}
+	logger.Info("using internal signer")
+
+	return &internalSigner, nil
+}
+
+func newExternalBackend(
+	ctx context.Context,
+	provider *rpc.Provider,
+	logger *junoUtils.ZapLogger,
+	signer *config.Signer,
+	addresses *config.ContractAddresses,
+	braavos bool,
+) (Signer, error) {
+	externalSigner, err := NewExternalSigner(ctx, provider, logger, signer, addresses, braavos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to external signer: %w", err)
+	}
+	if err := externalSigner.Prepare(logger); err != nil {
+		return nil, err
+	}
+	if urls := signer.SignerURLs(); len(urls) > 1 {
+		logger.Infof("using external signers at %s, in that order", strings.Join(urls, ", "))
+		go externalSigner.MonitorSigners()
+	} else {
+		logger.Infof("using external signer at %s", urls[0])
+	}
+
+	return &externalSigner, nil
+}
+
+func newKeystoreBackend(
+	ctx context.Context,
+	provider *rpc.Provider,
+	logger *junoUtils.ZapLogger,
+	signer *config.Signer,
+	addresses *config.ContractAddresses,
+	braavos bool,
+) (Signer, error) {
+	keystoreSigner, err := NewKeystoreSigner(ctx, provider, logger, signer, addresses, braavos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialise keystore signer: %w", err)
+	}
+	logger.Info("using internal signer with an encrypted keystore")
+
+	return &keystoreSigner, nil
+}
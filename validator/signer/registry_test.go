@@ -0,0 +1,117 @@
+package signer_test
+
+import (
+	"context"
+	"testing"
+
+	junoUtils "github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/mocks"
+	"github.com/NethermindEth/starknet-staking-v2/secret"
+	"github.com/NethermindEth/starknet-staking-v2/validator/config"
+	"github.com/NethermindEth/starknet-staking-v2/validator/signer"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRegistry(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	logger := junoUtils.NewNopZapLogger()
+	addresses := new(config.ContractAddresses).SetDefaults("SN_SEPOLIA")
+
+	t.Run("Built-in signer types cannot be registered again", func(t *testing.T) {
+		for _, signerType := range []config.SignerType{
+			config.InternalSigner, config.ExternalSigner, config.KeystoreSigner,
+		} {
+			err := signer.Register(signerType, signer.ConstructorFunc(nil))
+			require.ErrorContains(t, err, "already registered")
+		}
+	})
+
+	t.Run("Ambiguous configuration is rejected", func(t *testing.T) {
+		s, err := signer.New(
+			t.Context(),
+			nil,
+			logger,
+			&config.Signer{
+				ExternalURL:        "http://localhost:1234",
+				PrivKey:            secret.New("0x123"),
+				OperationalAddress: "0x456",
+			},
+			addresses,
+			false,
+		)
+		require.Nil(t, s)
+		require.ErrorContains(t, err, "ambiguous signer configuration")
+	})
+
+	t.Run("Unregistered signer type", func(t *testing.T) {
+		s, err := signer.New(
+			t.Context(),
+			nil,
+			logger,
+			&config.Signer{Type: "unregistered", OperationalAddress: "0x456"},
+			addresses,
+			false,
+		)
+		require.Nil(t, s)
+		require.ErrorContains(t, err, `no signer backend registered for type "unregistered"`)
+	})
+
+	t.Run("Registered signer type is used by its type", func(t *testing.T) {
+		const customType config.SignerType = "custom"
+		customSigner := mocks.NewMockSigner(mockCtrl)
+
+		err := signer.Register(customType, signer.ConstructorFunc(
+			func(
+				_ context.Context,
+				_ *rpc.Provider,
+				_ *junoUtils.ZapLogger,
+				conf *config.Signer,
+				_ *config.ContractAddresses,
+				_ bool,
+			) (signer.Signer, error) {
+				require.Equal(t, customType, conf.Type)
+
+				return customSigner, nil
+			},
+		))
+		require.NoError(t, err)
+
+		s, err := signer.New(
+			t.Context(),
+			nil,
+			logger,
+			&config.Signer{Type: customType, OperationalAddress: "0x456"},
+			addresses,
+			false,
+		)
+		require.NoError(t, err)
+		require.Equal(t, customSigner, s)
+	})
+
+	t.Run("Configuration accepts the registered signer types", func(t *testing.T) {
+		require.Equal(
+			t,
+			[]config.SignerType{
+				"custom", config.ExternalSigner, config.InternalSigner, config.KeystoreSigner,
+			},
+			signer.RegisteredTypes(),
+		)
+
+		//nolint:exhaustruct // Only specifying used fields
+		conf := config.Config{
+			Provider: config.Provider{
+				HTTP: secret.New("http://localhost:1234"), WS: secret.New("ws://localhost:1235"),
+			},
+			Signer: config.Signer{Type: "custom", OperationalAddress: "0x456"},
+		}
+		require.NoError(t, conf.Check())
+		conf.Signer.Type = "other"
+		require.ErrorContains(
+			t, conf.Check(), "expected one of: custom, external, internal, keystore",
+		)
+	})
+}
@@ -13,14 +13,18 @@ import (
 	"lukechampine.com/uint128"
 )
 
-//go:generate go tool mockgen -destination=../../mocks/mock_signer.go -package=mocks github.com/NethermindEth/starknet-staking-v2/validator/signer Signer
+//go:generate go tool mockgen -destination=../../mocks/mock_signer.go -package=mocks github.com/NethermindEth/starknet-staking-v2/validator/signer Signer,AccountDeployer,Invoker
 type Signer interface {
 	// Methods from Starknet.go Account implementation
 	TransactionStatus(transactionHash *felt.Felt) (*rpc.TxnStatusResult, error)
 
 	BuildAttestTransaction(blockHash *types.BlockHash) (rpc.BroadcastInvokeTxnV3, error)
 	EstimateFee(txn *rpc.BroadcastInvokeTxnV3) (rpc.FeeEstimation, error)
-	SignTransaction(txn *rpc.BroadcastInvokeTxnV3) (*rpc.BroadcastInvokeTxnV3, error)
+	// Signs the attestation of the epoch. An external signer records the epoch so it never
+	// signs a conflicting attestation for it
+	SignTransaction(
+		txn *rpc.BroadcastInvokeTxnV3, epochID uint64,
+	) (*rpc.BroadcastInvokeTxnV3, error)
 	InvokeTransaction(txn *rpc.BroadcastInvokeTxnV3) (rpc.AddInvokeTransactionResponse, error)
 
 	Call(call rpc.FunctionCall, blockID rpc.BlockID) ([]*felt.Felt, error)
@@ -32,6 +36,32 @@ type Signer interface {
 	ValidationContracts() *types.ValidationContracts
 }
 
+// Signer able to deploy the operational account, before it can attest
+type AccountDeployer interface {
+	Signer
+	// Public key the operational account is deployed with
+	AccountPublicKey() (*felt.Felt, error)
+	AccountDeployed() (bool, error)
+
+	BuildDeployAccountTransaction(
+		classHash, salt *felt.Felt, constructorCalldata []*felt.Felt,
+	) (rpc.BroadcastDeployAccountTxnV3, error)
+	EstimateDeployAccountFee(txn *rpc.BroadcastDeployAccountTxnV3) (rpc.FeeEstimation, error)
+	SignDeployAccountTransaction(txn *rpc.BroadcastDeployAccountTxnV3) error
+	DeployAccountTransaction(
+		txn *rpc.BroadcastDeployAccountTxnV3,
+	) (rpc.AddDeployAccountTransactionResponse, error)
+}
+
+// Signer able to send transactions other than attestations from its account, e.g. to
+// rotate the operational address
+type Invoker interface {
+	Signer
+	BuildInvokeTransaction(calls []rpc.InvokeFunctionCall) (rpc.BroadcastInvokeTxnV3, error)
+	// Signs the transaction, which isn't an attestation
+	SignInvokeTransaction(txn *rpc.BroadcastInvokeTxnV3) error
+}
+
 // I believe all these functions down here should be methods
 // Postponing for now to not affect test code
 
@@ -66,6 +96,63 @@ func FetchEpochInfo[S Signer](signer S) (types.EpochInfo, error) {
 	}, nil
 }
 
+// Reads the staker state from the staking contract, as returned by `staker_info_v1`
+func FetchStakerInfo[S Signer](signer S, staker *types.Address) (types.StakerInfo, error) {
+	const entrypoint = "staker_info_v1"
+	result, err := signer.Call(
+		rpc.FunctionCall{
+			ContractAddress:    signer.ValidationContracts().Staking.Felt(),
+			EntryPointSelector: utils.GetSelectorFromNameFelt(entrypoint),
+			Calldata:           []*felt.Felt{staker.Felt()},
+		},
+		rpc.WithBlockTag(rpc.BlockTagLatest),
+	)
+	if err != nil {
+		return types.StakerInfo{}, entrypointInternalError(entrypoint, err)
+	}
+
+	// Options are serialised as variant 0 followed by the value when set, and as
+	// variant 1 alone otherwise
+	isSome := func(i int) bool { return i < len(result) && result[i].IsZero() }
+	// Reward and operational addresses come first, then the unstake time
+	i := 2 //nolint:mnd // Index of the unstake time
+	if isSome(i) {
+		i++
+	}
+	i++
+	// Own stake and unclaimed rewards, then the pool info
+	if i+2 >= len(result) {
+		return types.StakerInfo{}, entrypointResponseError(entrypoint, result)
+	}
+	zero := new(felt.Felt)
+	stakerInfo := types.StakerInfo{
+		RewardAddress:      types.Address(*result[0]),
+		OperationalAddress: types.Address(*result[1]),
+		Stake:              types.NewBalance(result[i], zero),
+		UnclaimedRewards:   types.NewBalance(result[i+1], zero),
+		Pool:               nil,
+	}
+	i += 2
+	if !isSome(i) {
+		if i+1 != len(result) {
+			return types.StakerInfo{}, entrypointResponseError(entrypoint, result)
+		}
+
+		return stakerInfo, nil
+	}
+	// Pool contract, delegated amount and commission
+	if i+4 != len(result) { //nolint:mnd // Variant and pool info fields
+		return types.StakerInfo{}, entrypointResponseError(entrypoint, result)
+	}
+	stakerInfo.Pool = &types.PoolInfo{
+		Contract:   types.Address(*result[i+1]),
+		Amount:     types.NewBalance(result[i+2], zero),
+		Commission: result[i+3].Uint64(), //nolint:mnd // Index of the commission
+	}
+
+	return stakerInfo, nil
+}
+
 func FetchAttestWindow[S Signer](signer S) (uint64, error) {
 	result, err := signer.Call(
 		rpc.FunctionCall{
@@ -86,6 +173,35 @@ func FetchAttestWindow[S Signer](signer S) (uint64, error) {
 	return result[0].Uint64(), nil
 }
 
+// Reads the public key of the account signing the transactions. OpenZeppelin and Braavos
+// accounts expose it with `get_public_key`, Argent accounts with `get_owner`
+func FetchAccountPublicKey[S Signer](signer S) (*felt.Felt, error) {
+	var err error
+	for _, entrypoint := range []string{"get_public_key", "get_owner"} {
+		var result []*felt.Felt
+		result, err = signer.Call(
+			rpc.FunctionCall{
+				ContractAddress:    signer.Address().Felt(),
+				EntryPointSelector: utils.GetSelectorFromNameFelt(entrypoint),
+				Calldata:           []*felt.Felt{},
+			},
+			rpc.WithBlockTag(rpc.BlockTagLatest),
+		)
+		if err != nil {
+			err = entrypointInternalError(entrypoint, err)
+
+			continue
+		}
+		if len(result) != 1 {
+			return nil, entrypointResponseError(entrypoint, result)
+		}
+
+		return result[0], nil
+	}
+
+	return nil, err
+}
+
 // For near future when tracking validator's balance
 func FetchValidatorBalance[S Signer](signer S) (types.Balance, error) {
 	StrkTokenContract := types.AddressFromString(constants.StrkContractAddress)
@@ -146,15 +262,15 @@ func FetchEpochAndAttestInfo[S Signer](
 	return epochInfo, attestInfo, nil
 }
 
-func BuildAttest[S Signer](signer S, blockHash *types.BlockHash, multiplier float64) (
-	rpc.BroadcastInvokeTxnV3, error,
-) {
+func BuildAttest[S Signer](
+	signer S, blockHash *types.BlockHash, epochID uint64, multiplier float64,
+) (rpc.BroadcastInvokeTxnV3, error) {
 	txn, err := signer.BuildAttestTransaction(blockHash)
 	if err != nil {
 		return rpc.BroadcastInvokeTxnV3{}, err
 	}
 
-	_, err = signer.SignTransaction(&txn)
+	_, err = signer.SignTransaction(&txn, epochID)
 	if err != nil {
 		return rpc.BroadcastInvokeTxnV3{}, err
 	}
@@ -168,7 +284,7 @@ func BuildAttest[S Signer](signer S, blockHash *types.BlockHash, multiplier floa
 	// patch for making sure txn.Version is correct
 	txn.Version = rpc.TransactionV3
 
-	_, err = signer.SignTransaction(&txn)
+	_, err = signer.SignTransaction(&txn, epochID)
 	if err != nil {
 		return rpc.BroadcastInvokeTxnV3{}, err
 	}
@@ -0,0 +1,307 @@
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	junoUtils "github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/validator/config"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	"github.com/NethermindEth/starknet-staking-v2/validator/metrics"
+	signerP "github.com/NethermindEth/starknet-staking-v2/validator/signer"
+	"github.com/NethermindEth/starknet-staking-v2/validator/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+)
+
+const (
+	// Period the daily caps of the top-ups apply to
+	topUpCapPeriod = 24 * time.Hour
+	// Time a top-up has to be accepted before it is considered failed
+	topUpAcceptanceTimeout = 10 * time.Minute
+)
+
+// Tops up the operational account from a funding account, once its balance falls below
+// the balance threshold
+type Funder struct {
+	Signer signerP.Invoker
+	// Balance, in STRK, the operational account is topped up to
+	Target float64
+	// Most STRK sent in 24 hours
+	DailyCap float64
+	// Most top-ups sent in 24 hours. Not limited when zero
+	DailyTransfers uint64
+	// File the sent top-ups are kept in, so the daily caps hold across restarts. They are
+	// only kept in memory when it is not set
+	StateFile string
+	// Set while a top-up is ongoing, so it isn't sent twice
+	funding atomic.Bool
+	// Top-ups sent in the last 24 hours, oldest first. Only accessed while funding
+	sent []topUp
+}
+
+type topUp struct {
+	sentAt time.Time
+	amount types.Balance
+}
+
+// Top-up as written in the state file, with its amount in FRI
+type topUpState struct {
+	SentAt time.Time `json:"sentAt"`
+	Amount string    `json:"amount"`
+}
+
+// Creates the funder of the top-up configuration, signing through the provider of the
+// validator. The top-up target has to be above the balance threshold
+func (v *Validator) NewFunder(
+	ctx context.Context,
+	topUpConfig *config.TopUp,
+	balanceThreshold float64,
+	snConfig *config.StarknetConfig,
+) (*Funder, error) {
+	if topUpConfig.Target <= balanceThreshold {
+		return nil, fmt.Errorf(
+			"top-up target %f must be above the balance threshold %f",
+			topUpConfig.Target,
+			balanceThreshold,
+		)
+	}
+	signer, err := signerP.New(
+		ctx, v.provider, &v.logger, &topUpConfig.Funder, &snConfig.ContractAddresses, false,
+	)
+	if err != nil {
+		return nil, err
+	}
+	invoker, ok := signer.(signerP.Invoker)
+	if !ok {
+		return nil, errors.New("the top-up funder can only sign attestations")
+	}
+
+	//nolint:exhaustruct // The sent top-ups are read from the state file
+	funder := &Funder{
+		Signer:         invoker,
+		Target:         topUpConfig.Target,
+		DailyCap:       topUpConfig.DailyCap,
+		DailyTransfers: topUpConfig.DailyTransfers,
+		StateFile:      topUpConfig.StateFile,
+	}
+	if err := funder.LoadState(); err != nil {
+		return nil, err
+	}
+
+	return funder, nil
+}
+
+// Reads the top-ups sent before from the state file. There are none if it doesn't exist
+func (f *Funder) LoadState() error {
+	if f.StateFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(f.StateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot read the top-up state file: %w", err)
+	}
+
+	var state []topUpState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("cannot parse the top-up state file %s: %w", f.StateFile, err)
+	}
+	sent := make([]topUp, 0, len(state))
+	for i := range state {
+		amount, ok := new(big.Int).SetString(state[i].Amount, 10) //nolint:mnd // Decimal base
+		if !ok {
+			return fmt.Errorf(
+				"invalid top-up amount %q in the state file %s", state[i].Amount, f.StateFile,
+			)
+		}
+		sent = append(sent, topUp{sentAt: state[i].SentAt, amount: types.Balance(*amount)})
+	}
+	f.sent = sent
+
+	return nil
+}
+
+// Writes the sent top-ups to the state file. Written aside first, so the file is never
+// left half written
+func (f *Funder) saveState() error {
+	if f.StateFile == "" {
+		return nil
+	}
+	state := make([]topUpState, 0, len(f.sent))
+	for i := range f.sent {
+		state = append(state, topUpState{
+			SentAt: f.sent[i].sentAt,
+			Amount: f.sent[i].amount.Text(10), //nolint:mnd // Decimal base
+		})
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := f.StateFile + ".tmp"
+	//nolint:mnd // Only readable by the validator
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("cannot write the top-up state file: %w", err)
+	}
+	if err := os.Rename(tmpPath, f.StateFile); err != nil {
+		return fmt.Errorf("cannot write the top-up state file: %w", err)
+	}
+
+	return nil
+}
+
+// Returns how much can still be sent within the daily caps, or false when no top-up can
+// be sent until older ones are 24 hours old
+func (f *Funder) allowance(now time.Time) (*big.Int, bool) {
+	expired := 0
+	for expired < len(f.sent) && now.Sub(f.sent[expired].sentAt) >= topUpCapPeriod {
+		expired++
+	}
+	f.sent = f.sent[expired:]
+
+	if f.DailyTransfers != 0 && uint64(len(f.sent)) >= f.DailyTransfers {
+		return nil, false
+	}
+	dailyCap := types.BalanceFromStrk(f.DailyCap)
+	remaining := (*big.Int)(&dailyCap)
+	for i := range f.sent {
+		remaining.Sub(remaining, (*big.Int)(&f.sent[i].amount))
+	}
+
+	return remaining, remaining.Sign() > 0
+}
+
+// Sends STRK from the funding account to the operational account of the signer, whose
+// balance is given, so it reaches the target of the funder. The amount is limited by the
+// daily caps, and the top-up is tracked until it is accepted or the context is done
+func TopUp[S signerP.Signer](
+	ctx context.Context,
+	signer S,
+	balance *types.Balance,
+	funder *Funder,
+	logger *junoUtils.ZapLogger,
+	tracer metrics.Tracer,
+) {
+	if !funder.funding.CompareAndSwap(false, true) {
+		logger.Debug("a top-up of the operational account is already ongoing")
+
+		return
+	}
+	defer funder.funding.Store(false)
+
+	target := types.BalanceFromStrk(funder.Target)
+	amount := new(big.Int).Sub((*big.Int)(&target), (*big.Int)(balance))
+	if amount.Sign() <= 0 {
+		return
+	}
+	remaining, ok := funder.allowance(time.Now())
+	if !ok {
+		logger.Warnw(
+			"Top-up of the operational account skipped, its daily caps are reached",
+			"address", signer.Address(),
+			"daily cap STRK", funder.DailyCap,
+			"daily transfers", funder.DailyTransfers,
+		)
+		tracer.RecordTopUpCapped()
+
+		return
+	}
+	if amount.Cmp(remaining) > 0 {
+		logger.Warnw(
+			"Top-up of the operational account limited by its daily cap",
+			"address", signer.Address(),
+			"daily cap STRK", funder.DailyCap,
+		)
+		amount = remaining
+	}
+	topUpAmount := types.Balance(*amount)
+
+	funds, err := signerP.FetchValidatorBalance(funder.Signer)
+	if err != nil {
+		logger.Errorw(
+			"cannot get the STRK balance of the funding account",
+			"funding account", funder.Signer.Address(),
+			"error", err.Error(),
+		)
+		tracer.RecordTopUpFailure()
+
+		return
+	}
+	if (*big.Int)(&funds).Cmp(amount) < 0 {
+		logger.Errorw(
+			"the funding account balance is too low to top up the operational account",
+			"funding account", funder.Signer.Address(),
+			"balance STRK", funds.Strk(),
+			"top-up STRK", topUpAmount.Strk(),
+		)
+		tracer.RecordTopUpFailure()
+
+		return
+	}
+
+	logger.Infow(
+		"Topping up the operational account",
+		"address", signer.Address(),
+		"funding account", funder.Signer.Address(),
+		"STRK", topUpAmount.Strk(),
+		"target STRK", funder.Target,
+	)
+	// Counted towards the daily caps before being sent, so a restart while sending can't
+	// exceed them. It is kept even if sending fails, as it may have reached the provider
+	funder.sent = append(funder.sent, topUp{sentAt: time.Now(), amount: topUpAmount})
+	if err := funder.saveState(); err != nil {
+		funder.sent = funder.sent[:len(funder.sent)-1]
+		logger.Errorw(
+			"failed to record the top-up of the operational account", "error", err.Error(),
+		)
+		tracer.RecordTopUpFailure()
+
+		return
+	}
+
+	strkContract := types.AddressFromString(constants.StrkContractAddress)
+	low, high := topUpAmount.U256()
+	txHash, err := sendInvoke(funder.Signer, []rpc.InvokeFunctionCall{{
+		ContractAddress: strkContract.Felt(),
+		FunctionName:    "transfer",
+		CallData:        []*felt.Felt{signer.Address().Felt(), low, high},
+	}}, logger)
+	if err != nil {
+		logger.Errorw("failed to send the top-up of the operational account", "error", err.Error())
+		tracer.RecordTopUpFailure()
+
+		return
+	}
+	tracer.RecordTopUpSubmitted()
+
+	err = waitForAcceptance(
+		ctx, funder.Signer.TransactionStatus, txHash, topUpAcceptanceTimeout, logger,
+	)
+	if err != nil {
+		logger.Errorw(
+			"top-up of the operational account failed",
+			"transaction hash", txHash,
+			"error", err.Error(),
+		)
+		tracer.RecordTopUpFailure()
+
+		return
+	}
+	logger.Infow(
+		"Operational account topped up",
+		"address", signer.Address(),
+		"STRK", topUpAmount.Strk(),
+		"transaction hash", txHash,
+	)
+	tracer.RecordTopUpConfirmed(topUpAmount.Strk())
+}
@@ -0,0 +1,214 @@
+package validator_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/starknet-staking-v2/mocks"
+	"github.com/NethermindEth/starknet-staking-v2/validator"
+	"github.com/NethermindEth/starknet-staking-v2/validator/constants"
+	"github.com/NethermindEth/starknet-staking-v2/validator/metrics"
+	"github.com/NethermindEth/starknet-staking-v2/validator/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	snUtils "github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// Records the top-ups reported to the tracer
+type topUpTracer struct {
+	metrics.NoOpMetrics
+
+	submitted int
+	confirmed []float64
+	failures  int
+	capped    int
+}
+
+func (t *topUpTracer) RecordTopUpSubmitted() {
+	t.submitted++
+}
+
+func (t *topUpTracer) RecordTopUpConfirmed(amount float64) {
+	t.confirmed = append(t.confirmed, amount)
+}
+
+func (t *topUpTracer) RecordTopUpFailure() {
+	t.failures++
+}
+
+func (t *topUpTracer) RecordTopUpCapped() {
+	t.capped++
+}
+
+func TestTopUp(t *testing.T) {
+	logger := utils.NewNopZapLogger()
+	validator.After = func(time.Duration) <-chan time.Time { return time.After(0) }
+	t.Cleanup(func() { validator.After = time.After })
+
+	operationalAddress := types.AddressFromString("0x123")
+	funderAddress := types.AddressFromString("0x456")
+	strkContract := utils.HexToFelt(t, constants.StrkContractAddress)
+	txHash := utils.HexToFelt(t, "0xabc")
+
+	newSigner := func(t *testing.T) *mocks.MockSigner {
+		t.Helper()
+
+		mockCtrl := gomock.NewController(t)
+		signer := mocks.NewMockSigner(mockCtrl)
+		signer.EXPECT().Address().Return(&operationalAddress).AnyTimes()
+
+		return signer
+	}
+
+	// Creates a funder whose account holds the funds, in STRK
+	newFunder := func(t *testing.T, funds uint64, dailyCap float64) *validator.Funder {
+		t.Helper()
+
+		mockCtrl := gomock.NewController(t)
+		invoker := mocks.NewMockInvoker(mockCtrl)
+		invoker.EXPECT().Address().Return(&funderAddress).AnyTimes()
+		fundsBalance := types.BalanceFromStrk(float64(funds))
+		low, high := fundsBalance.U256()
+		invoker.EXPECT().
+			Call(rpc.FunctionCall{
+				ContractAddress:    strkContract,
+				EntryPointSelector: snUtils.GetSelectorFromNameFelt("balance_of"),
+				Calldata:           []*felt.Felt{funderAddress.Felt()},
+			}, rpc.WithBlockTag(rpc.BlockTagLatest)).
+			Return([]*felt.Felt{low, high}, nil).
+			AnyTimes()
+
+		//nolint:exhaustruct // No top-up has been sent yet
+		return &validator.Funder{Signer: invoker, Target: 100, DailyCap: dailyCap}
+	}
+
+	// Expects a transfer of the amount, in STRK, to the operational account
+	expectTransfer := func(funder *validator.Funder, amount float64) {
+		invoker := funder.Signer.(*mocks.MockInvoker)
+		amountBalance := types.BalanceFromStrk(amount)
+		low, high := amountBalance.U256()
+		invoker.EXPECT().
+			BuildInvokeTransaction([]rpc.InvokeFunctionCall{{
+				ContractAddress: strkContract,
+				FunctionName:    "transfer",
+				CallData:        []*felt.Felt{operationalAddress.Felt(), low, high},
+			}}).
+			Return(rpc.BroadcastInvokeTxnV3{Version: rpc.TransactionV3}, nil)
+		invoker.EXPECT().SignInvokeTransaction(gomock.Any()).Times(2)
+		invoker.EXPECT().EstimateFee(gomock.Any()).Return(rpc.FeeEstimation{
+			FeeEstimationCommon: rpc.FeeEstimationCommon{
+				L1GasConsumed:     new(felt.Felt),
+				L1GasPrice:        new(felt.Felt),
+				L2GasConsumed:     new(felt.Felt).SetUint64(0x1000),
+				L2GasPrice:        new(felt.Felt).SetUint64(0x10),
+				L1DataGasConsumed: new(felt.Felt),
+				L1DataGasPrice:    new(felt.Felt),
+			},
+		}, nil)
+		invoker.EXPECT().
+			InvokeTransaction(gomock.Any()).
+			Return(rpc.AddInvokeTransactionResponse{Hash: txHash}, nil)
+		invoker.EXPECT().TransactionStatus(txHash).Return(&rpc.TxnStatusResult{
+			FinalityStatus:  rpc.TxnStatusAcceptedOnL2,
+			ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+		}, nil)
+	}
+
+	t.Run("Balance topped up to the target", func(t *testing.T) {
+		funder := newFunder(t, 1000, 500)
+		expectTransfer(funder, 80)
+		tracer := &topUpTracer{}
+		balance := types.BalanceFromStrk(20)
+
+		validator.TopUp(t.Context(), newSigner(t), &balance, funder, logger, tracer)
+
+		require.Equal(t, 1, tracer.submitted)
+		require.Equal(t, []float64{80}, tracer.confirmed)
+		require.Zero(t, tracer.failures)
+	})
+
+	t.Run("Top-up limited by the daily cap", func(t *testing.T) {
+		funder := newFunder(t, 1000, 50)
+		expectTransfer(funder, 50)
+		tracer := &topUpTracer{}
+		balance := types.BalanceFromStrk(20)
+
+		validator.TopUp(t.Context(), newSigner(t), &balance, funder, logger, tracer)
+		require.Equal(t, []float64{50}, tracer.confirmed)
+
+		// The cap is used up for the next 24 hours
+		validator.TopUp(t.Context(), newSigner(t), &balance, funder, logger, tracer)
+		require.Equal(t, 1, tracer.submitted)
+		require.Equal(t, 1, tracer.capped)
+	})
+
+	t.Run("Daily cap kept across restarts", func(t *testing.T) {
+		stateFile := filepath.Join(t.TempDir(), "top-ups.json")
+		funder := newFunder(t, 1000, 50)
+		funder.StateFile = stateFile
+		expectTransfer(funder, 50)
+		tracer := &topUpTracer{}
+		balance := types.BalanceFromStrk(20)
+
+		validator.TopUp(t.Context(), newSigner(t), &balance, funder, logger, tracer)
+		require.Equal(t, []float64{50}, tracer.confirmed)
+
+		// A new funder reads the top-up sent by the previous one
+		restarted := newFunder(t, 1000, 50)
+		restarted.StateFile = stateFile
+		require.NoError(t, restarted.LoadState())
+		validator.TopUp(t.Context(), newSigner(t), &balance, restarted, logger, tracer)
+		require.Equal(t, 1, tracer.submitted)
+		require.Equal(t, 1, tracer.capped)
+	})
+
+	t.Run("Invalid state file", func(t *testing.T) {
+		stateFile := filepath.Join(t.TempDir(), "top-ups.json")
+		require.NoError(t, os.WriteFile(stateFile, []byte(`[{"amount":"50 STRK"}]`), 0o600))
+		funder := newFunder(t, 1000, 50)
+		funder.StateFile = stateFile
+
+		require.ErrorContains(t, funder.LoadState(), `invalid top-up amount "50 STRK"`)
+	})
+
+	t.Run("Top-ups limited by the daily transfers", func(t *testing.T) {
+		funder := newFunder(t, 1000, 500)
+		funder.DailyTransfers = 1
+		expectTransfer(funder, 10)
+		tracer := &topUpTracer{}
+		balance := types.BalanceFromStrk(90)
+
+		validator.TopUp(t.Context(), newSigner(t), &balance, funder, logger, tracer)
+		validator.TopUp(t.Context(), newSigner(t), &balance, funder, logger, tracer)
+
+		require.Equal(t, 1, tracer.submitted)
+		require.Equal(t, 1, tracer.capped)
+	})
+
+	t.Run("Funding account without enough funds", func(t *testing.T) {
+		funder := newFunder(t, 10, 500)
+		tracer := &topUpTracer{}
+		balance := types.BalanceFromStrk(20)
+
+		validator.TopUp(t.Context(), newSigner(t), &balance, funder, logger, tracer)
+
+		require.Zero(t, tracer.submitted)
+		require.Equal(t, 1, tracer.failures)
+	})
+
+	t.Run("Balance already at the target", func(t *testing.T) {
+		funder := newFunder(t, 1000, 500)
+		tracer := &topUpTracer{}
+		balance := types.BalanceFromStrk(100)
+
+		validator.TopUp(t.Context(), newSigner(t), &balance, funder, logger, tracer)
+
+		require.Zero(t, tracer.submitted)
+		require.Zero(t, tracer.failures)
+	})
+}
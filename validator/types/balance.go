@@ -38,3 +38,25 @@ func (b *Balance) Strk() float64 {
 
 	return f
 }
+
+// Returns the balance (represented in FRI) of an amount in STRK units
+func BalanceFromStrk(strk float64) Balance {
+	FRIUnit := new(big.Float).SetUint64(1e18) //nolint:mnd // 1e18 is the FRI unit
+	bigF := new(big.Float).SetFloat64(strk)
+	bigF = bigF.Mul(bigF, FRIUnit)
+
+	fri, _ := bigF.Int(nil)
+
+	return Balance(*fri)
+}
+
+// Splits the balance into the low and high 128 bits of its u256 calldata
+func (b *Balance) U256() (low, high *felt.Felt) {
+	bigI := (*big.Int)(b)
+	mask := new(big.Int).Lsh(big.NewInt(1), 128) //nolint:mnd // Shifting by 128 bits
+	mask = mask.Sub(mask, big.NewInt(1))
+	lowB := new(big.Int).And(bigI, mask)
+	highB := new(big.Int).Rsh(bigI, 128) //nolint:mnd // Shifting by 128 bits
+
+	return new(felt.Felt).SetBigInt(lowB), new(felt.Felt).SetBigInt(highB)
+}
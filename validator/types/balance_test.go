@@ -0,0 +1,35 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet-staking-v2/validator/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceFromStrk(t *testing.T) {
+	balance := types.BalanceFromStrk(1.5)
+	require.Equal(t, "1500000000000000000", balance.Text(10))
+	require.Equal(t, 1.5, balance.Strk())
+
+	balance = types.BalanceFromStrk(0)
+	require.Equal(t, "0", balance.Text(10))
+}
+
+func TestBalanceU256(t *testing.T) {
+	low := new(felt.Felt).SetUint64(0x123)
+	high := new(felt.Felt).SetUint64(0x456)
+	balance := types.NewBalance(low, high)
+
+	gotLow, gotHigh := balance.U256()
+	require.Equal(t, low, gotLow)
+	require.Equal(t, high, gotHigh)
+
+	balance = types.BalanceFromStrk(100)
+	gotLow, gotHigh = balance.U256()
+	expectedLow, err := new(felt.Felt).SetString("0x56bc75e2d63100000")
+	require.NoError(t, err)
+	require.Equal(t, expectedLow, gotLow)
+	require.True(t, gotHigh.IsZero())
+}
@@ -11,11 +11,15 @@ import (
 // Represents an event for the dispatcher to prepare for the next attest
 type PrepareAttest struct {
 	BlockHash BlockHash
+	// Epoch the attestation is for
+	EpochID uint64
 }
 
 // Represents an event for the dispatcher to invoke an attest transaction
 type DoAttest struct {
 	BlockHash BlockHash
+	// Epoch the attestation is for
+	EpochID uint64
 }
 
 // Used by the validator to keep track of the starknet attestation window
@@ -44,6 +48,25 @@ func (e *EpochInfo) String() string {
 	return string(jsonData)
 }
 
+// Staker state kept by the staking contract
+type StakerInfo struct {
+	RewardAddress      Address
+	OperationalAddress Address
+	// Own stake of the staker, without the delegated one
+	Stake            Balance
+	UnclaimedRewards Balance
+	// Delegation pool of the staker, nil if it has none
+	Pool *PoolInfo
+}
+
+type PoolInfo struct {
+	Contract Address
+	// Stake delegated to the staker through the pool
+	Amount Balance
+	// Share of the pool rewards the staker keeps, in hundredths of a percent
+	Commission uint64
+}
+
 type ValidationContracts struct {
 	Staking Address
 	Attest  Address
@@ -1,12 +1,24 @@
 package validator
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/NethermindEth/juno/utils"
 	"github.com/NethermindEth/starknet-staking-v2/validator/types"
 )
 
+// Waits for the duration, returning the context error if it is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-After(d):
+		return nil
+	}
+}
+
 func logNewEpoch(
 	epochInfo *types.EpochInfo,
 	attestInfo *types.AttestInfo,
@@ -35,47 +35,23 @@ func New(
 	logger utils.ZapLogger,
 	braavos bool,
 ) (Validator, error) {
-	provider, err := NewProvider(ctx, conf.Provider.HTTP, &logger)
+	provider, err := NewProvider(ctx, conf.Provider.HTTP.Reveal(), &logger)
 	if err != nil {
 		return Validator{}, fmt.Errorf("failed to connect to provider: %w", err)
 	}
 
-	var signer signerP.Signer
-	if conf.Signer.External() {
-		externalSigner, err := signerP.NewExternalSigner(
-			ctx,
-			provider,
-			&logger,
-			&conf.Signer,
-			&snConfig.ContractAddresses,
-			braavos,
-		)
-		if err != nil {
-			return Validator{}, fmt.Errorf("failed to connect to external signer: %w", err)
-		}
-		signer = &externalSigner
-		logger.Infof("using external signer at %s", conf.Signer.ExternalURL)
-	} else {
-		internalSigner, err := signerP.NewInternalSigner(
-			ctx,
-			provider,
-			&logger,
-			&conf.Signer,
-			&snConfig.ContractAddresses,
-			braavos,
-		)
-		if err != nil {
-			return Validator{}, fmt.Errorf("failed to initialise internal signer: %w", err)
-		}
-		signer = &internalSigner
-		logger.Info("using internal signer")
+	signer, err := signerP.New(
+		ctx, provider, &logger, &conf.Signer, &snConfig.ContractAddresses, braavos,
+	)
+	if err != nil {
+		return Validator{}, err
 	}
 
 	return Validator{
 		provider:   provider,
 		signer:     signer,
 		logger:     logger,
-		wsProvider: conf.Provider.WS,
+		wsProvider: conf.Provider.WS.Reveal(),
 	}, nil
 }
 
@@ -92,25 +68,36 @@ func (v *Validator) ChainID(ctx context.Context) string {
 }
 
 // Main execution loop of the program. Listens to the blockchain and sends
-// attest invoke when it's the right time
+// attest invoke when it's the right time. The staker rewards are checked at each epoch,
+// and claimed by the claimer if it is set. The funder, if set, tops up the account once
+// its balance falls below the threshold
 func (v *Validator) Attest(
-	ctx context.Context, maxRetries types.Retries, balanceThreshold float64, tracer metrics.Tracer,
+	ctx context.Context,
+	maxRetries types.Retries,
+	balanceThreshold float64,
+	funder *Funder,
+	claimer *RewardsClaimer,
+	tracer metrics.Tracer,
 ) error {
+	if traced, ok := v.signer.(signerP.Traced); ok {
+		traced.SetTracer(tracer)
+	}
+
 	// Initial check of the account balance
-	go CheckBalance(v.signer, balanceThreshold, &v.logger, tracer)
+	go CheckBalance(ctx, v.signer, balanceThreshold, funder, &v.logger, tracer)
 
 	// Create the event dispatcher
 	dispatcher := NewEventDispatcher[signerP.Signer]()
 	wg := conc.NewWaitGroup()
 	wg.Go(func() {
-		dispatcher.Dispatch(v.signer, balanceThreshold, &v.logger, tracer)
+		dispatcher.Dispatch(ctx, v.signer, balanceThreshold, funder, &v.logger, tracer)
 		v.logger.Debug("Dispatch method finished")
 	})
 	defer wg.Wait()
 	defer close(dispatcher.PrepareAttest)
 
 	return RunBlockHeaderWatcher(
-		ctx, v.wsProvider, &v.logger, v.signer, &dispatcher, maxRetries, wg, tracer,
+		ctx, v.wsProvider, &v.logger, v.signer, &dispatcher, maxRetries, claimer, wg, tracer,
 	)
 }
 
@@ -121,6 +108,7 @@ func RunBlockHeaderWatcher[S signerP.Signer](
 	signer S,
 	dispatcher *EventDispatcher[S],
 	maxRetries types.Retries,
+	claimer *RewardsClaimer,
 	wg *conc.WaitGroup,
 	tracer metrics.Tracer,
 ) error {
@@ -156,6 +144,7 @@ func RunBlockHeaderWatcher[S signerP.Signer](
 				logger,
 				dispatcher,
 				maxRetries,
+				claimer,
 				tracer,
 			)
 			if err != nil {
@@ -194,8 +183,13 @@ func ProcessBlockHeaders[Account signerP.Signer](
 	logger *utils.ZapLogger,
 	dispatcher *EventDispatcher[Account],
 	maxRetries types.Retries,
+	claimer *RewardsClaimer,
 	tracer metrics.Tracer,
 ) error {
+	checkRewards := func(staker types.Address) {
+		go CheckRewards(ctx, account, &staker, claimer, logger, tracer)
+	}
+
 	noEpochSwitch := func(*types.EpochInfo, *types.EpochInfo) bool { return true }
 	epochInfo, attestInfo, err := FetchEpochAndAttestInfoWithRetry(
 		account, logger, nil, noEpochSwitch, maxRetries, "at app startup",
@@ -208,6 +202,7 @@ func ProcessBlockHeaders[Account signerP.Signer](
 
 	logNewEpoch(&epochInfo, &attestInfo, logger)
 	tracer.UpdateEpochInfo(&epochInfo, attestInfo.TargetBlock.Uint64())
+	checkRewards(epochInfo.StakerAddress)
 
 	for block := range headersFeed {
 		logBlock(block.Number, &epochInfo, &attestInfo, logger)
@@ -230,6 +225,7 @@ func ProcessBlockHeaders[Account signerP.Signer](
 			logNewEpoch(&epochInfo, &attestInfo, logger)
 			// Update epoch info metrics
 			tracer.UpdateEpochInfo(&epochInfo, attestInfo.TargetBlock.Uint64())
+			checkRewards(epochInfo.StakerAddress)
 		}
 		if uint64(attestInfo.TargetBlock) == block.Number {
 			attestInfo.TargetBlockHash = types.BlockHash(*block.Hash)
@@ -239,6 +235,7 @@ func ProcessBlockHeaders[Account signerP.Signer](
 			)
 			dispatcher.PrepareAttest <- types.PrepareAttest{
 				BlockHash: attestInfo.TargetBlockHash,
+				EpochID:   epochInfo.EpochID,
 			}
 		}
 
@@ -249,12 +246,14 @@ func ProcessBlockHeaders[Account signerP.Signer](
 			blockNum < attestInfo.WindowStart-1:
 			dispatcher.PrepareAttest <- types.PrepareAttest{
 				BlockHash: attestInfo.TargetBlockHash,
+				EpochID:   epochInfo.EpochID,
 			}
 		case blockNum >= attestInfo.WindowStart-1 &&
 			// from [window start, window end), make sure the attestation is done
 			blockNum < attestInfo.WindowEnd:
 			dispatcher.DoAttest <- types.DoAttest{
 				BlockHash: attestInfo.TargetBlockHash,
+				EpochID:   epochInfo.EpochID,
 			}
 		case blockNum == attestInfo.WindowEnd:
 			dispatcher.EndOfWindow <- struct{}{}